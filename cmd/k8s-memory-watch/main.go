@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
 	"github.com/eduardoferro/k8s-memory-watch/internal/monitor"
+	"golang.org/x/term"
 )
 
 // Version information (set during build with ldflags)
@@ -25,25 +30,126 @@ var (
 // Global variable to track if CSV header has been printed
 var csvHeaderPrinted = false
 
+// lastReport is when the report was last printed, used to honor --report-interval
+var lastReport time.Time
+
+// healthStatus records each cycle for the --health-addr probes, nil when they are disabled
+var healthStatus *monitor.HealthStatus
+
+// stringListFlag collects the values of a flag that may be repeated
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 func main() {
 	// Parse command line flags
 	var (
-		namespace       = flag.String("namespace", "", "Monitor specific namespace (default: all namespaces)")
-		allNamespaces   = flag.Bool("all-namespaces", false, "Monitor all namespaces explicitly")
-		kubeconfig      = flag.String("kubeconfig", "", "Path to kubeconfig file")
-		inCluster       = flag.Bool("in-cluster", false, "Use in-cluster configuration")
-		checkInterval   = flag.Duration("check-interval", 0, "Check interval (e.g., 30s, 1m)")
-		memoryThreshold = flag.Int64("memory-threshold", 0, "Memory threshold in MB")
-		memoryWarning   = flag.Float64("memory-warning", 0, "Memory warning percentage")
-		watch           = flag.Bool("watch", false, "Enable continuous monitoring (default: single check)")
-		logLevel        = flag.String("log-level", "", "Log level (debug, info, warn, error)")
-		labels          = flag.String("labels", "", "Comma-separated list of labels to display (e.g., dag_id,task_id,run_id)")
-		annotations     = flag.String("annotations", "", "Comma-separated list of annotations to display")
-		output          = flag.String("output", "table", "Output format (table, csv)")
-		version         = flag.Bool("version", false, "Show version information")
-		help            = flag.Bool("help", false, "Show help message")
+		namespace         = flag.String("namespace", "", "Monitor specific namespace (default: all namespaces)")
+		allNamespaces     = flag.Bool("all-namespaces", false, "Monitor all namespaces explicitly")
+		podName           = flag.String("pod", "", "Monitor a single pod by name (requires --namespace)")
+		nodeName          = flag.String("node", "", "Monitor only pods scheduled on this node, across all namespaces")
+		labelSelector     = flag.String("label-selector", "", "Monitor only pods matching this label selector (e.g., app=web,tier!=batch); pods and metrics are filtered server-side")
+		kubeconfig        = flag.String("kubeconfig", "", "Path to kubeconfig file, or a directory whose files are merged")
+		inCluster         = flag.Bool("in-cluster", false, "Use in-cluster configuration")
+		contexts          = flag.String("contexts", "", "Comma-separated kubeconfig contexts to collect from and merge into one report (e.g., prod,staging)")
+		metricsAPIGroup   = flag.String("metrics-api-group", "", "API group serving pod metrics (default metrics.k8s.io)")
+		noMetrics         = flag.Bool("no-metrics", false, "Skip pod metrics and report requests and limits only (e.g., without metrics-server)")
+		checkInterval     = flag.Duration("check-interval", 0, "Check interval (e.g., 30s, 1m)")
+		intervalJitter    = flag.Duration("interval-jitter", 0, "Add a random delay up to this value to each check interval (e.g., 10s)")
+		reportInterval    = flag.Duration("report-interval", 0, "With --watch, print the report at most this often while still collecting every check interval (e.g., 5m)")
+		refreshMetrics    = flag.Bool("refresh-metrics-only", false, "With --watch, reuse pod specs from a recent cycle and only list metrics again")
+		podCacheTTL       = flag.Duration("pod-cache-ttl", 0, "With --refresh-metrics-only, list pods again once cached specs are this old, at most 15m (default: 5m)")
+		samples           = flag.Int("samples", 0, "Read metrics this many times per cycle and average each container's usage (default: 1)")
+		sampleInterval    = flag.Duration("sample-interval", 0, "With --samples, wait this long between metrics reads (default: 5s)")
+		maxMetricsAge     = flag.Duration("max-metrics-age", 0, "Flag usage samples older than this and downgrade their problems to warnings (e.g., 2m)")
+		memoryThreshold   = flag.String("memory-threshold", "", "Flag pods using more memory than this, whatever their limits (e.g. 2Gi; bare numbers are MB) (default: disabled)")
+		memoryWarning     = flag.Float64("memory-warning", 0, "Memory warning percentage")
+		primaryMetric     = flag.String("primary-metric", "", "Percentage the warning threshold applies to: usage vs request or vs limit (default: request)")
+		maxRetries        = flag.Int("max-retries", -1, "Retries for transient API errors, 0 to disable retrying (default: 2)")
+		retryBackoff      = flag.Duration("retry-backoff", 0, "Initial backoff between retries, doubled each attempt (default: 500ms)")
+		listPageSize      = flag.Int64("list-page-size", 0, "Pods requested per API list call (default: 500)")
+		strict            = flag.Bool("strict", false, "Fail the check when any namespace cannot be listed instead of reporting the others (non-zero exit without --watch)")
+		maxNamespaces     = flag.Int("max-namespaces", 0, "Stop an all-namespaces collection after this many namespaces, marking the report truncated (default: no limit)")
+		watch             = flag.Bool("watch", false, "Enable continuous monitoring (default: single check)")
+		namespaceEvents   = flag.Bool("watch-namespace-events", false, "With --watch, log pod_added/pod_removed events for pods appearing or disappearing between cycles")
+		crossings         = flag.Bool("watch-threshold-crossings", false, "With --watch, report pods whose status changes between ok, warning and critical")
+		changedOnly       = flag.Bool("watch-pods-changed-only", false, "With --watch, list only pods whose status or usage changed since the previous cycle")
+		logLevel          = flag.String("log-level", "", "Log level (debug, info, warn, error)")
+		logFormat         = flag.String("log-format", "", "Log format (json, text)")
+		labels            = flag.String("labels", "", "Comma-separated list of labels to display (e.g., dag_id,task_id,run_id)")
+		annotations       = flag.String("annotations", "", "Comma-separated list of annotations to display; key:$.path shows one field of a JSON value")
+		annotationsLabels = flag.String("annotations-as-labels", "", "Comma-separated annotations treated as labels for --labels, --group-by-label and --exclude-label; a label with the same key wins")
+		envVars           = flag.String("env-vars", "", "Comma-separated container environment variables to display, literal values only (e.g., DAG_ID,TASK_ID)")
+		symbolOK          = flag.String("symbol-ok", "", "Status symbol for running, ready pods (default: 🟢)")
+		symbolWarning     = flag.String("symbol-warning", "", "Status symbol for pending pods (default: 🟡)")
+		symbolCritical    = flag.String("symbol-critical", "", "Status symbol for failed or not ready pods (default: 🔴)")
+		symbolNoData      = flag.String("symbol-nodata", "", "Status symbol for pods without usage metrics (default: ⚪)")
+		symbolCompleted   = flag.String("symbol-completed", "", "Status symbol for pods that ran to completion, such as finished Jobs (default: 🔵)")
+		expectedMemory    = flag.String("expected-memory-annotation", "", "Pod annotation holding the expected memory (e.g., team.io/expected-memory), shown against actual usage")
+		containers        = flag.String("container", "", "Comma-separated list of container names to report (e.g., app)")
+		excludeContainers = flag.String("exclude-container", "", "Comma-separated list of container names to skip (e.g., istio-proxy)")
+		systemContainers  = flag.String("system-containers", "", "Comma-separated name patterns of pause/sandbox containers reported by some metrics setups (default: POD)")
+		includeSystem     = flag.Bool("include-system-containers", false, "Report system containers and count them in pod usage (default: skipped)")
+		includePhases     = flag.String("include-phases", "", "Comma-separated pod phases to show (default: Running,Pending)")
+		output            = flag.String("output", "table", "Output format (table, table-wide, csv, json)")
+		problemsOnly      = flag.Bool("problems-only", false, "With --output=json, emit only detected problems, one JSON object per line")
+		onlyProblems      = flag.Bool("only-problems", false, "With table output, list only pods with a memory problem; the summary still covers every pod")
+		summaryOnly       = flag.Bool("summary-only", false, "With --output=json, emit only the cluster totals and risk counts each cycle")
+		maxProblems       = flag.Int("max-problems", 0, "List at most this many problems, critical ones first, followed by a count of the rest (default: no limit)")
+		dedupeProblems    = flag.Bool("dedupe-problems", false, "Collapse identical problems of a workload's replicas into one entry with a pod count")
+		sortBy            = flag.String("sort-by", "", "Pod ordering (name, headroom) (default: name)")
+		sortOrder         = flag.String("sort-order", "", "Sort direction (asc, desc) (default: asc, i.e. by name or least headroom first)")
+		sortContainers    = flag.Bool("sort-containers", false, "List each pod's containers by memory usage, largest first (default: by name)")
+		groupBy           = flag.String("group-by", "", "Print an aggregated report (node: usage, requests and limits per node vs allocatable)")
+		groupByLabel      = flag.String("group-by-label", "", "Print usage, requests and limits summed per value of this pod label (e.g., team)")
+		color             = flag.String("color", "", "Colorize table output (auto, always, never) (default: auto)")
+		statusOnly        = flag.Bool("watch-status-only", false, "Refresh a compact count-only view in place (terminal only)")
+		summaryOnExit     = flag.Bool("summary-on-exit", false, "With --watch, print peak usage, critical events and cycles run on shutdown")
+		requestRatio      = flag.Bool("request-as-percent-of-limit", false, "Show each container's request as a percent of its limit (and a CSV column)")
+		containerStats    = flag.Bool("container-stats", false, "Show each pod's max, mean and p95 container usage to spot outlier containers")
+		compactPods       = flag.Bool("compact-pods", false, "Print one line per pod, without the container breakdown or metadata")
+		explain           = flag.Bool("explain", false, "Append the rule that decided each pod's memory status to its line, e.g. (critical: limit_usage 92.0% ≥ 90%)")
+		showImages        = flag.Bool("show-images", false, "Display container images (and add an image CSV column)")
+		suggestRequests   = flag.Bool("suggest-requests", false, "Suggest memory requests from observed usage in the recommendations")
+		suggestFactor     = flag.Float64("suggest-headroom-factor", 0, "Multiplier applied to usage when suggesting requests (default: 1.2)")
+		suggestRoundTo    = flag.String("suggest-round-to", "", "Round suggested requests up to a multiple of this quantity (default: 32Mi)")
+		compareRequests   = flag.Bool("compare-requests", false, "Print over- and under-provisioned pods by usage/request")
+		csvCycle          = flag.Bool("csv-cycle", false, "End each CSV row with a cycle column numbering the checks of this run")
+		csvTotals         = flag.Bool("csv-totals", false, "With --output=csv, end the report with a row of total usage, request and limit bytes (single runs only)")
+		csvAppend         = flag.String("csv-append", "", "Append each cycle's CSV rows to this file, writing the header only when it is new or empty")
+		outputFile        = flag.String("output-file", "", "Write CSV or JSON reports to this file instead of stdout")
+		rotateSize        = flag.String("rotate-size", "", "Rotate --output-file once it reaches this size (e.g. 100MB)")
+		rotateInterval    = flag.Duration("rotate-interval", 0, "Rotate --output-file once it is this old (e.g. 1h)")
+		rightSizingLow    = flag.Float64("rightsizing-low", 0, "Usage/request percentage below which a pod is over-provisioned (default: 50)")
+		rightSizingHigh   = flag.Float64("rightsizing-high", 0, "Usage/request percentage above which a pod is under-provisioned (default: 90)")
+		efficiency        = flag.Bool("efficiency", false, "Print per-namespace memory request efficiency (usage/request)")
+		percentPrecision  = flag.Int("percent-precision", -1, "Decimals shown for percentages in table output (default: 1)")
+		units             = flag.String("units", "", "Memory units for display: binary (KiB/MiB/GiB) or decimal (KB/MB/GB, 1000-based)")
+		timestampFormat   = flag.String("timestamp-format", "", "Timestamp format for CSV and table output: rfc3339, epoch or epochmillis (default: rfc3339)")
+		slackWebhook      = flag.String("slack-webhook", "", "Slack incoming webhook URL notified when pods become critical")
+		webhookURL        = flag.String("webhook-url", "", "POST each report, in the --output=json format, to this URL")
+		requestTimeout    = flag.Duration("request-timeout", 0, "Timeout for outgoing webhook requests (default: 10s)")
+		healthAddr        = flag.String("health-addr", "", "Serve /healthz and /readyz probes and /metrics on this address (e.g., :8080)")
+		quiet             = flag.Bool("quiet", false, "Print only the pod report, skipping warnings and recommendations")
+		diagnose          = flag.Bool("diagnose", false, "Run connectivity, RBAC and metrics-server checks and exit")
+		diffPath          = flag.String("diff", "", "Compare the live cluster with a report saved with --output=json, print usage deltas and new problems, then exit")
+		replay            = flag.String("replay", "", "Re-run the analysis on reports saved with --output=json instead of querying the cluster, then exit")
+		version           = flag.Bool("version", false, "Show version information")
+		help              = flag.Bool("help", false, "Show help message")
 	)
 
+	var webhookHeaders stringListFlag
+	flag.Var(&webhookHeaders, "webhook-header", "Header sent with webhook requests as 'Name: value' (repeatable)")
+	var excludeLabels stringListFlag
+	flag.Var(&excludeLabels, "exclude-label", "Skip pods carrying this label as 'key=value', still counting them in totals (repeatable)")
+
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Kubernetes Memory Monitoring Tool\n\n")
 		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS]\n\n", os.Args[0])
@@ -53,17 +159,55 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  # Single check (default behavior)\n")
 		fmt.Fprintf(os.Stderr, "  %s --namespace=production\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --all-namespaces\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --namespace=production --pod=api-7f9c\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --node=node-5\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\n  # Continuous monitoring\n")
 		fmt.Fprintf(os.Stderr, "  %s --watch --check-interval=1m\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --watch --namespace=production --check-interval=30s\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --watch-status-only --check-interval=10s\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --check-interval=1m --interval-jitter=10s\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --check-interval=15s --report-interval=5m\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\n  # Other options\n")
 		fmt.Fprintf(os.Stderr, "  %s --labels=dag_id,task_id,run_id\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --annotations=owner,team --labels=app\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --exclude-container=istio-proxy\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --exclude-label=monitoring.io/ignore=true --exclude-label=tier=batch\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --include-phases=Running,Failed\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --output=csv --labels=app,version > pods.csv\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --output=table-wide --namespace=production\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --quiet --namespace=production\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --sort-by=headroom\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --sort-by=headroom --sort-order=desc\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --primary-metric=limit --memory-warning=85\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --group-by=node\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --group-by-label=team\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --efficiency --all-namespaces\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --compare-requests --rightsizing-low=40 --rightsizing-high=95\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --suggest-requests --suggest-headroom-factor=1.3 --suggest-round-to=64Mi\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --diagnose --namespace=production\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --replay=report.json --memory-warning=70\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --diff=before-deploy.json --namespace=production\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --watch --output=csv --all-namespaces > cluster-memory.csv\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --summary-on-exit --namespace=production\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --csv-append=/var/lib/memory-watch/samples.csv\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --output=csv --output-file=report.csv --rotate-size=100MB --rotate-interval=24h\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --output=json --problems-only | alert-router\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --slack-webhook=https://hooks.slack.com/services/...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --health-addr=:8080\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --watch --webhook-url=https://incidents.example.com/hook --webhook-header='Authorization: Bearer TOKEN'\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nEnvironment Variables (lower priority than CLI flags):\n")
-		fmt.Fprintf(os.Stderr, "  NAMESPACE, KUBECONFIG, IN_CLUSTER, CHECK_INTERVAL,\n")
-		fmt.Fprintf(os.Stderr, "  MEMORY_THRESHOLD_MB, MEMORY_WARNING_PERCENT, LOG_LEVEL, WATCH\n")
+		fmt.Fprintf(os.Stderr, "  NAMESPACE, NODE, LABEL_SELECTOR, KUBECONFIG, IN_CLUSTER, KUBE_CONTEXTS, METRICS_API_GROUP, NO_METRICS, CHECK_INTERVAL,\n")
+		fmt.Fprintf(os.Stderr, "  INTERVAL_JITTER, REPORT_INTERVAL, REFRESH_METRICS_ONLY, POD_CACHE_TTL, SAMPLES, SAMPLE_INTERVAL, MAX_METRICS_AGE, MEMORY_THRESHOLD, MEMORY_WARNING_PERCENT, PRIMARY_METRIC, LOG_LEVEL, LOG_FORMAT, QUIET,\n")
+		fmt.Fprintf(os.Stderr, "  SYSTEM_CONTAINERS, INCLUDE_SYSTEM_CONTAINERS, ENV_VARS, ANNOTATIONS_AS_LABELS,\n")
+		fmt.Fprintf(os.Stderr, "  EXCLUDE_LABELS, MAX_RETRIES, RETRY_BACKOFF, LIST_PAGE_SIZE, MAX_NAMESPACES, STRICT, SORT_BY, SORT_ORDER, SORT_CONTAINERS, GROUP_BY, GROUP_BY_LABEL, COLOR, SHOW_IMAGES, INCLUDE_PHASES, PERCENT_PRECISION, UNITS,\n")
+		fmt.Fprintf(os.Stderr, "  TIMESTAMP_FORMAT, EXPECTED_MEMORY_ANNOTATION, DEDUPE_PROBLEMS, MAX_PROBLEMS, ONLY_PROBLEMS, SUMMARY_ONLY,\n")
+		fmt.Fprintf(os.Stderr, "  SYMBOL_OK, SYMBOL_WARNING, SYMBOL_CRITICAL, SYMBOL_NODATA, SYMBOL_COMPLETED,\n")
+		fmt.Fprintf(os.Stderr, "  WATCH, WATCH_NAMESPACE_EVENTS, WATCH_THRESHOLD_CROSSINGS, WATCH_PODS_CHANGED_ONLY,\n")
+		fmt.Fprintf(os.Stderr, "  REQUEST_AS_PERCENT_OF_LIMIT, CONTAINER_STATS, COMPACT_PODS, EXPLAIN,\n")
+		fmt.Fprintf(os.Stderr, "  SUMMARY_ON_EXIT, CSV_APPEND, CSV_TOTALS, CSV_CYCLE, OUTPUT_FILE, ROTATE_SIZE, ROTATE_INTERVAL,\n")
+		fmt.Fprintf(os.Stderr, "  SLACK_WEBHOOK_URL, WEBHOOK_URL, WEBHOOK_HEADER, REQUEST_TIMEOUT, HEALTH_ADDR,\n")
+		fmt.Fprintf(os.Stderr, "  SUGGEST_REQUESTS, SUGGEST_HEADROOM_FACTOR, SUGGEST_ROUND_TO,\n")
+		fmt.Fprintf(os.Stderr, "  COMPARE_REQUESTS, RIGHTSIZING_LOW_PERCENT, RIGHTSIZING_HIGH_PERCENT\n")
 	}
 
 	flag.Parse()
@@ -86,21 +230,114 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: --namespace and --all-namespaces are mutually exclusive\n")
 		os.Exit(1)
 	}
+	if *namespace != "" && *nodeName != "" {
+		fmt.Fprintf(os.Stderr, "Error: --node lists pods across all namespaces and cannot be combined with --namespace\n")
+		os.Exit(1)
+	}
+
+	// Only override precision when the flag was given, since 0 is a valid value
+	var percentPrecisionOverride *int
+	if *percentPrecision >= 0 {
+		percentPrecisionOverride = percentPrecision
+	}
+
+	// Likewise for retries, where 0 disables retrying
+	var maxRetriesOverride *int
+	if *maxRetries >= 0 {
+		maxRetriesOverride = maxRetries
+	}
 
 	// Create CLI config
 	cliConfig := &config.CLIConfig{
 		Namespace:            *namespace,
 		AllNamespaces:        *allNamespaces,
+		PodName:              *podName,
+		NodeName:             *nodeName,
+		LabelSelector:        *labelSelector,
 		KubeConfig:           *kubeconfig,
 		InCluster:            *inCluster,
+		Contexts:             *contexts,
+		MetricsAPIGroup:      *metricsAPIGroup,
+		NoMetrics:            *noMetrics,
 		CheckInterval:        *checkInterval,
-		MemoryThresholdMB:    *memoryThreshold,
+		IntervalJitter:       *intervalJitter,
+		ReportInterval:       *reportInterval,
+		RefreshMetricsOnly:   *refreshMetrics,
+		PodCacheTTL:          *podCacheTTL,
+		Samples:              *samples,
+		SampleInterval:       *sampleInterval,
+		MaxMetricsAge:        *maxMetricsAge,
+		MemoryThreshold:      *memoryThreshold,
 		MemoryWarningPercent: *memoryWarning,
+		PrimaryMetric:        *primaryMetric,
 		Watch:                *watch,
+		WatchNamespaceEvents: *namespaceEvents,
+		WatchCrossings:       *crossings,
+		WatchChangedOnly:     *changedOnly,
+		MaxRetries:           maxRetriesOverride,
+		RetryBackoff:         *retryBackoff,
+		ListPageSize:         *listPageSize,
+		MaxNamespaces:        *maxNamespaces,
+		Strict:               *strict,
 		LogLevel:             *logLevel,
+		LogFormat:            *logFormat,
 		Labels:               *labels,
 		Annotations:          *annotations,
+		EnvVars:              *envVars,
+		AnnotationsAsLabels:  *annotationsLabels,
+		ExpectedMemory:       *expectedMemory,
+		SymbolOK:             *symbolOK,
+		SymbolWarning:        *symbolWarning,
+		SymbolCritical:       *symbolCritical,
+		SymbolNoData:         *symbolNoData,
+		SymbolCompleted:      *symbolCompleted,
+		Containers:           *containers,
+		ExcludeContainers:    *excludeContainers,
+		SystemContainers:     *systemContainers,
+		IncludeSystem:        *includeSystem,
+		ExcludeLabels:        excludeLabels,
+		HealthAddr:           *healthAddr,
+		IncludePhases:        *includePhases,
 		Output:               *output,
+		Quiet:                *quiet,
+		SortBy:               *sortBy,
+		SortOrder:            *sortOrder,
+		SortContainers:       *sortContainers,
+		GroupBy:              *groupBy,
+		GroupByLabel:         *groupByLabel,
+		Color:                *color,
+		PercentPrecision:     percentPrecisionOverride,
+		Units:                *units,
+		TimestampFormat:      *timestampFormat,
+		WatchStatusOnly:      *statusOnly,
+		ProblemsOnly:         *problemsOnly,
+		OnlyProblems:         *onlyProblems,
+		DedupeProblems:       *dedupeProblems,
+		MaxProblems:          *maxProblems,
+		SummaryOnly:          *summaryOnly,
+		ShowEfficiency:       *efficiency,
+		ShowImages:           *showImages,
+		ShowRequestRatio:     *requestRatio,
+		ContainerStats:       *containerStats,
+		CompactPods:          *compactPods,
+		Explain:              *explain,
+		SummaryOnExit:        *summaryOnExit,
+		SuggestRequests:      *suggestRequests,
+		SuggestFactor:        *suggestFactor,
+		SuggestRoundTo:       *suggestRoundTo,
+		CompareRequests:      *compareRequests,
+		RightSizingLow:       *rightSizingLow,
+		RightSizingHigh:      *rightSizingHigh,
+		CSVAppendPath:        *csvAppend,
+		CSVTotals:            *csvTotals,
+		CSVCycle:             *csvCycle,
+		OutputFile:           *outputFile,
+		RotateSize:           *rotateSize,
+		RotateInterval:       *rotateInterval,
+		SlackWebhookURL:      *slackWebhook,
+		WebhookURL:           *webhookURL,
+		WebhookHeaders:       webhookHeaders,
+		RequestTimeout:       *requestTimeout,
 	}
 
 	// Load configuration (combines env vars with CLI flags)
@@ -108,49 +345,75 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load configuration:", err)
 	}
+	k8s.SetPercentPrecision(cfg.PercentPrecision)
+	k8s.SetUnitSystem(unitSystem(cfg.Units))
+	isTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	cfg.UseColor = cfg.ResolveColor(isTerminal)
+	cfg.WatchStatusOnly = cfg.ResolveStatusOnly(isTerminal)
 
-	// Set up structured logging (suppressed in CSV mode)
-	if cfg.Output != config.OutputFormatCSV {
-		logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-		}))
-		slog.SetDefault(logger)
+	// Set up structured logging (only in table mode)
+	if cfg.IsTableOutput() {
+		slog.SetDefault(slog.New(newLogHandler(cfg)))
 		slog.Info("Starting Kubernetes Management Monitoring Application")
 		slog.Info("Configuration loaded successfully",
 			"namespace", cfg.Namespace,
 			"all_namespaces", cfg.AllNamespaces,
 			"check_interval", cfg.CheckInterval)
 	}
+	if collisions := cfg.CSVColumnCollisions(); len(collisions) > 0 {
+		slog.Warn("Repeated CSV column names get a numeric suffix (_2, _3, ...)",
+			"names", strings.Join(collisions, ","))
+	}
+
+	if *replay != "" {
+		os.Exit(runReplay(*replay, cfg))
+	}
 
 	// Create memory monitor
 	memMonitor, err := monitor.New(cfg)
+	if *diagnose {
+		os.Exit(runDiagnostics(memMonitor, err))
+	}
 	if err != nil {
 		log.Fatal("Failed to create memory monitor:", err)
 	}
+	defer memMonitor.Close()
 
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if cfg.HealthAddr != "" {
+		healthStatus = monitor.NewHealthStatus(cfg.ReadinessWindow(), memMonitor.Now)
+		mux := http.NewServeMux()
+		mux.Handle("/", healthStatus.Handler())
+		mux.Handle("/metrics", memMonitor.MetricsHandler())
+		startHealthServer(ctx, cfg.HealthAddr, mux)
+	}
+
 	// Perform initial health check
-	if cfg.Output != config.OutputFormatCSV {
+	if cfg.IsTableOutput() {
 		slog.Info("Performing initial health check...")
 	}
 	if err := memMonitor.HealthCheck(ctx); err != nil {
-		if cfg.Output != config.OutputFormatCSV {
+		if cfg.IsTableOutput() {
 			slog.Error("Health check failed", "error", err)
 		}
 		cancel()
 		return
 	}
 
+	if *diffPath != "" {
+		os.Exit(runDiff(ctx, memMonitor, *diffPath, cfg))
+	}
+
 	// Set up graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-sigChan
-		if cfg.Output != config.OutputFormatCSV {
+		if cfg.IsTableOutput() {
 			slog.Info("Received shutdown signal, gracefully shutting down...")
 		}
 		cancel()
@@ -158,79 +421,265 @@ func main() {
 
 	// Run initial collection and analysis
 	if err := runMemoryCheck(ctx, memMonitor, cfg); err != nil {
-		if cfg.Output != config.OutputFormatCSV {
+		if cfg.IsTableOutput() {
 			slog.Error("Initial memory check failed", "error", err)
 		}
+		if cfg.Strict && !cfg.Watch {
+			fmt.Fprintf(os.Stderr, "Memory check failed: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Only continue with continuous monitoring if --watch flag is enabled
 	if !cfg.Watch {
-		if cfg.Output != config.OutputFormatCSV {
+		if cfg.IsTableOutput() {
 			slog.Info("Single check completed. Use --watch for continuous monitoring.")
 		}
 		return
 	}
 
 	// Continuous monitoring mode
-	if cfg.Output != config.OutputFormatCSV {
+	if cfg.IsTableOutput() {
 		slog.Info("Starting continuous monitoring loop...")
 	}
 
-	ticker := time.NewTicker(cfg.CheckInterval)
-	defer ticker.Stop()
+	// A timer is re-armed after each cycle so every wait gets its own jitter
+	timer := time.NewTimer(cfg.NextCheckDelay())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			if cfg.Output != config.OutputFormatCSV {
+			if cfg.SummaryOnExit {
+				printSessionSummary(memMonitor, cfg)
+			}
+			if cfg.IsTableOutput() {
 				slog.Info("Application shutdown complete")
 			}
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			if err := runMemoryCheck(ctx, memMonitor, cfg); err != nil {
-				if cfg.Output != config.OutputFormatCSV {
+				if cfg.IsTableOutput() {
 					slog.Error("Memory check cycle failed", "error", err)
 				}
 			}
+			timer.Reset(cfg.NextCheckDelay())
 		}
 	}
 }
 
-// runMemoryCheck executes a single cycle of memory monitoring and analysis
-func runMemoryCheck(ctx context.Context, memMonitor *monitor.MemoryMonitor, cfg *config.Config) error {
-	if cfg.Output != config.OutputFormatCSV {
-		slog.Info("Starting memory check cycle...", "timestamp", time.Now().Format(time.RFC3339))
+// printSessionSummary prints the session recap, on stderr when stdout carries CSV or JSON
+func printSessionSummary(memMonitor *monitor.MemoryMonitor, cfg *config.Config) {
+	out := os.Stdout
+	if !cfg.IsTableOutput() {
+		out = os.Stderr
 	}
+	memMonitor.Session().Print(out, memMonitor.Now())
+}
 
-	// Perform memory analysis
-	analysis, err := memMonitor.AnalyzeMemoryUsage(ctx)
+// startHealthServer serves the liveness and readiness probes until ctx is cancelled
+func startHealthServer(ctx context.Context, addr string, handler http.Handler) {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Health server failed", "addr", addr, "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+}
+
+// runReplay analyzes the reports of a saved JSON file with the current configuration and returns the exit code
+func runReplay(path string, cfg *config.Config) int {
+	reports, err := monitor.LoadReports(path)
 	if err != nil {
-		return err
+		fmt.Fprintf(os.Stderr, "Replay failed: %v\n", err)
+		return 1
+	}
+	for i := range reports {
+		renderReport(monitor.AnalyzeReport(&reports[i], cfg), cfg)
 	}
+	return 0
+}
 
-	// Print output according to format
-	if cfg.Output == config.OutputFormatCSV {
-		// Show header only on first run
-		analysis.Report.PrintCSV(cfg, !csvHeaderPrinted)
-		csvHeaderPrinted = true
+// runDiff compares a live collection with the last report of a saved JSON file and returns the exit code
+// Both reports are analyzed with the current configuration so their problems are comparable
+func runDiff(ctx context.Context, memMonitor *monitor.MemoryMonitor, path string, cfg *config.Config) int {
+	reports, err := monitor.LoadReports(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Diff failed: %v\n", err)
+		return 1
+	}
+	old := monitor.AnalyzeReport(&reports[len(reports)-1], cfg)
+
+	current, err := memMonitor.AnalyzeMemoryUsage(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Diff failed: %v\n", err)
+		return 1
+	}
+
+	diff := monitor.DiffReports(old, current)
+	if cfg.Output == config.OutputFormatJSON {
+		diff.PrintJSON()
 	} else {
-		// Print the complete detailed report showing all pods
-		analysis.Report.PrintDetailedReport(cfg)
-		// Always print analysis (warnings, recommendations)
-		analysis.PrintAnalysis(cfg)
+		diff.PrintDiff(cfg)
+	}
+	return 0
+}
+
+// runDiagnostics prints a PASS/FAIL checklist and returns the process exit code
+func runDiagnostics(memMonitor *monitor.MemoryMonitor, setupErr error) int {
+	fmt.Printf("=== Connectivity Diagnostics ===\n")
+	if setupErr != nil {
+		printDiagnostic(k8s.DiagnosticResult{Name: "Load Kubernetes configuration", Err: setupErr})
+		return 1
+	}
+	printDiagnostic(k8s.DiagnosticResult{Name: "Load Kubernetes configuration"})
+
+	exitCode := 0
+	for _, result := range memMonitor.Diagnose(context.Background()) {
+		printDiagnostic(result)
+		if !result.Passed() {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
+
+func printDiagnostic(result k8s.DiagnosticResult) {
+	if result.Passed() {
+		fmt.Printf("[PASS] %s\n", result.Name)
+		return
+	}
+	fmt.Printf("[FAIL] %s\n       %v\n", result.Name, result.Err)
+}
+
+// unitSystem maps the configured units name to the k8s display unit system
+func unitSystem(units string) k8s.UnitSystem {
+	switch units {
+	case config.UnitsBinary:
+		return k8s.UnitsBinary
+	case config.UnitsDecimal:
+		return k8s.UnitsDecimal
+	default:
+		return k8s.UnitsLegacy
+	}
+}
+
+// newLogHandler builds the slog handler matching the configured log format and level
+func newLogHandler(cfg *config.Config) slog.Handler {
+	opts := &slog.HandlerOptions{Level: cfg.SlogLevel()}
+	if cfg.LogFormat == config.LogFormatText {
+		return slog.NewTextHandler(os.Stdout, opts)
+	}
+	return slog.NewJSONHandler(os.Stdout, opts)
+}
+
+// runMemoryCheck executes a single cycle of memory monitoring and analysis
+// Collection runs on every cycle, while printing the report is throttled by --report-interval
+func runMemoryCheck(ctx context.Context, memMonitor *monitor.MemoryMonitor, cfg *config.Config) error {
+	analysis, err := collectMemoryCheck(ctx, memMonitor, cfg)
+	healthStatus.RecordCycle(err)
+	if err != nil {
+		return err
+	}
+	if cfg.OutputFile != "" {
+		// The report went to the file instead of stdout
+		return nil
+	}
+
+	if now := memMonitor.Now(); cfg.ReportDue(lastReport, now) {
+		lastReport = now
+		if cfg.WatchStatusOnly {
+			// The compact view already shows the summary, so skip the trailing log line
+			analysis.PrintStatusOnly(cfg)
+			return nil
+		}
+		renderReport(analysis, cfg)
 	}
 
 	// Log summary information structured (only in table mode)
-	if cfg.Output != config.OutputFormatCSV {
+	if cfg.IsTableOutput() {
 		slog.Info("Memory check completed",
 			"total_pods", analysis.Report.Summary.TotalPods,
 			"running_pods", analysis.Report.Summary.RunningPods,
-			"problems_found", len(analysis.ProblemsFound),
+			"problems_found", analysis.ProblemCount(),
 			"high_usage_pods", len(analysis.HighUsagePods),
 			"warning_pods", len(analysis.WarningPods),
+			"critical_pods", analysis.Report.Summary.CriticalPods,
+			"warning_pods_count", analysis.Report.Summary.WarningPodsCount,
+			"pods_over_limit", analysis.Report.Summary.PodsOverLimit,
 			"total_memory_usage", analysis.Report.Summary.TotalMemoryUsage.String(),
+			"collection_duration_ms", analysis.Report.Summary.Timings.Total.Milliseconds(),
 		)
 	}
 
 	return nil
 }
+
+// collectMemoryCheck analyzes the current memory usage and feeds the sinks that record every cycle
+// (notifications, webhook, CSV samples and the output file)
+func collectMemoryCheck(ctx context.Context, memMonitor *monitor.MemoryMonitor, cfg *config.Config) (
+	*monitor.AnalysisResult, error) {
+	if cfg.IsTableOutput() {
+		slog.Info("Starting memory check cycle...", "timestamp", memMonitor.Timestamp())
+	}
+
+	// Perform memory analysis
+	analysis, err := memMonitor.AnalyzeMemoryUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memMonitor.NotifyCritical(ctx, analysis)
+	memMonitor.PublishWebhook(ctx, analysis)
+	if cfg.CSVAppendPath != "" {
+		if err := analysis.Report.AppendCSV(cfg, cfg.CSVAppendPath); err != nil {
+			slog.Error("Failed to append CSV samples", "path", cfg.CSVAppendPath, "error", err)
+		}
+	}
+	if cfg.OutputFile != "" {
+		// The report goes to the file instead of stdout
+		if err := memMonitor.WriteOutputFile(analysis); err != nil {
+			slog.Error("Failed to write output file", "path", cfg.OutputFile, "error", err)
+		}
+	}
+	return analysis, nil
+}
+
+// renderReport prints the report according to the output format
+func renderReport(analysis *monitor.AnalysisResult, cfg *config.Config) {
+	switch cfg.Output {
+	case config.OutputFormatCSV:
+		// Show header only on first run
+		analysis.Report.PrintCSV(cfg, !csvHeaderPrinted)
+		csvHeaderPrinted = true
+	case config.OutputFormatJSON:
+		analysis.PrintJSON(cfg)
+	default:
+		if cfg.Watch {
+			analysis.Report.PrintCycleBanner(cfg)
+		}
+		// Print the complete detailed report showing all pods
+		analysis.Report.PrintDetailedReport(cfg)
+		if cfg.ShowEfficiency {
+			analysis.Report.PrintEfficiencyReport()
+		}
+		if cfg.GroupBy == config.GroupByNode {
+			analysis.Report.PrintNodeReport()
+		}
+		if cfg.GroupByLabel != "" {
+			analysis.Report.PrintLabelGroupReport(cfg.GroupByLabel)
+		}
+		if cfg.CompareRequests {
+			analysis.Report.PrintRightSizing(cfg.RightSizingLow, cfg.RightSizingHigh)
+		}
+		// Print analysis (warnings, recommendations) unless quiet mode is enabled
+		if !cfg.Quiet {
+			analysis.PrintAnalysis(cfg)
+		}
+	}
+}
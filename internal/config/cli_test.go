@@ -226,3 +226,865 @@ func TestLoadWithCLI_WatchFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadWithCLI_InvalidLogLevel(t *testing.T) {
+	_, err := LoadWithCLI(&CLIConfig{LogLevel: "verbose"})
+	if err == nil {
+		t.Error("Expected validation error for invalid log level")
+	}
+}
+
+func TestLoadWithCLI_LogFormatOverride(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{LogFormat: "text"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.LogFormat != "text" {
+		t.Errorf("Expected log format 'text', got '%s'", cfg.LogFormat)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{LogFormat: "xml"}); err == nil {
+		t.Error("Expected validation error for invalid log format")
+	}
+}
+
+func TestLoadWithCLI_QuietFlag(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Quiet: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.Quiet {
+		t.Error("Expected Quiet to be true when --quiet is specified")
+	}
+}
+
+func TestLoadWithCLI_RetryOverrides(t *testing.T) {
+	five := 5
+	cfg, err := LoadWithCLI(&CLIConfig{MaxRetries: &five, RetryBackoff: time.Second})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxRetries != 5 || cfg.RetryBackoff != time.Second {
+		t.Errorf("Expected retries 5 with 1s backoff, got %d with %v", cfg.MaxRetries, cfg.RetryBackoff)
+	}
+
+	// An explicit 0 disables retrying rather than keeping the default
+	zero := 0
+	cfg, err = LoadWithCLI(&CLIConfig{MaxRetries: &zero})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxRetries != 0 {
+		t.Errorf("Expected retries to be disabled, got %d", cfg.MaxRetries)
+	}
+
+	negative := -1
+	if _, err := LoadWithCLI(&CLIConfig{MaxRetries: &negative}); err == nil {
+		t.Error("Expected validation error for negative max retries")
+	}
+}
+
+func TestLoadWithCLI_PodRequiresNamespace(t *testing.T) {
+	if _, err := LoadWithCLI(&CLIConfig{PodName: "api"}); err == nil {
+		t.Error("Expected validation error for --pod without --namespace")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{PodName: "api", AllNamespaces: true}); err == nil {
+		t.Error("Expected validation error for --pod with --all-namespaces")
+	}
+
+	cfg, err := LoadWithCLI(&CLIConfig{PodName: "api", Namespace: "prod"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.PodName != "api" || cfg.Namespace != "prod" {
+		t.Errorf("Expected pod prod/api, got %s/%s", cfg.Namespace, cfg.PodName)
+	}
+}
+
+func TestLoadWithCLI_ContainerFilters(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Containers: "app", ExcludeContainers: "istio-proxy, linkerd-proxy"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.Containers) != 1 || cfg.Containers[0] != "app" {
+		t.Errorf("expected containers [app], got %v", cfg.Containers)
+	}
+	if len(cfg.ExcludeContainers) != 2 || cfg.ExcludeContainers[1] != "linkerd-proxy" {
+		t.Errorf("expected exclude containers [istio-proxy linkerd-proxy], got %v", cfg.ExcludeContainers)
+	}
+}
+
+func TestLoadWithCLI_SystemContainers(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.SystemContainers) != 1 || cfg.SystemContainers[0] != "POD" || cfg.IncludeSystem {
+		t.Errorf("expected POD containers to be skipped by default, got %v (include %v)", cfg.SystemContainers, cfg.IncludeSystem)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{SystemContainers: "POD, pause*", IncludeSystem: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.SystemContainers) != 2 || cfg.SystemContainers[1] != "pause*" || !cfg.IncludeSystem {
+		t.Errorf("expected system containers [POD pause*] to be included, got %v (include %v)", cfg.SystemContainers, cfg.IncludeSystem)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{SystemContainers: "pause["}); err == nil {
+		t.Error("Expected validation error for an invalid system container pattern")
+	}
+}
+
+func TestLoadWithCLI_EnvVars(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{EnvVars: "DAG_ID, TASK_ID"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.EnvVars) != 2 || cfg.EnvVars[0] != "DAG_ID" || cfg.EnvVars[1] != "TASK_ID" {
+		t.Errorf("Expected env vars [DAG_ID TASK_ID], got %v", cfg.EnvVars)
+	}
+}
+
+func TestLoadWithCLI_PercentPrecision(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.PercentPrecision != 1 {
+		t.Errorf("Expected default percent precision 1, got %d", cfg.PercentPrecision)
+	}
+
+	zero := 0
+	cfg, err = LoadWithCLI(&CLIConfig{PercentPrecision: &zero})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.PercentPrecision != 0 {
+		t.Errorf("Expected percent precision 0, got %d", cfg.PercentPrecision)
+	}
+
+	tooMany := 10
+	if _, err := LoadWithCLI(&CLIConfig{PercentPrecision: &tooMany}); err == nil {
+		t.Error("Expected validation error for excessive percent precision")
+	}
+}
+
+func TestLoadWithCLI_Units(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Units: UnitsBinary})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.Units != UnitsBinary {
+		t.Errorf("Expected units %q, got %q", UnitsBinary, cfg.Units)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{Units: "metric"}); err == nil {
+		t.Error("Expected validation error for invalid units")
+	}
+}
+
+func TestLoadWithCLI_SlackWebhook(t *testing.T) {
+	t.Setenv("SLACK_WEBHOOK_URL", "https://hooks.example.com/env")
+
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SlackWebhookURL != "https://hooks.example.com/env" {
+		t.Errorf("Expected webhook from env, got %q", cfg.SlackWebhookURL)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{SlackWebhookURL: "https://hooks.example.com/cli"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SlackWebhookURL != "https://hooks.example.com/cli" {
+		t.Errorf("Expected webhook from CLI, got %q", cfg.SlackWebhookURL)
+	}
+}
+
+func TestLoadWithCLI_Webhook(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{
+		WebhookURL:     "https://incidents.example.com/hook",
+		WebhookHeaders: []string{"Authorization: Bearer token"},
+	})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.WebhookURL != "https://incidents.example.com/hook" {
+		t.Errorf("Expected webhook URL to be set, got %q", cfg.WebhookURL)
+	}
+	if cfg.RequestTimeout != 10*time.Second {
+		t.Errorf("Expected default request timeout 10s, got %v", cfg.RequestTimeout)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{WebhookHeaders: []string{"no-separator"}}); err == nil {
+		t.Error("Expected validation error for malformed webhook header")
+	}
+}
+
+func TestLoadWithCLI_IntervalJitter(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{IntervalJitter: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.IntervalJitter != 10*time.Second {
+		t.Errorf("Expected interval jitter 10s, got %v", cfg.IntervalJitter)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{IntervalJitter: -time.Second}); err == nil {
+		t.Error("Expected validation error for negative interval jitter")
+	}
+}
+
+func TestLoadWithCLI_SuggestRequests(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{SuggestRequests: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SuggestFactor != 1.2 || cfg.SuggestRoundToBytes() != 32*1024*1024 {
+		t.Errorf("Expected defaults 1.2 and 32Mi, got %v and %d", cfg.SuggestFactor, cfg.SuggestRoundToBytes())
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{SuggestRequests: true, SuggestFactor: 1.5, SuggestRoundTo: "64Mi"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SuggestFactor != 1.5 || cfg.SuggestRoundToBytes() != 64*1024*1024 {
+		t.Errorf("Expected 1.5 and 64Mi, got %v and %d", cfg.SuggestFactor, cfg.SuggestRoundToBytes())
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{SuggestRequests: true, SuggestRoundTo: "lots"}); err == nil {
+		t.Error("Expected validation error for invalid rounding quantity")
+	}
+}
+
+func TestLoadWithCLI_IncludePhases(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.IncludePhases) != 2 || cfg.IncludePhases[0] != PhaseRunning || cfg.IncludePhases[1] != PhasePending {
+		t.Errorf("Expected default phases [Running Pending], got %v", cfg.IncludePhases)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{IncludePhases: "running, FAILED"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.IncludePhases) != 2 || cfg.IncludePhases[0] != PhaseRunning || cfg.IncludePhases[1] != PhaseFailed {
+		t.Errorf("Expected normalized phases [Running Failed], got %v", cfg.IncludePhases)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{IncludePhases: "Crashing"}); err == nil {
+		t.Error("Expected validation error for unknown phase")
+	}
+}
+
+func TestLoadWithCLI_CompareRequests(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{CompareRequests: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.RightSizingLow != 50 || cfg.RightSizingHigh != 90 {
+		t.Errorf("Expected default thresholds 50/90, got %v/%v", cfg.RightSizingLow, cfg.RightSizingHigh)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{CompareRequests: true, RightSizingLow: 95}); err == nil {
+		t.Error("Expected validation error when the low threshold is above the high threshold")
+	}
+}
+
+func TestLoadWithCLI_NodeName(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{NodeName: "node-5"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.NodeName != "node-5" || !cfg.AllNamespaces {
+		t.Errorf("Expected node-5 across all namespaces, got node=%q all=%v", cfg.NodeName, cfg.AllNamespaces)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{NodeName: "node-5", Namespace: "prod"}); err == nil {
+		t.Error("Expected validation error when combining node and namespace")
+	}
+}
+
+func TestLoadWithCLI_Contexts(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Contexts: "prod, staging"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.Contexts) != 2 || cfg.Contexts[0] != "prod" || cfg.Contexts[1] != "staging" {
+		t.Errorf("Expected contexts [prod staging], got %v", cfg.Contexts)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{Contexts: "prod", InCluster: true}); err == nil {
+		t.Error("Expected validation error when combining contexts and in-cluster")
+	}
+}
+
+func TestLoadWithCLI_MaxMetricsAge(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{MaxMetricsAge: 2 * time.Minute})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxMetricsAge != 2*time.Minute {
+		t.Errorf("Expected max metrics age 2m, got %v", cfg.MaxMetricsAge)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{MaxMetricsAge: -time.Minute}); err == nil {
+		t.Error("Expected validation error for negative max metrics age")
+	}
+}
+
+func TestLoadWithCLI_SortOrder(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{SortBy: SortByHeadroom, SortOrder: SortOrderDesc})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SortOrder != SortOrderDesc {
+		t.Errorf("Expected descending sort order, got %q", cfg.SortOrder)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{SortOrder: "down"}); err == nil {
+		t.Error("Expected validation error for unknown sort order")
+	}
+}
+
+func TestLoadWithCLI_GroupBy(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{GroupBy: GroupByNode})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.GroupBy != GroupByNode {
+		t.Errorf("Expected group by node, got %q", cfg.GroupBy)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{GroupBy: "zone"}); err == nil {
+		t.Error("Expected validation error for unknown grouping")
+	}
+}
+
+func TestLoadWithCLI_GroupByLabel(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{GroupByLabel: "team"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.GroupByLabel != "team" {
+		t.Errorf("Expected group by label team, got %q", cfg.GroupByLabel)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{GroupByLabel: "team", Output: "json"}); err == nil {
+		t.Error("Expected validation error for label grouping with JSON output")
+	}
+}
+
+func TestLoadWithCLI_MetricsAPIGroup(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{MetricsAPIGroup: "custom.metrics.example.com"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MetricsAPIGroup != "custom.metrics.example.com" {
+		t.Errorf("Expected custom metrics API group, got %q", cfg.MetricsAPIGroup)
+	}
+}
+
+func TestLoadWithCLI_NoMetrics(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{NoMetrics: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.NoMetrics {
+		t.Error("Expected metrics to be disabled")
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{NoMetrics: true, Watch: true, RefreshMetricsOnly: true}); err == nil {
+		t.Error("Expected validation error for refresh_metrics_only with no_metrics")
+	}
+}
+
+func TestLoadWithCLI_CSVAppendPath(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{CSVAppendPath: "/tmp/samples.csv"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.CSVAppendPath != "/tmp/samples.csv" {
+		t.Errorf("Expected CSV append path, got %q", cfg.CSVAppendPath)
+	}
+}
+
+func TestLoadWithCLI_SummaryOnExit(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{SummaryOnExit: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.SummaryOnExit {
+		t.Error("Expected SummaryOnExit to be enabled")
+	}
+}
+
+func TestLoadWithCLI_PrimaryMetric(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{PrimaryMetric: PrimaryMetricLimit})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.LimitIsPrimary() {
+		t.Errorf("Expected limit as primary metric, got %q", cfg.PrimaryMetric)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{PrimaryMetric: "usage"}); err == nil {
+		t.Error("Expected validation error for unknown primary metric")
+	}
+}
+
+func TestLoadWithCLI_SortContainers(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{SortContainers: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.SortContainers {
+		t.Error("Expected SortContainers to be enabled")
+	}
+}
+
+func TestLoadWithCLI_WatchNamespaceEvents(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{WatchNamespaceEvents: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.WatchNamespaceEvents {
+		t.Error("Expected WatchNamespaceEvents to be enabled")
+	}
+}
+
+func TestLoadWithCLI_OutputFileRotation(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Output: OutputFormatCSV, OutputFile: "report.csv", RotateSize: "100MB",
+		RotateInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.OutputFile != "report.csv" || cfg.RotateSizeBytes() != 100_000_000 || cfg.RotateInterval != time.Hour {
+		t.Errorf("Expected report.csv rotated at 100MB or hourly, got %q %d %v",
+			cfg.OutputFile, cfg.RotateSizeBytes(), cfg.RotateInterval)
+	}
+
+	invalid := []*CLIConfig{
+		{OutputFile: "report.txt"},
+		{Output: OutputFormatCSV, OutputFile: "report.csv", RotateSize: "lots"},
+		{Output: OutputFormatJSON, RotateSize: "10MiB"},
+	}
+	for _, cli := range invalid {
+		if _, err := LoadWithCLI(cli); err == nil {
+			t.Errorf("Expected validation error for %+v", cli)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	for value, expected := range map[string]int64{"100MB": 100_000_000, "64MiB": 64 << 20, "64Mi": 64 << 20, "512": 512, "512B": 512} {
+		q, err := ParseByteSize(value)
+		if err != nil || q.Value() != expected {
+			t.Errorf("ParseByteSize(%q) = %d, %v; expected %d", value, q.Value(), err, expected)
+		}
+	}
+}
+
+func TestLoadWithCLI_SummaryOnly(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Output: OutputFormatJSON, SummaryOnly: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.SummaryOnly {
+		t.Error("Expected SummaryOnly to be enabled")
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{Output: OutputFormatCSV, SummaryOnly: true}); err == nil {
+		t.Error("Expected validation error for summary-only without JSON output")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{Output: OutputFormatJSON, SummaryOnly: true, ProblemsOnly: true}); err == nil {
+		t.Error("Expected validation error when combining summary-only and problems-only")
+	}
+}
+
+func TestLoadWithCLI_DedupeProblems(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{DedupeProblems: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.DedupeProblems {
+		t.Error("Expected DedupeProblems to be enabled")
+	}
+}
+
+func TestLoadWithCLI_TimestampFormat(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{TimestampFormat: TimestampEpochMillis})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.TimestampFormat != TimestampEpochMillis {
+		t.Errorf("Expected epochmillis timestamps, got %q", cfg.TimestampFormat)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{TimestampFormat: "unix"}); err == nil {
+		t.Error("Expected validation error for an unknown timestamp format")
+	}
+}
+
+func TestLoadWithCLI_WatchCrossings(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{WatchCrossings: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.WatchCrossings {
+		t.Error("Expected WatchCrossings to be enabled")
+	}
+}
+
+func TestLoadWithCLI_ExpectedMemoryAnnotation(t *testing.T) {
+	t.Setenv("EXPECTED_MEMORY_ANNOTATION", "env.io/expected")
+
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.ExpectedMemory != "env.io/expected" {
+		t.Errorf("Expected env annotation, got %q", cfg.ExpectedMemory)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{ExpectedMemory: "team.io/expected-memory"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.ExpectedMemory != "team.io/expected-memory" {
+		t.Errorf("Expected CLI annotation to win, got %q", cfg.ExpectedMemory)
+	}
+}
+
+func TestLoadWithCLI_ReportInterval(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{ReportInterval: 5 * time.Minute})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.ReportInterval != 5*time.Minute {
+		t.Errorf("Expected report interval 5m, got %v", cfg.ReportInterval)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{ReportInterval: -time.Second}); err == nil {
+		t.Error("Expected validation error for negative report interval")
+	}
+}
+
+func TestLoadWithCLI_StatusSymbols(t *testing.T) {
+	t.Setenv("SYMBOL_NODATA", "?")
+
+	cfg, err := LoadWithCLI(&CLIConfig{SymbolOK: "OK", SymbolCritical: "!!"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.SymbolOK != "OK" || cfg.SymbolCritical != "!!" {
+		t.Errorf("Expected CLI symbols, got ok=%q critical=%q", cfg.SymbolOK, cfg.SymbolCritical)
+	}
+	if cfg.SymbolWarning != DefaultSymbolWarning {
+		t.Errorf("Expected default warning symbol, got %q", cfg.SymbolWarning)
+	}
+	if cfg.SymbolNoData != "?" {
+		t.Errorf("Expected env no-data symbol, got %q", cfg.SymbolNoData)
+	}
+}
+
+func TestLoadWithCLI_ContainerStats(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{ContainerStats: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.ContainerStats {
+		t.Error("Expected ContainerStats to be enabled")
+	}
+}
+
+func TestLoadWithCLI_ExcludeLabels(t *testing.T) {
+	t.Setenv("EXCLUDE_LABELS", "env=skip")
+
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.ExcludeLabels) != 1 || cfg.ExcludeLabels[0] != "env=skip" {
+		t.Errorf("Expected env exclude labels, got %v", cfg.ExcludeLabels)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{ExcludeLabels: []string{"monitoring.io/ignore=true", "tier=batch"}})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.ExcludeLabels) != 2 {
+		t.Errorf("Expected CLI exclude labels to win, got %v", cfg.ExcludeLabels)
+	}
+
+	for _, invalid := range []string{"monitoring.io/ignore", "=true"} {
+		if _, err := LoadWithCLI(&CLIConfig{ExcludeLabels: []string{invalid}}); err == nil {
+			t.Errorf("Expected validation error for exclude label %q", invalid)
+		}
+	}
+}
+
+func TestLoadWithCLI_HealthAddr(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{HealthAddr: ":8080", CheckInterval: time.Minute, IntervalJitter: 10 * time.Second})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.HealthAddr != ":8080" {
+		t.Errorf("Expected health address :8080, got %q", cfg.HealthAddr)
+	}
+	if got := cfg.ReadinessWindow(); got != 2*time.Minute+10*time.Second {
+		t.Errorf("Expected readiness window 2m10s, got %v", got)
+	}
+}
+
+func TestLoadWithCLI_MaxNamespaces(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{MaxNamespaces: 25})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxNamespaces != 25 {
+		t.Errorf("Expected max namespaces 25, got %d", cfg.MaxNamespaces)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{MaxNamespaces: -1}); err == nil {
+		t.Error("Expected validation error for negative max namespaces")
+	}
+}
+
+func TestLoadWithCLI_MemoryThreshold(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+	}{
+		{"4Gi", 4 * 1024 * 1024 * 1024},
+		{"2048", 2048 * 1024 * 1024}, // deprecated bare MB value
+		{"0", 0},                     // disabled
+		{"", 0},                      // disabled, the default
+	}
+	for _, tt := range tests {
+		cfg, err := LoadWithCLI(&CLIConfig{MemoryThreshold: tt.value})
+		if err != nil {
+			t.Fatalf("LoadWithCLI(%q) failed: %v", tt.value, err)
+		}
+		if got := cfg.MemoryThresholdBytes(); got != tt.expected {
+			t.Errorf("MemoryThresholdBytes() for %q = %d, want %d", tt.value, got, tt.expected)
+		}
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{MemoryThreshold: "lots"}); err == nil {
+		t.Error("Expected validation error for an unparseable memory threshold")
+	}
+}
+
+func TestLoadWithCLI_RefreshMetricsOnly(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{RefreshMetricsOnly: true, PodCacheTTL: 2 * time.Minute})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.RefreshMetricsOnly || cfg.PodCacheTTL != 2*time.Minute {
+		t.Errorf("Expected refresh-metrics-only with a 2m cache, got %v and %v", cfg.RefreshMetricsOnly, cfg.PodCacheTTL)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{RefreshMetricsOnly: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.PodCacheTTL != 5*time.Minute {
+		t.Errorf("Expected default pod cache TTL 5m, got %v", cfg.PodCacheTTL)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{RefreshMetricsOnly: true, PodCacheTTL: time.Hour}); err == nil {
+		t.Error("Expected validation error for a pod cache TTL that leaves pod status stale too long")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{RefreshMetricsOnly: true, PodCacheTTL: -time.Minute}); err == nil {
+		t.Error("Expected validation error for a negative pod cache TTL")
+	}
+}
+
+func TestLoadWithCLI_OnlyProblems(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{OnlyProblems: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.OnlyProblems {
+		t.Error("Expected only-problems to be enabled")
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{OnlyProblems: true, Output: "csv"}); err == nil {
+		t.Error("Expected validation error for only-problems with CSV output")
+	}
+}
+
+func TestLoadWithCLI_WatchChangedOnly(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{WatchChangedOnly: true, Watch: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.WatchChangedOnly {
+		t.Error("Expected changed-only printing to be enabled")
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{WatchChangedOnly: true}); err == nil {
+		t.Error("Expected validation error for changed-only printing without watch")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{WatchChangedOnly: true, Watch: true, Output: "json"}); err == nil {
+		t.Error("Expected validation error for changed-only printing with JSON output")
+	}
+}
+
+func TestLoadWithCLI_CompactPods(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{CompactPods: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.CompactPods {
+		t.Error("Expected compact pods to be enabled")
+	}
+}
+
+func TestLoadWithCLI_CSVTotals(t *testing.T) {
+	if _, err := LoadWithCLI(&CLIConfig{CSVTotals: true, Output: "csv"}); err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+
+	invalid := []*CLIConfig{
+		{CSVTotals: true},
+		{CSVTotals: true, Output: "csv", Watch: true},
+		{CSVTotals: true, Output: "csv", CSVAppendPath: "samples.csv"},
+	}
+	for _, cli := range invalid {
+		if _, err := LoadWithCLI(cli); err == nil {
+			t.Errorf("Expected validation error for %+v", *cli)
+		}
+	}
+}
+
+func TestLoadWithCLI_AnnotationPath(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Annotations: "owner,meta.io/info:$.team"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.Annotations) != 2 || cfg.Annotations[1] != "meta.io/info:$.team" {
+		t.Errorf("Expected annotations with a JSON path, got %v", cfg.Annotations)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{Annotations: "meta.io/info:team"}); err == nil {
+		t.Error("Expected validation error for a JSON path without '$'")
+	}
+}
+
+func TestLoadWithCLI_Strict(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.Strict {
+		t.Error("Expected strict mode to be off by default")
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{Strict: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.Strict {
+		t.Error("Expected --strict to enable strict mode")
+	}
+}
+
+func TestLoadWithCLI_CSVCycle(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Output: OutputFormatCSV, CSVCycle: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.CSVCycle {
+		t.Error("Expected --csv-cycle to enable the cycle column")
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{CSVCycle: true}); err == nil {
+		t.Error("Expected validation error for csv_cycle with table output")
+	}
+}
+
+func TestLoadWithCLI_AnnotationsAsLabels(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{AnnotationsAsLabels: "team, cost-center"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cfg.AnnotationsAsLabels) != 2 || cfg.AnnotationsAsLabels[1] != "cost-center" {
+		t.Errorf("Expected annotations as labels [team cost-center], got %v", cfg.AnnotationsAsLabels)
+	}
+}
+
+func TestLoadWithCLI_LabelSelector(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{LabelSelector: "app=web,tier!=batch"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.LabelSelector != "app=web,tier!=batch" {
+		t.Errorf("Expected label selector app=web,tier!=batch, got %q", cfg.LabelSelector)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{LabelSelector: "app in (web"}); err == nil {
+		t.Error("Expected validation error for an invalid label selector")
+	}
+}
+
+func TestLoadWithCLI_MaxProblems(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxProblems != 0 {
+		t.Errorf("Expected no problem limit by default, got %d", cfg.MaxProblems)
+	}
+
+	cfg, err = LoadWithCLI(&CLIConfig{MaxProblems: 25})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.MaxProblems != 25 {
+		t.Errorf("Expected max problems 25, got %d", cfg.MaxProblems)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{MaxProblems: -1}); err == nil {
+		t.Error("Expected validation error for a negative problem limit")
+	}
+}
+
+func TestLoadWithCLI_Samples(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Samples: 3, SampleInterval: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if cfg.Samples != 3 || cfg.SampleInterval != 2*time.Second {
+		t.Errorf("Expected 3 samples every 2s, got %d every %v", cfg.Samples, cfg.SampleInterval)
+	}
+
+	if _, err := LoadWithCLI(&CLIConfig{Samples: -1}); err == nil {
+		t.Error("Expected validation error for a negative sample count")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{Samples: 3, NoMetrics: true}); err == nil {
+		t.Error("Expected validation error for samples without metrics")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{Watch: true, CheckInterval: 10 * time.Second, Samples: 3, SampleInterval: 5 * time.Second}); err == nil {
+		t.Error("Expected validation error for sampling that does not fit inside the check interval")
+	}
+	if _, err := LoadWithCLI(&CLIConfig{Watch: true, CheckInterval: 30 * time.Second, Samples: 3, SampleInterval: 5 * time.Second}); err != nil {
+		t.Errorf("Expected sampling within the check interval to be valid, got %v", err)
+	}
+}
+
+func TestLoadWithCLI_Explain(t *testing.T) {
+	cfg, err := LoadWithCLI(&CLIConfig{Explain: true})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if !cfg.Explain {
+		t.Error("Expected explain to be enabled")
+	}
+}
@@ -2,50 +2,250 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	// Kubernetes configuration
 	Namespace     string
-	AllNamespaces bool // true if monitoring all namespaces explicitly
+	AllNamespaces bool   // true if monitoring all namespaces explicitly
+	PodName       string // Single pod to monitor within Namespace (optional)
+	NodeName      string // Only monitor pods scheduled on this node, across namespaces (optional)
+	LabelSelector string // Only monitor pods matching this label selector, e.g. app=web,tier!=batch (optional)
 	KubeConfig    string
 	InCluster     bool
+	Contexts      []string // Kubeconfig contexts to collect from and merge into one report (optional)
+	MaxNamespaces int      // Stop an all-namespaces collection after this many namespaces (0 for no limit)
+	Strict        bool     // true to fail the cycle when any namespace cannot be listed
+
+	MetricsAPIGroup string // API group serving pod metrics, empty for metrics.k8s.io
+	NoMetrics       bool   // true to skip metrics and report requests and limits only
 
 	// Monitoring configuration
-	CheckInterval        time.Duration
-	MemoryThresholdMB    int64
-	MemoryWarningPercent float64
-	Watch                bool // true for continuous monitoring, false for single check
+	CheckInterval           time.Duration
+	MemoryThreshold         string             // Absolute usage that flags a pod, as a quantity (bare numbers are MiB; empty or 0 disables)
+	MemoryThresholdQuantity *resource.Quantity // MemoryThreshold parsed by validation, nil when disabled
+	MemoryWarningPercent    float64
+	PrimaryMetric           string        // Percentage compared with MemoryWarningPercent (request, limit)
+	Watch                   bool          // true for continuous monitoring, false for single check
+	WatchNamespaceEvents    bool          // true to log pods added or removed between cycles
+	WatchCrossings          bool          // true to report pods whose memory status changed since the previous cycle
+	WatchChangedOnly        bool          // true to print only pods whose status or usage bucket changed after the first report
+	IntervalJitter          time.Duration // Random offset up to this value added to each check interval
+	MaxMetricsAge           time.Duration // Usage samples older than this are flagged and de-prioritized (0 disables)
+	ReportInterval          time.Duration // Minimum time between printed reports in watch mode (0 prints every cycle)
+	RefreshMetricsOnly      bool          // true to reuse cached pod specs between cycles, re-listing only metrics
+	PodCacheTTL             time.Duration // Age after which cached pod specs are listed again with RefreshMetricsOnly
+	Samples                 int           // Metrics reads averaged per cycle (0 or 1 reads once)
+	SampleInterval          time.Duration // Wait between metrics reads when Samples is above 1
+
+	// API retry configuration
+	MaxRetries   int           // Retries for transient API errors (0 disables retrying)
+	RetryBackoff time.Duration // Initial backoff between retries, doubled on each attempt
+	ListPageSize int64         // Pods requested per list call (0 lists each namespace in one call)
 
 	// Logging configuration
 	LogLevel  string
 	LogFormat string
 
 	// Display configuration
-	Labels      []string // Labels to display for each pod
-	Annotations []string // Annotations to display for each pod
-	Output      string   // Output format (table, csv)
+	Labels              []string // Labels to display for each pod
+	Annotations         []string // Annotations to display for each pod
+	EnvVars             []string // Container environment variables to display, literal values only
+	AnnotationsAsLabels []string // Annotations merged into pod labels; labels win when keys clash
+	ExpectedMemory      string   // Annotation holding each pod's expected memory, compared with its usage
+	SymbolOK            string   // Symbol for running, ready pods
+	SymbolWarning       string   // Symbol for pending pods
+	SymbolCritical      string   // Symbol for failed or not ready pods
+	SymbolNoData        string   // Symbol for pods without usage metrics
+	SymbolCompleted     string   // Symbol for pods that ran to completion, such as finished Jobs
+
+	// Container selection
+	Containers        []string // Only report these container names (empty means all)
+	ExcludeContainers []string // Container names to skip
+	SystemContainers  []string // Name patterns of pause/sandbox containers, skipped unless IncludeSystem is set
+	IncludeSystem     bool     // true to report system containers and count them in pod usage
+	ExcludeLabels     []string // "key=value" labels whose pods are left out of the listing, still counted in totals
+	IncludePhases     []string // Pod phases shown in the report (empty means all)
+	Output            string   // Output format (table, csv, json)
+	Quiet             bool     // true to print only the report, skipping the analysis section
+	SortBy            string   // Pod ordering (name, headroom)
+	SortOrder         string   // Sort direction (asc, desc), empty for the natural direction of SortBy
+	SortContainers    bool     // true to list each pod's containers by usage, largest first
+	GroupBy           string   // Aggregated report printed after the pods (node), empty for none
+	GroupByLabel      string   // Pod label whose values usage, requests and limits are summed by, empty for none
+	Color             string   // Color mode (auto, always, never)
+	PercentPrecision  int      // Decimals shown for percentages in table output
+	Units             string   // Memory units for display (binary, decimal, or empty for historical labels)
+	TimestampFormat   string   // Timestamp format for CSV and table output (rfc3339, epoch, epochmillis)
+	UseColor          bool     // Resolved at startup from Color, output format and TTY detection
+
+	WatchStatusOnly  bool // true to refresh a compact count-only view in place instead of the full report
+	ProblemsOnly     bool // true to emit only structured problems (JSON output)
+	OnlyProblems     bool // true to list only pods with a memory problem in the detailed report (table output)
+	DedupeProblems   bool // true to collapse identical problems of a workload's replicas into one entry
+	MaxProblems      int  // Problems listed at most, critical ones first (0 for no limit)
+	SummaryOnly      bool // true to emit only the summary and risk counts (JSON output)
+	ShowEfficiency   bool // true to print the per-namespace request efficiency report
+	ShowImages       bool // true to display container images
+	ShowRequestRatio bool // true to show each container's request as a percent of its limit
+	ContainerStats   bool // true to show each pod's max, mean and p95 container usage
+	CompactPods      bool // true to print one line per pod, without container, stats or metadata lines
+	Explain          bool // true to append the rule that decided each pod's memory status to its line
+	SummaryOnExit    bool // true to print totals accumulated over the session when the watch loop stops
+
+	// Request right-sizing
+	SuggestRequests bool    // true to suggest memory requests from observed usage
+	SuggestFactor   float64 // Multiplier applied to usage when suggesting a request
+	SuggestRoundTo  string  // Quantity suggestions are rounded up to (e.g. 32Mi)
+
+	CompareRequests bool    // true to print the over/under-provisioning report
+	RightSizingLow  float64 // Usage/request below this is over-provisioned
+	RightSizingHigh float64 // Usage/request above this is under-provisioned
+
+	CSVAppendPath string // File each cycle's CSV rows are appended to, kept across restarts (empty disables)
+	CSVTotals     bool   // true to end a single-run CSV report with a row of summed bytes
+	CSVCycle      bool   // true to end each CSV row with the number of the cycle that produced it
+
+	// Report file, written instead of stdout for CSV and JSON output (empty disables)
+	OutputFile     string
+	RotateSize     string        // Size after which the file is rotated, as a quantity such as 100MB (empty disables)
+	RotateInterval time.Duration // Age after which the file is rotated (0 disables)
+
+	// Notification configuration
+	SlackWebhookURL string        // Slack incoming webhook for new critical pods (empty disables)
+	WebhookURL      string        // Endpoint receiving each report as JSON (empty disables)
+	WebhookHeaders  []string      // Extra "Name: value" headers sent with webhook requests
+	RequestTimeout  time.Duration // Timeout for outgoing webhook requests (0 disables)
+
+	HealthAddr string // Address serving /healthz and /readyz probes, e.g. :8080 (empty disables)
+}
+
+// validPhases lists the pod phases accepted by IncludePhases
+var validPhases = map[string]bool{
+	PhasePending:   true,
+	PhaseRunning:   true,
+	PhaseSucceeded: true,
+	PhaseFailed:    true,
+	PhaseUnknown:   true,
+}
+
+// maxPercentPrecision caps the decimals shown for percentages
+const maxPercentPrecision = 6
+
+// maxPodCacheTTL caps how long refreshed cycles reuse cached pods, whose phase, readiness and restart
+// counts stay those of the last pod listing
+const maxPodCacheTTL = 15 * time.Minute
+
+// logLevels maps the supported log level names to their slog levels
+var logLevels = map[string]slog.Level{
+	LogLevelDebug: slog.LevelDebug,
+	LogLevelInfo:  slog.LevelInfo,
+	LogLevelWarn:  slog.LevelWarn,
+	LogLevelError: slog.LevelError,
 }
 
 // CLIConfig holds command line argument values
 type CLIConfig struct {
 	Namespace            string
 	AllNamespaces        bool
+	PodName              string
+	NodeName             string
+	LabelSelector        string // Kubernetes label selector pods must match
 	KubeConfig           string
 	InCluster            bool
+	Contexts             string // Comma-separated list of kubeconfig contexts
+	MaxNamespaces        int    // Namespaces collected at most with all namespaces
+	Strict               bool   // true to fail on any namespace collection error
+	MetricsAPIGroup      string
+	NoMetrics            bool // true to collect without reading pod metrics
 	CheckInterval        time.Duration
-	MemoryThresholdMB    int64
+	MemoryThreshold      string
 	MemoryWarningPercent float64
+	PrimaryMetric        string
 	Watch                bool // true for continuous monitoring, false for single check
+	WatchNamespaceEvents bool // true to log pods added or removed between cycles
+	WatchCrossings       bool // true to report memory status transitions between cycles
+	WatchChangedOnly     bool // true to print only changed pods after the first watch report
+	IntervalJitter       time.Duration
+	MaxMetricsAge        time.Duration
+	ReportInterval       time.Duration
+	RefreshMetricsOnly   bool // true to re-list only metrics while cached pod specs are fresh
+	PodCacheTTL          time.Duration
+	Samples              int // Metrics reads averaged per cycle
+	SampleInterval       time.Duration
+	MaxRetries           *int // Retries for transient API errors (nil keeps the default)
+	RetryBackoff         time.Duration
+	ListPageSize         int64
 	LogLevel             string
-	Labels               string // Comma-separated list of labels to display
-	Annotations          string // Comma-separated list of annotations to display
-	Output               string // Output format (table, csv)
+	LogFormat            string
+	Labels               string   // Comma-separated list of labels to display
+	Annotations          string   // Comma-separated list of annotations to display
+	EnvVars              string   // Comma-separated list of container environment variables to display
+	AnnotationsAsLabels  string   // Comma-separated list of annotations treated as labels
+	ExpectedMemory       string   // Annotation holding each pod's expected memory
+	SymbolOK             string   // Symbol for running, ready pods
+	SymbolWarning        string   // Symbol for pending pods
+	SymbolCritical       string   // Symbol for failed or not ready pods
+	SymbolNoData         string   // Symbol for pods without usage metrics
+	SymbolCompleted      string   // Symbol for completed pods
+	Containers           string   // Comma-separated list of container names to report
+	ExcludeContainers    string   // Comma-separated list of container names to skip
+	SystemContainers     string   // Comma-separated list of pause/sandbox container name patterns
+	IncludeSystem        bool     // true to keep system containers
+	ExcludeLabels        []string // "key=value" labels whose pods are skipped (repeatable)
+	IncludePhases        string   // Comma-separated list of pod phases to show
+	Output               string   // Output format (table, csv, json)
+	Quiet                bool     // true to print only the report, skipping the analysis section
+	SortBy               string   // Pod ordering (name, headroom)
+	SortOrder            string   // Sort direction (asc, desc)
+	SortContainers       bool     // true to list containers by usage, largest first
+	GroupBy              string   // Aggregated report to print (node)
+	GroupByLabel         string   // Pod label to sum memory by (e.g., team)
+	Color                string   // Color mode (auto, always, never)
+	PercentPrecision     *int     // Decimals shown for percentages (nil keeps the default)
+	Units                string   // Memory units for display (binary, decimal)
+	TimestampFormat      string   // Timestamp format (rfc3339, epoch, epochmillis)
+	WatchStatusOnly      bool     // true to refresh a compact count-only view in place
+	ProblemsOnly         bool     // true to emit only structured problems (JSON output)
+	OnlyProblems         bool     // true to list only pods with a memory problem (table output)
+	DedupeProblems       bool     // true to collapse identical problems across workload replicas
+	MaxProblems          int      // Problems listed at most
+	SummaryOnly          bool     // true to emit only the summary and risk counts (JSON output)
+	ShowEfficiency       bool     // true to print the per-namespace request efficiency report
+	ShowImages           bool     // true to display container images
+	ShowRequestRatio     bool     // true to show container request as percent of limit
+	ContainerStats       bool     // true to show max, mean and p95 container usage per pod
+	CompactPods          bool     // true to print one line per pod
+	Explain              bool     // true to show why each pod got its memory status
+	SummaryOnExit        bool     // true to print session totals on shutdown
+	SuggestRequests      bool     // true to suggest memory requests from observed usage
+	SuggestFactor        float64  // Multiplier applied to usage when suggesting a request
+	SuggestRoundTo       string   // Quantity suggestions are rounded up to
+	CompareRequests      bool     // true to print the over/under-provisioning report
+	RightSizingLow       float64  // Usage/request below this is over-provisioned
+	RightSizingHigh      float64  // Usage/request above this is under-provisioned
+	CSVAppendPath        string   // File each cycle's CSV rows are appended to
+	CSVTotals            bool     // true to end a single-run CSV report with a totals row
+	CSVCycle             bool     // true to add a cycle column to CSV rows
+	OutputFile           string   // File CSV or JSON reports are written to instead of stdout
+	RotateSize           string   // Size after which the output file is rotated (e.g. 100MB)
+	SlackWebhookURL      string   // Slack incoming webhook for new critical pods
+	WebhookURL           string   // Endpoint receiving each report as JSON
+	WebhookHeaders       []string // Extra "Name: value" headers sent with webhook requests
+	HealthAddr           string   // Address serving /healthz and /readyz probes
+	RequestTimeout       time.Duration
+	RotateInterval       time.Duration
 }
 
 // Load loads configuration from environment variables with sensible defaults
@@ -69,17 +269,89 @@ func defaultConfigFromEnv() *Config {
 	return &Config{
 		Namespace:            getEnv("NAMESPACE", ""),
 		AllNamespaces:        getEnvBool("ALL_NAMESPACES", false),
+		NodeName:             getEnv("NODE", ""),
+		LabelSelector:        getEnv("LABEL_SELECTOR", ""),
 		KubeConfig:           getEnv("KUBECONFIG", ""),
 		InCluster:            getEnvBool("IN_CLUSTER", false),
+		Contexts:             parseCommaSeparated(getEnv("KUBE_CONTEXTS", "")),
+		MetricsAPIGroup:      getEnv("METRICS_API_GROUP", ""),
+		NoMetrics:            getEnvBool("NO_METRICS", false),
 		CheckInterval:        getEnvDuration("CHECK_INTERVAL", "30s"),
-		MemoryThresholdMB:    getEnvInt64("MEMORY_THRESHOLD_MB", 1024),
+		MemoryThreshold:      getEnv("MEMORY_THRESHOLD", getEnv("MEMORY_THRESHOLD_MB", "")),
 		MemoryWarningPercent: getEnvFloat("MEMORY_WARNING_PERCENT", 80.0),
+		PrimaryMetric:        getEnv("PRIMARY_METRIC", PrimaryMetricRequest),
 		Watch:                getEnvBool("WATCH", false),
+		WatchNamespaceEvents: getEnvBool("WATCH_NAMESPACE_EVENTS", false),
+		WatchCrossings:       getEnvBool("WATCH_THRESHOLD_CROSSINGS", false),
+		WatchChangedOnly:     getEnvBool("WATCH_PODS_CHANGED_ONLY", false),
+		IntervalJitter:       getEnvDuration("INTERVAL_JITTER", "0s"),
+		MaxMetricsAge:        getEnvDuration("MAX_METRICS_AGE", "0s"),
+		ReportInterval:       getEnvDuration("REPORT_INTERVAL", "0s"),
+		RefreshMetricsOnly:   getEnvBool("REFRESH_METRICS_ONLY", false),
+		PodCacheTTL:          getEnvDuration("POD_CACHE_TTL", "5m"),
+		Samples:              int(getEnvInt64("SAMPLES", 1)),
+		SampleInterval:       getEnvDuration("SAMPLE_INTERVAL", "5s"),
+		MaxRetries:           int(getEnvInt64("MAX_RETRIES", 2)),
+		RetryBackoff:         getEnvDuration("RETRY_BACKOFF", "500ms"),
+		ListPageSize:         getEnvInt64("LIST_PAGE_SIZE", 500),
+		MaxNamespaces:        int(getEnvInt64("MAX_NAMESPACES", 0)),
+		Strict:               getEnvBool("STRICT", false),
 		LogLevel:             getEnv("LOG_LEVEL", "info"),
 		LogFormat:            getEnv("LOG_FORMAT", "json"),
-		Labels:               parseCommaSeparated(getEnv("LABELS", "")),
-		Annotations:          parseCommaSeparated(getEnv("ANNOTATIONS", "")),
+		Labels:               getEnvExpandedList("LABELS"),
+		Annotations:          getEnvExpandedList("ANNOTATIONS"),
+		EnvVars:              parseCommaSeparated(getEnv("ENV_VARS", "")),
+		AnnotationsAsLabels:  getEnvExpandedList("ANNOTATIONS_AS_LABELS"),
+		ExpectedMemory:       getEnv("EXPECTED_MEMORY_ANNOTATION", ""),
+		SymbolOK:             getEnv("SYMBOL_OK", DefaultSymbolOK),
+		SymbolWarning:        getEnv("SYMBOL_WARNING", DefaultSymbolWarning),
+		SymbolCritical:       getEnv("SYMBOL_CRITICAL", DefaultSymbolCritical),
+		SymbolNoData:         getEnv("SYMBOL_NODATA", DefaultSymbolNoData),
+		SymbolCompleted:      getEnv("SYMBOL_COMPLETED", DefaultSymbolCompleted),
+		Containers:           parseCommaSeparated(getEnv("CONTAINERS", "")),
+		ExcludeContainers:    parseCommaSeparated(getEnv("EXCLUDE_CONTAINERS", "")),
+		SystemContainers:     parseCommaSeparated(getEnv("SYSTEM_CONTAINERS", DefaultSystemContainers)),
+		IncludeSystem:        getEnvBool("INCLUDE_SYSTEM_CONTAINERS", false),
+		ExcludeLabels:        getEnvExpandedList("EXCLUDE_LABELS"),
+		IncludePhases:        parsePhases(getEnv("INCLUDE_PHASES", "Running,Pending")),
 		Output:               getEnv("OUTPUT", "table"),
+		Quiet:                getEnvBool("QUIET", false),
+		ShowImages:           getEnvBool("SHOW_IMAGES", false),
+		DedupeProblems:       getEnvBool("DEDUPE_PROBLEMS", false),
+		MaxProblems:          int(getEnvInt64("MAX_PROBLEMS", 0)),
+		OnlyProblems:         getEnvBool("ONLY_PROBLEMS", false),
+		SummaryOnly:          getEnvBool("SUMMARY_ONLY", false),
+		ShowRequestRatio:     getEnvBool("REQUEST_AS_PERCENT_OF_LIMIT", false),
+		ContainerStats:       getEnvBool("CONTAINER_STATS", false),
+		CompactPods:          getEnvBool("COMPACT_PODS", false),
+		Explain:              getEnvBool("EXPLAIN", false),
+		SummaryOnExit:        getEnvBool("SUMMARY_ON_EXIT", false),
+		SuggestRequests:      getEnvBool("SUGGEST_REQUESTS", false),
+		SuggestFactor:        getEnvFloat("SUGGEST_HEADROOM_FACTOR", 1.2),
+		SuggestRoundTo:       getEnv("SUGGEST_ROUND_TO", "32Mi"),
+		CompareRequests:      getEnvBool("COMPARE_REQUESTS", false),
+		RightSizingLow:       getEnvFloat("RIGHTSIZING_LOW_PERCENT", 50.0),
+		RightSizingHigh:      getEnvFloat("RIGHTSIZING_HIGH_PERCENT", 90.0),
+		SortBy:               getEnv("SORT_BY", SortByName),
+		SortOrder:            getEnv("SORT_ORDER", ""),
+		SortContainers:       getEnvBool("SORT_CONTAINERS", false),
+		GroupBy:              getEnv("GROUP_BY", ""),
+		GroupByLabel:         getEnv("GROUP_BY_LABEL", ""),
+		Color:                getEnv("COLOR", ColorAuto),
+		PercentPrecision:     int(getEnvInt64("PERCENT_PRECISION", 1)),
+		Units:                getEnv("UNITS", ""),
+		TimestampFormat:      getEnv("TIMESTAMP_FORMAT", TimestampRFC3339),
+		CSVAppendPath:        getEnv("CSV_APPEND", ""),
+		CSVTotals:            getEnvBool("CSV_TOTALS", false),
+		CSVCycle:             getEnvBool("CSV_CYCLE", false),
+		OutputFile:           getEnv("OUTPUT_FILE", ""),
+		RotateSize:           getEnv("ROTATE_SIZE", ""),
+		RotateInterval:       getEnvDuration("ROTATE_INTERVAL", "0s"),
+		SlackWebhookURL:      getEnv("SLACK_WEBHOOK_URL", ""),
+		WebhookURL:           getEnv("WEBHOOK_URL", ""),
+		WebhookHeaders:       webhookHeadersFromEnv(),
+		RequestTimeout:       getEnvDuration("REQUEST_TIMEOUT", "10s"),
+		HealthAddr:           getEnv("HEALTH_ADDR", ""),
 	}
 }
 
@@ -93,6 +365,8 @@ func applyCLIOverrides(cfg *Config, cli *CLIConfig) {
 	overrideMonitoring(cfg, cli)
 	overrideLogging(cfg, cli)
 	overrideDisplay(cfg, cli)
+	overrideSymbols(cfg, cli)
+	overrideNotifications(cfg, cli)
 }
 
 func overrideNamespace(cfg *Config, cli *CLIConfig) {
@@ -102,6 +376,15 @@ func overrideNamespace(cfg *Config, cli *CLIConfig) {
 	if cli.AllNamespaces {
 		cfg.AllNamespaces = true
 	}
+	if cli.PodName != "" {
+		cfg.PodName = cli.PodName
+	}
+	if cli.NodeName != "" {
+		cfg.NodeName = cli.NodeName
+	}
+	if cli.LabelSelector != "" {
+		cfg.LabelSelector = cli.LabelSelector
+	}
 }
 
 func overrideKubeConfig(cfg *Config, cli *CLIConfig) {
@@ -111,35 +394,113 @@ func overrideKubeConfig(cfg *Config, cli *CLIConfig) {
 	if cli.InCluster {
 		cfg.InCluster = true
 	}
+	if cli.Contexts != "" {
+		cfg.Contexts = parseCommaSeparated(cli.Contexts)
+	}
+	if cli.MaxNamespaces != 0 {
+		cfg.MaxNamespaces = cli.MaxNamespaces
+	}
+	if cli.Strict {
+		cfg.Strict = true
+	}
+	if cli.MetricsAPIGroup != "" {
+		cfg.MetricsAPIGroup = cli.MetricsAPIGroup
+	}
+	if cli.NoMetrics {
+		cfg.NoMetrics = true
+	}
 }
 
 func overrideIntervals(cfg *Config, cli *CLIConfig) {
 	if cli.CheckInterval != 0 {
 		cfg.CheckInterval = cli.CheckInterval
 	}
-	if cli.MemoryThresholdMB != 0 {
-		cfg.MemoryThresholdMB = cli.MemoryThresholdMB
+	if cli.IntervalJitter != 0 {
+		cfg.IntervalJitter = cli.IntervalJitter
+	}
+	if cli.MaxMetricsAge != 0 {
+		cfg.MaxMetricsAge = cli.MaxMetricsAge
+	}
+	if cli.ReportInterval != 0 {
+		cfg.ReportInterval = cli.ReportInterval
+	}
+	if cli.PodCacheTTL != 0 {
+		cfg.PodCacheTTL = cli.PodCacheTTL
+	}
+	if cli.Samples != 0 {
+		cfg.Samples = cli.Samples
+	}
+	if cli.SampleInterval != 0 {
+		cfg.SampleInterval = cli.SampleInterval
+	}
+	if cli.MemoryThreshold != "" {
+		cfg.MemoryThreshold = cli.MemoryThreshold
 	}
 	if cli.MemoryWarningPercent != 0 {
 		cfg.MemoryWarningPercent = cli.MemoryWarningPercent
 	}
+	if cli.PrimaryMetric != "" {
+		cfg.PrimaryMetric = cli.PrimaryMetric
+	}
 }
 
 func overrideMonitoring(cfg *Config, cli *CLIConfig) {
 	if cli.Watch {
 		cfg.Watch = true
 	}
+	if cli.WatchNamespaceEvents {
+		cfg.WatchNamespaceEvents = true
+	}
+	if cli.WatchCrossings {
+		cfg.WatchCrossings = true
+	}
+	if cli.WatchChangedOnly {
+		cfg.WatchChangedOnly = true
+	}
+	if cli.RefreshMetricsOnly {
+		cfg.RefreshMetricsOnly = true
+	}
+	if cli.MaxRetries != nil {
+		cfg.MaxRetries = *cli.MaxRetries
+	}
+	if cli.RetryBackoff != 0 {
+		cfg.RetryBackoff = cli.RetryBackoff
+	}
+	if cli.ListPageSize != 0 {
+		cfg.ListPageSize = cli.ListPageSize
+	}
 }
 
 func overrideLogging(cfg *Config, cli *CLIConfig) {
 	if cli.LogLevel != "" {
 		cfg.LogLevel = cli.LogLevel
 	}
+	if cli.LogFormat != "" {
+		cfg.LogFormat = cli.LogFormat
+	}
 	if cli.Output != "" {
 		cfg.Output = cli.Output
 	}
 }
 
+func overrideSymbols(cfg *Config, cli *CLIConfig) {
+	if cli.SymbolOK != "" {
+		cfg.SymbolOK = cli.SymbolOK
+	}
+	if cli.SymbolWarning != "" {
+		cfg.SymbolWarning = cli.SymbolWarning
+	}
+	if cli.SymbolCritical != "" {
+		cfg.SymbolCritical = cli.SymbolCritical
+	}
+	if cli.SymbolNoData != "" {
+		cfg.SymbolNoData = cli.SymbolNoData
+	}
+	if cli.SymbolCompleted != "" {
+		cfg.SymbolCompleted = cli.SymbolCompleted
+	}
+}
+
 func overrideDisplay(cfg *Config, cli *CLIConfig) {
 	if cli.Labels != "" {
 		cfg.Labels = parseCommaSeparated(cli.Labels)
@@ -147,6 +508,165 @@ func overrideDisplay(cfg *Config, cli *CLIConfig) {
 	if cli.Annotations != "" {
 		cfg.Annotations = parseCommaSeparated(cli.Annotations)
 	}
+	if cli.EnvVars != "" {
+		cfg.EnvVars = parseCommaSeparated(cli.EnvVars)
+	}
+	if cli.AnnotationsAsLabels != "" {
+		cfg.AnnotationsAsLabels = parseCommaSeparated(cli.AnnotationsAsLabels)
+	}
+	if cli.ExpectedMemory != "" {
+		cfg.ExpectedMemory = cli.ExpectedMemory
+	}
+	if cli.Containers != "" {
+		cfg.Containers = parseCommaSeparated(cli.Containers)
+	}
+	if cli.ExcludeContainers != "" {
+		cfg.ExcludeContainers = parseCommaSeparated(cli.ExcludeContainers)
+	}
+	if cli.SystemContainers != "" {
+		cfg.SystemContainers = parseCommaSeparated(cli.SystemContainers)
+	}
+	if cli.IncludeSystem {
+		cfg.IncludeSystem = true
+	}
+	if len(cli.ExcludeLabels) > 0 {
+		cfg.ExcludeLabels = cli.ExcludeLabels
+	}
+	if cli.IncludePhases != "" {
+		cfg.IncludePhases = parsePhases(cli.IncludePhases)
+	}
+	if cli.Quiet {
+		cfg.Quiet = true
+	}
+	if cli.SortBy != "" {
+		cfg.SortBy = cli.SortBy
+	}
+	if cli.SortOrder != "" {
+		cfg.SortOrder = cli.SortOrder
+	}
+	if cli.SortContainers {
+		cfg.SortContainers = true
+	}
+	if cli.GroupBy != "" {
+		cfg.GroupBy = cli.GroupBy
+	}
+	if cli.GroupByLabel != "" {
+		cfg.GroupByLabel = cli.GroupByLabel
+	}
+	if cli.Color != "" {
+		cfg.Color = cli.Color
+	}
+	if cli.PercentPrecision != nil {
+		cfg.PercentPrecision = *cli.PercentPrecision
+	}
+	if cli.Units != "" {
+		cfg.Units = cli.Units
+	}
+	if cli.TimestampFormat != "" {
+		cfg.TimestampFormat = cli.TimestampFormat
+	}
+	if cli.WatchStatusOnly {
+		cfg.WatchStatusOnly = true
+	}
+	if cli.ProblemsOnly {
+		cfg.ProblemsOnly = true
+	}
+	if cli.OnlyProblems {
+		cfg.OnlyProblems = true
+	}
+	if cli.DedupeProblems {
+		cfg.DedupeProblems = true
+	}
+	if cli.MaxProblems != 0 {
+		cfg.MaxProblems = cli.MaxProblems
+	}
+	if cli.SummaryOnly {
+		cfg.SummaryOnly = true
+	}
+	if cli.ShowEfficiency {
+		cfg.ShowEfficiency = true
+	}
+	if cli.ShowImages {
+		cfg.ShowImages = true
+	}
+	if cli.ShowRequestRatio {
+		cfg.ShowRequestRatio = true
+	}
+	if cli.ContainerStats {
+		cfg.ContainerStats = true
+	}
+	if cli.CompactPods {
+		cfg.CompactPods = true
+	}
+	if cli.Explain {
+		cfg.Explain = true
+	}
+	if cli.SummaryOnExit {
+		cfg.SummaryOnExit = true
+	}
+	if cli.SuggestRequests {
+		cfg.SuggestRequests = true
+	}
+	if cli.SuggestFactor != 0 {
+		cfg.SuggestFactor = cli.SuggestFactor
+	}
+	if cli.SuggestRoundTo != "" {
+		cfg.SuggestRoundTo = cli.SuggestRoundTo
+	}
+	if cli.CompareRequests {
+		cfg.CompareRequests = true
+	}
+	if cli.RightSizingLow != 0 {
+		cfg.RightSizingLow = cli.RightSizingLow
+	}
+	if cli.RightSizingHigh != 0 {
+		cfg.RightSizingHigh = cli.RightSizingHigh
+	}
+}
+
+func overrideNotifications(cfg *Config, cli *CLIConfig) {
+	if cli.CSVAppendPath != "" {
+		cfg.CSVAppendPath = cli.CSVAppendPath
+	}
+	if cli.CSVTotals {
+		cfg.CSVTotals = true
+	}
+	if cli.CSVCycle {
+		cfg.CSVCycle = true
+	}
+	if cli.OutputFile != "" {
+		cfg.OutputFile = cli.OutputFile
+	}
+	if cli.RotateSize != "" {
+		cfg.RotateSize = cli.RotateSize
+	}
+	if cli.RotateInterval != 0 {
+		cfg.RotateInterval = cli.RotateInterval
+	}
+	if cli.SlackWebhookURL != "" {
+		cfg.SlackWebhookURL = cli.SlackWebhookURL
+	}
+	if cli.WebhookURL != "" {
+		cfg.WebhookURL = cli.WebhookURL
+	}
+	if len(cli.WebhookHeaders) > 0 {
+		cfg.WebhookHeaders = cli.WebhookHeaders
+	}
+	if cli.RequestTimeout != 0 {
+		cfg.RequestTimeout = cli.RequestTimeout
+	}
+	if cli.HealthAddr != "" {
+		cfg.HealthAddr = cli.HealthAddr
+	}
+}
+
+// webhookHeadersFromEnv reads a single "Name: value" header from WEBHOOK_HEADER
+// Header values may contain commas, so a list is not supported here
+func webhookHeadersFromEnv() []string {
+	if header := getEnv("WEBHOOK_HEADER", ""); header != "" {
+		return []string{header}
+	}
+	return nil
 }
 
 func applyDefaultNamespace(cfg *Config) {
@@ -157,25 +677,371 @@ func applyDefaultNamespace(cfg *Config) {
 
 // validate checks that the configuration is valid
 func (c *Config) validate() error {
+	if c.PodName != "" && (c.Namespace == "" || c.AllNamespaces) {
+		return fmt.Errorf("pod requires a specific namespace and cannot be used with all_namespaces")
+	}
+
+	if c.NodeName != "" && c.Namespace != "" {
+		return fmt.Errorf("node cannot be combined with namespace")
+	}
+
+	if len(c.Contexts) > 0 && c.InCluster {
+		return fmt.Errorf("contexts cannot be combined with in_cluster")
+	}
+
 	if c.CheckInterval <= 0 {
 		return fmt.Errorf("check_interval must be positive")
 	}
 
-	if c.MemoryThresholdMB <= 0 {
-		return fmt.Errorf("memory_threshold_mb must be positive")
+	if c.MaxNamespaces < 0 {
+		return fmt.Errorf("max_namespaces must not be negative")
+	}
+
+	if c.MaxProblems < 0 {
+		return fmt.Errorf("max_problems must not be negative")
+	}
+
+	if c.IntervalJitter < 0 {
+		return fmt.Errorf("interval_jitter must not be negative")
+	}
+
+	if c.MaxMetricsAge < 0 {
+		return fmt.Errorf("max_metrics_age must not be negative")
+	}
+
+	if c.ReportInterval < 0 {
+		return fmt.Errorf("report_interval must not be negative")
+	}
+
+	if c.Samples < 0 {
+		return fmt.Errorf("samples must not be negative")
+	}
+
+	if c.Samples > 1 && c.SampleInterval <= 0 {
+		return fmt.Errorf("sample_interval must be positive when samples is above 1")
+	}
+
+	if c.Samples > 1 && c.NoMetrics {
+		return fmt.Errorf("samples cannot be combined with no_metrics")
+	}
+
+	// Sampling waits (samples-1) intervals every cycle, which must leave time for the cycle itself
+	if c.Watch && c.Samples > 1 && time.Duration(c.Samples-1)*c.SampleInterval >= c.CheckInterval {
+		return fmt.Errorf("samples waiting sample_interval between reads must fit inside check_interval")
+	}
+
+	if c.RefreshMetricsOnly && c.PodCacheTTL <= 0 {
+		return fmt.Errorf("pod_cache_ttl must be positive when refresh_metrics_only is set")
+	}
+
+	if c.RefreshMetricsOnly && c.PodCacheTTL > maxPodCacheTTL {
+		return fmt.Errorf("pod_cache_ttl must be at most %s, since cached pods keep their last listed phase, readiness and restarts", maxPodCacheTTL)
+	}
+
+	if c.RefreshMetricsOnly && c.NoMetrics {
+		return fmt.Errorf("refresh_metrics_only cannot be combined with no_metrics")
+	}
+
+	// The parsed threshold is kept so the analysis does not parse it again for every pod
+	c.MemoryThresholdQuantity = nil
+	if c.MemoryThreshold != "" {
+		q, err := ParseMemoryThreshold(c.MemoryThreshold)
+		if err != nil || q.Sign() < 0 {
+			return fmt.Errorf("memory_threshold must be a non-negative quantity (e.g. 2Gi, or a number of MB)")
+		}
+		if !q.IsZero() {
+			c.MemoryThresholdQuantity = &q
+		}
 	}
 
 	if c.MemoryWarningPercent <= 0 || c.MemoryWarningPercent > 100 {
 		return fmt.Errorf("memory_warning_percent must be between 0 and 100")
 	}
 
-	if c.Output != "table" && c.Output != "csv" {
-		return fmt.Errorf("output must be either 'table' or 'csv'")
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative")
+	}
+
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("retry_backoff must not be negative")
+	}
+
+	if c.ListPageSize < 0 {
+		return fmt.Errorf("list_page_size must not be negative")
+	}
+
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("request_timeout must not be negative")
+	}
+
+	for _, h := range c.WebhookHeaders {
+		if name, _, ok := strings.Cut(h, ":"); !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("webhook_header %q must have the form 'Name: value'", h)
+		}
+	}
+
+	if _, err := labels.Parse(c.LabelSelector); err != nil {
+		return fmt.Errorf("label_selector is invalid: %w", err)
+	}
+
+	for _, label := range c.ExcludeLabels {
+		if key, _, ok := strings.Cut(label, "="); !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("exclude_labels entry %q must have the form 'key=value'", label)
+		}
+	}
+
+	for _, annotation := range c.Annotations {
+		if _, path, ok := strings.Cut(annotation, ":"); ok && !strings.HasPrefix(path, "$") {
+			return fmt.Errorf("annotations entry %q must use a JSON path starting with '$' after ':'", annotation)
+		}
+	}
+
+	for _, phase := range c.IncludePhases {
+		if !validPhases[phase] {
+			return fmt.Errorf("include_phases contains unknown phase %q (valid: Pending, Running, Succeeded, Failed, Unknown)", phase)
+		}
+	}
+
+	if !c.IsTableOutput() && c.Output != OutputFormatCSV && c.Output != OutputFormatJSON {
+		return fmt.Errorf("output must be one of 'table', 'table-wide', 'csv' or 'json'")
+	}
+
+	if c.ProblemsOnly && c.Output != OutputFormatJSON {
+		return fmt.Errorf("problems_only requires output 'json'")
+	}
+
+	if c.CSVTotals && c.Output != OutputFormatCSV {
+		return fmt.Errorf("csv_totals requires output 'csv'")
+	}
+
+	// A totals row only sums a complete report; streamed or appended rows have no last cycle to close
+	if c.CSVTotals && (c.Watch || c.CSVAppendPath != "") {
+		return fmt.Errorf("csv_totals is only supported for single runs, not with watch or csv_append")
+	}
+
+	if c.CSVCycle && c.Output != OutputFormatCSV && c.CSVAppendPath == "" {
+		return fmt.Errorf("csv_cycle requires output 'csv' or csv_append")
+	}
+
+	for _, pattern := range c.SystemContainers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("system_containers contains an invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	if c.OnlyProblems && !c.IsTableOutput() {
+		return fmt.Errorf("only_problems requires output 'table' or 'table-wide'")
+	}
+
+	if c.WatchChangedOnly && (!c.Watch || !c.IsTableOutput()) {
+		return fmt.Errorf("watch_pods_changed_only requires watch with output 'table' or 'table-wide'")
+	}
+
+	if c.SummaryOnly && c.Output != OutputFormatJSON {
+		return fmt.Errorf("summary_only requires output 'json'")
+	}
+
+	if c.SummaryOnly && c.ProblemsOnly {
+		return fmt.Errorf("summary_only cannot be combined with problems_only")
+	}
+
+	if err := c.validateOutputFile(); err != nil {
+		return err
+	}
+
+	if c.SortBy != SortByName && c.SortBy != SortByHeadroom {
+		return fmt.Errorf("sort_by must be either 'name' or 'headroom'")
+	}
+
+	if c.SortOrder != "" && c.SortOrder != SortOrderAsc && c.SortOrder != SortOrderDesc {
+		return fmt.Errorf("sort_order must be either 'asc' or 'desc'")
+	}
+
+	if c.GroupBy != "" && c.GroupBy != GroupByNode {
+		return fmt.Errorf("group_by must be 'node'")
+	}
+
+	if c.GroupByLabel != "" && !c.IsTableOutput() {
+		return fmt.Errorf("group_by_label requires output 'table' or 'table-wide'")
+	}
+
+	if c.PrimaryMetric != "" && c.PrimaryMetric != PrimaryMetricRequest && c.PrimaryMetric != PrimaryMetricLimit {
+		return fmt.Errorf("primary_metric must be either 'request' or 'limit'")
+	}
+
+	if c.Color != ColorAuto && c.Color != ColorAlways && c.Color != ColorNever {
+		return fmt.Errorf("color must be one of 'auto', 'always' or 'never'")
+	}
+
+	if c.PercentPrecision < 0 || c.PercentPrecision > maxPercentPrecision {
+		return fmt.Errorf("percent_precision must be between 0 and %d", maxPercentPrecision)
+	}
+
+	if c.Units != "" && c.Units != UnitsBinary && c.Units != UnitsDecimal {
+		return fmt.Errorf("units must be either 'binary' or 'decimal'")
+	}
+
+	switch c.TimestampFormat {
+	case "", TimestampRFC3339, TimestampEpoch, TimestampEpochMillis:
+	default:
+		return fmt.Errorf("timestamp_format must be one of 'rfc3339', 'epoch' or 'epochmillis'")
+	}
+
+	if c.SuggestRequests {
+		if c.SuggestFactor <= 0 {
+			return fmt.Errorf("suggest_headroom_factor must be positive")
+		}
+		if q, err := resource.ParseQuantity(c.SuggestRoundTo); err != nil || q.Value() <= 0 {
+			return fmt.Errorf("suggest_round_to must be a positive quantity such as '32Mi'")
+		}
+	}
+
+	if c.CompareRequests && (c.RightSizingLow <= 0 || c.RightSizingLow >= c.RightSizingHigh) {
+		return fmt.Errorf("rightsizing_low must be positive and below rightsizing_high")
+	}
+
+	if _, ok := logLevels[strings.ToLower(c.LogLevel)]; !ok {
+		return fmt.Errorf("log_level must be one of 'debug', 'info', 'warn' or 'error'")
+	}
+
+	if c.LogFormat != LogFormatJSON && c.LogFormat != LogFormatText {
+		return fmt.Errorf("log_format must be either 'json' or 'text'")
 	}
 
 	return nil
 }
 
+// IsTableOutput reports whether a human-readable table format is selected
+func (c *Config) IsTableOutput() bool {
+	return c.Output == OutputFormatTable || c.Output == OutputFormatWide
+}
+
+// LimitIsPrimary reports whether the warning threshold applies to usage vs limit rather than usage vs request
+func (c *Config) LimitIsPrimary() bool {
+	return c.PrimaryMetric == PrimaryMetricLimit
+}
+
+// ResolveColor reports whether ANSI colors should be emitted
+// Colors are only ever used for table output so machine-readable formats stay clean
+func (c *Config) ResolveColor(isTerminal bool) bool {
+	if !c.IsTableOutput() {
+		return false
+	}
+	switch c.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return isTerminal
+	}
+}
+
+// ResolveStatusOnly reports whether the compact status view can be used
+// It needs a terminal to refresh in place, so it falls back to the full report otherwise
+func (c *Config) ResolveStatusOnly(isTerminal bool) bool {
+	return c.WatchStatusOnly && isTerminal && c.IsTableOutput()
+}
+
+// NextCheckDelay returns the check interval plus a random jitter in [0, IntervalJitter]
+func (c *Config) NextCheckDelay() time.Duration {
+	if c.IntervalJitter <= 0 {
+		return c.CheckInterval
+	}
+	return c.CheckInterval + time.Duration(rand.Int64N(int64(c.IntervalJitter)+1))
+}
+
+// ReadinessWindow is how long after a successful cycle the watcher still counts as ready:
+// two check intervals plus the maximum jitter, so one slow or failed cycle is tolerated
+func (c *Config) ReadinessWindow() time.Duration {
+	return 2*c.CheckInterval + c.IntervalJitter
+}
+
+// ReportDue reports whether a report should be printed at now, given when the last one was printed.
+// Collection still runs every check interval; this only throttles the printed output.
+func (c *Config) ReportDue(lastReport, now time.Time) bool {
+	if c.ReportInterval <= 0 || lastReport.IsZero() {
+		return true
+	}
+	return now.Sub(lastReport) >= c.ReportInterval
+}
+
+// validateOutputFile checks the report file and its rotation settings
+func (c *Config) validateOutputFile() error {
+	if c.OutputFile == "" {
+		if c.RotateSize != "" || c.RotateInterval != 0 {
+			return fmt.Errorf("rotate_size and rotate_interval require output_file")
+		}
+		return nil
+	}
+	if c.Output != OutputFormatCSV && c.Output != OutputFormatJSON {
+		return fmt.Errorf("output_file requires output 'csv' or 'json'")
+	}
+	if c.RotateSize != "" {
+		if q, err := ParseByteSize(c.RotateSize); err != nil || q.Value() <= 0 {
+			return fmt.Errorf("rotate_size must be a positive size (e.g. 100MB or 64Mi)")
+		}
+	}
+	if c.RotateInterval < 0 {
+		return fmt.Errorf("rotate_interval must not be negative")
+	}
+	return nil
+}
+
+// ParseByteSize parses a size such as 100MB, 64MiB or 64Mi
+// A trailing B is dropped so the usual spellings map to Kubernetes quantities (MB is 1000-based, MiB 1024-based)
+func ParseByteSize(value string) (resource.Quantity, error) {
+	if trimmed, ok := strings.CutSuffix(value, "B"); ok && trimmed != "" {
+		value = trimmed
+	}
+	return resource.ParseQuantity(value)
+}
+
+// RotateSizeBytes returns the output file rotation size in bytes
+// It returns 0 when rotation by size is disabled or RotateSize is not a valid size
+func (c *Config) RotateSizeBytes() int64 {
+	if c.RotateSize == "" {
+		return 0
+	}
+	q, err := ParseByteSize(c.RotateSize)
+	if err != nil {
+		return 0
+	}
+	return q.Value()
+}
+
+// SuggestRoundToBytes returns the rounding step for suggested requests in bytes
+// It returns 0 when SuggestRoundTo is not a valid quantity
+func (c *Config) SuggestRoundToBytes() int64 {
+	q, err := resource.ParseQuantity(c.SuggestRoundTo)
+	if err != nil {
+		return 0
+	}
+	return q.Value()
+}
+
+// ParseMemoryThreshold parses an absolute memory threshold such as 2Gi
+// A bare number is read as MiB, keeping the historical MEMORY_THRESHOLD_MB meaning
+func ParseMemoryThreshold(value string) (resource.Quantity, error) {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		value += "Mi"
+	}
+	return resource.ParseQuantity(value)
+}
+
+// MemoryThresholdBytes returns the absolute memory threshold in bytes, or 0 when it is disabled
+func (c *Config) MemoryThresholdBytes() int64 {
+	if c.MemoryThresholdQuantity == nil {
+		return 0
+	}
+	return c.MemoryThresholdQuantity.Value()
+}
+
+// SlogLevel returns the slog level corresponding to the configured log level
+func (c *Config) SlogLevel() slog.Level {
+	return logLevels[strings.ToLower(c.LogLevel)]
+}
+
 // Helper functions for environment variable parsing
 
 func getEnv(key, defaultValue string) string {
@@ -227,6 +1093,30 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+// getEnvExpandedList parses a comma-separated env var after expanding $VAR and ${VAR} references,
+// so templated deployments can set LABELS=$TEAM_LABEL. Unset variables expand to nothing.
+// CLI values are not expanded, the shell has already done so.
+func getEnvExpandedList(key string) []string {
+	return parseCommaSeparated(os.ExpandEnv(os.Getenv(key)))
+}
+
+// CSVColumnCollisions returns the labels, annotations and env vars whose CSV column names repeat
+// Names collide when listed twice or when they only differ by '.' versus '_', e.g. app.tier and app_tier
+func (c *Config) CSVColumnCollisions() []string {
+	var collisions []string
+	for _, names := range [][]string{c.Labels, c.Annotations, c.EnvVars} {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			column := strings.ReplaceAll(name, ".", "_")
+			if seen[column] {
+				collisions = append(collisions, name)
+			}
+			seen[column] = true
+		}
+	}
+	return collisions
+}
+
 // parseCommaSeparated parses a comma-separated string into a slice of trimmed, non-empty strings
 func parseCommaSeparated(value string) []string {
 	if value == "" {
@@ -242,3 +1132,12 @@ func parseCommaSeparated(value string) []string {
 	}
 	return result
 }
+
+// parsePhases parses a comma-separated phase list, normalizing case so "failed" matches "Failed"
+func parsePhases(value string) []string {
+	phases := parseCommaSeparated(value)
+	for i, phase := range phases {
+		phases[i] = strings.ToUpper(phase[:1]) + strings.ToLower(phase[1:])
+	}
+	return phases
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
@@ -26,8 +28,8 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected check interval '30s', got '%v'", cfg.CheckInterval)
 	}
 
-	if cfg.MemoryThresholdMB != 1024 {
-		t.Errorf("Expected memory threshold '1024', got '%d'", cfg.MemoryThresholdMB)
+	if cfg.MemoryThresholdBytes() != 0 {
+		t.Errorf("Expected the memory threshold to be disabled by default, got '%s'", cfg.MemoryThreshold)
 	}
 }
 
@@ -70,8 +72,9 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 		t.Errorf("Expected check interval '1m', got '%v'", cfg.CheckInterval)
 	}
 
-	if cfg.MemoryThresholdMB != 2048 {
-		t.Errorf("Expected memory threshold '2048', got '%d'", cfg.MemoryThresholdMB)
+	// The legacy MB variable is still honored, a bare number meaning MiB
+	if cfg.MemoryThresholdBytes() != 2048*1024*1024 {
+		t.Errorf("Expected memory threshold '2048' MB, got '%s'", cfg.MemoryThreshold)
 	}
 
 	if cfg.MemoryWarningPercent != 90.0 {
@@ -83,6 +86,36 @@ func TestLoadWithEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestLoad_ExpandsLabelEnvReferences(t *testing.T) {
+	t.Setenv("TEAM_LABEL", "team")
+	t.Setenv("LABELS", "$TEAM_LABEL,app")
+	t.Setenv("ANNOTATIONS", "${TEAM_LABEL}/owner,$UNSET_ANNOTATION")
+	t.Setenv("EXCLUDE_LABELS", "${UNSET_LABEL}")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if len(cfg.Labels) != 2 || cfg.Labels[0] != "team" || cfg.Labels[1] != "app" {
+		t.Errorf("Expected labels [team app], got %v", cfg.Labels)
+	}
+	if len(cfg.Annotations) != 1 || cfg.Annotations[0] != "team/owner" {
+		t.Errorf("Expected unset references to expand to nothing, got %v", cfg.Annotations)
+	}
+	if len(cfg.ExcludeLabels) != 0 {
+		t.Errorf("Expected no exclude labels, got %v", cfg.ExcludeLabels)
+	}
+
+	cli, err := LoadWithCLI(&CLIConfig{Labels: "$TEAM_LABEL"})
+	if err != nil {
+		t.Fatalf("LoadWithCLI() failed: %v", err)
+	}
+	if len(cli.Labels) != 1 || cli.Labels[0] != "$TEAM_LABEL" {
+		t.Errorf("Expected CLI labels to be kept verbatim, got %v", cli.Labels)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -93,9 +126,13 @@ func TestValidate(t *testing.T) {
 			name: "valid config",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 80.0,
 				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "name",
+				Color:                "auto",
 			},
 			wantErr: false,
 		},
@@ -103,7 +140,7 @@ func TestValidate(t *testing.T) {
 			name: "invalid check interval",
 			config: Config{
 				CheckInterval:        0,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 80.0,
 				Output:               "table",
 			},
@@ -113,17 +150,31 @@ func TestValidate(t *testing.T) {
 			name: "invalid memory threshold",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    0,
+				MemoryThreshold:      "-1Gi",
 				MemoryWarningPercent: 80.0,
 				Output:               "table",
 			},
 			wantErr: true,
 		},
+		{
+			name: "disabled memory threshold",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "0",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "name",
+				Color:                "auto",
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid warning percent - too low",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 0,
 				Output:               "table",
 			},
@@ -133,7 +184,7 @@ func TestValidate(t *testing.T) {
 			name: "invalid warning percent - too high",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 101.0,
 				Output:               "table",
 			},
@@ -143,9 +194,27 @@ func TestValidate(t *testing.T) {
 			name: "valid output - csv",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 80.0,
 				Output:               "csv",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "name",
+				Color:                "auto",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid output - table-wide",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table-wide",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "name",
+				Color:                "auto",
 			},
 			wantErr: false,
 		},
@@ -153,9 +222,85 @@ func TestValidate(t *testing.T) {
 			name: "invalid output format",
 			config: Config{
 				CheckInterval:        30 * time.Second,
-				MemoryThresholdMB:    1024,
+				MemoryThreshold:      "1Gi",
 				MemoryWarningPercent: 80.0,
-				Output:               "json",
+				Output:               "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "problems only without json output",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				ProblemsOnly:         true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid log level",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "verbose",
+				LogFormat:            "json",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid log format - text",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "text",
+				SortBy:               "name",
+				Color:                "auto",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid log format",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "xml",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid sort order",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "usage",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid color mode",
+			config: Config{
+				CheckInterval:        30 * time.Second,
+				MemoryThreshold:      "1Gi",
+				MemoryWarningPercent: 80.0,
+				Output:               "table",
+				LogLevel:             "info",
+				LogFormat:            "json",
+				SortBy:               "name",
+				Color:                "sometimes",
 			},
 			wantErr: true,
 		},
@@ -170,3 +315,153 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestSlogLevel(t *testing.T) {
+	testCases := []struct {
+		logLevel string
+		expected slog.Level
+	}{
+		{logLevel: "debug", expected: slog.LevelDebug},
+		{logLevel: "info", expected: slog.LevelInfo},
+		{logLevel: "warn", expected: slog.LevelWarn},
+		{logLevel: "error", expected: slog.LevelError},
+		{logLevel: "DEBUG", expected: slog.LevelDebug},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.logLevel, func(t *testing.T) {
+			cfg := &Config{LogLevel: tc.logLevel}
+			if got := cfg.SlogLevel(); got != tc.expected {
+				t.Errorf("SlogLevel() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	testCases := []struct {
+		name       string
+		color      string
+		output     string
+		isTerminal bool
+		expected   bool
+	}{
+		{name: "auto on terminal", color: "auto", output: "table", isTerminal: true, expected: true},
+		{name: "auto when piped", color: "auto", output: "table", isTerminal: false, expected: false},
+		{name: "always when piped", color: "always", output: "table", isTerminal: false, expected: true},
+		{name: "never on terminal", color: "never", output: "table", isTerminal: true, expected: false},
+		{name: "always with csv", color: "always", output: "csv", isTerminal: true, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Color: tc.color, Output: tc.output}
+			if got := cfg.ResolveColor(tc.isTerminal); got != tc.expected {
+				t.Errorf("ResolveColor(%t) = %t, want %t", tc.isTerminal, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveStatusOnly(t *testing.T) {
+	cfg := &Config{WatchStatusOnly: true, Output: "table"}
+	if !cfg.ResolveStatusOnly(true) {
+		t.Error("Expected status-only view on a terminal")
+	}
+	if cfg.ResolveStatusOnly(false) {
+		t.Error("Expected status-only view to be disabled when not a terminal")
+	}
+
+	cfg.Output = "csv"
+	if cfg.ResolveStatusOnly(true) {
+		t.Error("Expected status-only view to be disabled for CSV output")
+	}
+}
+
+func TestNextCheckDelay(t *testing.T) {
+	cfg := &Config{CheckInterval: 30 * time.Second}
+	if got := cfg.NextCheckDelay(); got != 30*time.Second {
+		t.Errorf("Expected no jitter by default, got %v", got)
+	}
+
+	cfg.IntervalJitter = 10 * time.Second
+	for i := 0; i < 100; i++ {
+		got := cfg.NextCheckDelay()
+		if got < 30*time.Second || got > 40*time.Second {
+			t.Fatalf("Expected delay within [30s, 40s], got %v", got)
+		}
+	}
+}
+
+func TestParseMemoryThreshold(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		wantErr  bool
+	}{
+		{"2Gi", 2 * 1024 * 1024 * 1024, false},
+		{"512Mi", 512 * 1024 * 1024, false},
+		{"1G", 1000 * 1000 * 1000, false},
+		{"1024", 1024 * 1024 * 1024, false}, // bare numbers keep the historical MB meaning
+		{"lots", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			q, err := ParseMemoryThreshold(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMemoryThreshold(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && q.Value() != tt.expected {
+				t.Errorf("ParseMemoryThreshold(%q) = %d, want %d", tt.value, q.Value(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestReportDue(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		interval   time.Duration
+		lastReport time.Time
+		want       bool
+	}{
+		{"every cycle by default", 0, now.Add(-time.Second), true},
+		{"first report", 5 * time.Minute, time.Time{}, true},
+		{"too soon", 5 * time.Minute, now.Add(-4 * time.Minute), false},
+		{"interval elapsed", 5 * time.Minute, now.Add(-5 * time.Minute), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ReportInterval: tt.interval}
+			if got := cfg.ReportDue(tt.lastReport, now); got != tt.want {
+				t.Errorf("ReportDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVColumnCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{"distinct names", Config{Labels: []string{"app", "team"}, Annotations: []string{"app"}}, ""},
+		{"repeated label", Config{Labels: []string{"app", "app"}}, "app"},
+		{"dot and underscore", Config{Annotations: []string{"a.b", "a_b"}}, "a_b"},
+		{"repeated env var", Config{EnvVars: []string{"DAG_ID", "DAG_ID"}}, "DAG_ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmt.Sprint(tt.cfg.CSVColumnCollisions())
+			if got != "["+tt.expected+"]" {
+				t.Errorf("CSVColumnCollisions() = %s, want [%s]", got, tt.expected)
+			}
+		})
+	}
+}
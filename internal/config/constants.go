@@ -4,4 +4,84 @@ package config
 const (
 	OutputFormatCSV   = "csv"
 	OutputFormatTable = "table"
+	OutputFormatWide  = "table-wide"
+	OutputFormatJSON  = "json"
+)
+
+// Sort order constants
+const (
+	SortByName     = "name"
+	SortByHeadroom = "headroom"
+)
+
+// Sort direction constants; an empty direction uses the natural one of the sort key
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Grouping constants for the aggregated report
+const (
+	GroupByNode = "node"
+)
+
+// Primary metric constants, selecting the percentage the warning threshold applies to
+const (
+	PrimaryMetricRequest = "request"
+	PrimaryMetricLimit   = "limit"
+)
+
+// Color mode constants
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// Memory unit constants (empty keeps the historical 1024-based KB/MB/GB labels)
+const (
+	UnitsBinary  = "binary"
+	UnitsDecimal = "decimal"
+)
+
+// Timestamp format constants for CSV and table output
+const (
+	TimestampRFC3339     = "rfc3339"
+	TimestampEpoch       = "epoch"
+	TimestampEpochMillis = "epochmillis"
+)
+
+// Default status symbols shown before each pod in table output
+const (
+	DefaultSymbolOK        = "🟢"
+	DefaultSymbolWarning   = "🟡"
+	DefaultSymbolCritical  = "🔴"
+	DefaultSymbolNoData    = "⚪"
+	DefaultSymbolCompleted = "🔵"
+)
+
+// DefaultSystemContainers names the pause container some metrics setups report alongside the pod's own containers
+const DefaultSystemContainers = "POD"
+
+// Log level constants
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Log format constants
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// Pod phase names accepted by --include-phases
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+	PhaseUnknown   = "Unknown"
 )
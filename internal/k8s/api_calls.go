@@ -0,0 +1,46 @@
+package k8s
+
+import "sync/atomic"
+
+// APICallCounts counts the requests a client sent to the API server, to gauge the collector's own load
+// Retried requests count once per attempt, paged lists once per page
+type APICallCounts struct {
+	NamespaceLists      int64 `json:"namespace_lists"`
+	PodRequests         int64 `json:"pod_requests"` // Pod list pages and single pod gets
+	PodsListed          int64 `json:"pods_listed"`
+	MetricsRequests     int64 `json:"metrics_requests"`
+	NodeLists           int64 `json:"node_lists"`
+	NamespacesProcessed int64 `json:"namespaces_processed"`
+}
+
+// Add accumulates the counts of another client, e.g. across clusters
+func (a *APICallCounts) Add(other APICallCounts) {
+	a.NamespaceLists += other.NamespaceLists
+	a.PodRequests += other.PodRequests
+	a.PodsListed += other.PodsListed
+	a.MetricsRequests += other.MetricsRequests
+	a.NodeLists += other.NodeLists
+	a.NamespacesProcessed += other.NamespacesProcessed
+}
+
+// apiCallCounters holds the running counts, safe for concurrent collections
+type apiCallCounters struct {
+	namespaceLists      atomic.Int64
+	podRequests         atomic.Int64
+	podsListed          atomic.Int64
+	metricsRequests     atomic.Int64
+	nodeLists           atomic.Int64
+	namespacesProcessed atomic.Int64
+}
+
+// TakeAPICallCounts returns the counts since the previous call and resets them, so each cycle reports its own
+func (c *Client) TakeAPICallCounts() APICallCounts {
+	return APICallCounts{
+		NamespaceLists:      c.apiCalls.namespaceLists.Swap(0),
+		PodRequests:         c.apiCalls.podRequests.Swap(0),
+		PodsListed:          c.apiCalls.podsListed.Swap(0),
+		MetricsRequests:     c.apiCalls.metricsRequests.Swap(0),
+		NodeLists:           c.apiCalls.nodeLists.Swap(0),
+		NamespacesProcessed: c.apiCalls.namespacesProcessed.Swap(0),
+	}
+}
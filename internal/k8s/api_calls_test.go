@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTakeAPICallCounts(t *testing.T) {
+	c := newFakeClient(
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("a", "worker", corev1.PodRunning, "", ""),
+			newTestPod("b", "api", corev1.PodRunning, "50Mi", ""),
+		},
+		newTestPodMetrics("a", "web", "80Mi"),
+	)
+
+	if _, _, err := c.GetPodsMemoryInfo(context.Background(), "", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := c.GetSinglePodMemoryInfo(context.Background(), "a", "web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := APICallCounts{
+		NamespaceLists:      1,
+		PodRequests:         4, // One list per namespace and the single pod get
+		PodsListed:          4,
+		MetricsRequests:     3, // The empty namespace skips its metrics list
+		NamespacesProcessed: 4,
+	}
+	if got := c.TakeAPICallCounts(); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if got := c.TakeAPICallCounts(); got != (APICallCounts{}) {
+		t.Errorf("expected the counts to reset, got %+v", got)
+	}
+}
+
+func TestAPICallCounts_Add(t *testing.T) {
+	total := APICallCounts{PodRequests: 2, PodsListed: 10}
+	total.Add(APICallCounts{NamespaceLists: 1, PodRequests: 3, PodsListed: 5, MetricsRequests: 3, NodeLists: 1, NamespacesProcessed: 3})
+
+	expected := APICallCounts{NamespaceLists: 1, PodRequests: 5, PodsListed: 15, MetricsRequests: 3, NodeLists: 1, NamespacesProcessed: 3}
+	if total != expected {
+		t.Errorf("expected %+v, got %+v", expected, total)
+	}
+}
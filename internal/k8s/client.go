@@ -3,8 +3,11 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -14,9 +17,26 @@ import (
 
 // Client wraps Kubernetes clients
 type Client struct {
-	clientset     kubernetes.Interface
-	metricsClient versioned.Interface
-	config        *rest.Config
+	clientset       kubernetes.Interface
+	metricsClient   versioned.Interface // nil when metrics are unavailable or disabled
+	config          *rest.Config
+	retryPolicy     RetryPolicy
+	containerFilter ContainerFilter
+	listPageSize    int64     // Pods requested per list call, 0 disables paging
+	maxNamespaces   int       // Namespaces visited by all-namespaces collections, 0 for no limit
+	strict          bool      // Fail all-namespaces collections when any namespace cannot be listed
+	nodeName        string    // Only list pods scheduled on this node when set
+	labelSelector   string    // Only list pods, and their metrics, matching this selector when set
+	metricsAPIGroup string    // API group serving pod metrics, empty for metrics.k8s.io
+	clock           Clock     // Time source for timestamps, real time when nil
+	cluster         string    // Cluster name recorded on collected pods, empty for single-cluster runs
+	podCache        *podCache // Pod specs of the last complete collection, nil when disabled
+	metricsDisabled bool      // Set by DisableMetrics, skips metrics even with a custom API group
+	apiCalls        apiCallCounters
+	envVarNames     []string      // Container environment variables recorded on each container
+	annotationKeys  []string      // Annotations copied into the pod labels, see SetAnnotationsAsLabels
+	samples         int           // Metrics reads averaged per collection, see SetSampling
+	sampleInterval  time.Duration // Wait between metrics reads when sampling
 }
 
 // NewClient creates a new Kubernetes client
@@ -31,41 +51,179 @@ func NewClient(kubeconfig string, inCluster bool) (*Client, error) {
 			return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
 		}
 	} else {
-		// Use kubeconfig file
-		if kubeconfig == "" {
-			// Default to ~/.kube/config
-			home, homeErr := os.UserHomeDir()
-			if homeErr != nil {
-				return nil, fmt.Errorf("failed to get home directory: %w", homeErr)
-			}
-			kubeconfig = filepath.Join(home, ".kube", "config")
+		// Use kubeconfig file, or every file of a kubeconfig directory
+		rules, err := kubeconfigLoadingRules(kubeconfig)
+		if err != nil {
+			return nil, err
 		}
 
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
 		if err != nil {
 			return nil, fmt.Errorf("failed to build config from kubeconfig: %w", err)
 		}
 	}
 
+	return newClientForConfig(config)
+}
+
+// NewClientForContext creates a client for a named context of the kubeconfig file
+// Pods collected through it are tagged with the context name as their cluster
+func NewClientForContext(kubeconfig, contextName string) (*Client, error) {
+	rules, err := kubeconfigLoadingRules(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for context %s: %w", contextName, err)
+	}
+
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("context %s: %w", contextName, err)
+	}
+	client.cluster = contextName
+	return client, nil
+}
+
+// resolveKubeconfigPath defaults an empty kubeconfig path to ~/.kube/config
+func resolveKubeconfigPath(kubeconfig string) (string, error) {
+	if kubeconfig != "" {
+		return kubeconfig, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// kubeconfigLoadingRules returns the rules loading the kubeconfig at path
+// A directory is expanded to its files, merged like a multi-path KUBECONFIG: files are read in name order
+// and the first file setting a value wins. Hidden files and subdirectories are skipped.
+func kubeconfigLoadingRules(kubeconfig string) (*clientcmd.ClientConfigLoadingRules, error) {
+	kubeconfig, err := resolveKubeconfigPath(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(kubeconfig)
+	if err != nil || !info.IsDir() {
+		// Missing files are reported by the loader, with its usual message
+		return &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}, nil
+	}
+
+	entries, err := os.ReadDir(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig directory %s: %w", kubeconfig, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(kubeconfig, entry.Name())
+		// The merging loader ignores unreadable files, so each one is checked to fail loudly instead
+		if _, err := clientcmd.LoadFromFile(path); err != nil {
+			return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+		}
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("kubeconfig directory %s contains no kubeconfig files", kubeconfig)
+	}
+	return &clientcmd.ClientConfigLoadingRules{Precedence: files}, nil
+}
+
+// newClientForConfig creates the clientsets for a resolved REST config
+func newClientForConfig(config *rest.Config) (*Client, error) {
 	// Create standard Kubernetes clientset
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
-	// Create metrics clientset
+	// Create metrics clientset, continuing without usage data when it cannot be built
 	metricsClient, err := versioned.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create metrics client: %w", err)
+		slog.Warn("Failed to create metrics client, continuing without metrics", "error", err)
+		metricsClient = nil
 	}
 
 	return &Client{
 		clientset:     clientset,
 		metricsClient: metricsClient,
 		config:        config,
+		retryPolicy:   DefaultRetryPolicy(),
 	}, nil
 }
 
+// NewClientWithInterfaces creates a client from already constructed clientsets
+// It lets callers such as tests inject fake implementations; a nil metricsClient disables metrics
+func NewClientWithInterfaces(clientset kubernetes.Interface, metricsClient versioned.Interface) *Client {
+	return &Client{
+		clientset:     clientset,
+		metricsClient: metricsClient,
+		retryPolicy:   DefaultRetryPolicy(),
+	}
+}
+
+// SetContainerFilter restricts which containers are reported and counted in pod totals
+func (c *Client) SetContainerFilter(filter ContainerFilter) {
+	c.containerFilter = filter
+}
+
+// SetRetryPolicy overrides how transient API errors are retried
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// SetListPageSize sets how many pods are requested per list call
+// Paging keeps large namespaces from being returned in one response; 0 lists everything at once
+func (c *Client) SetListPageSize(size int64) {
+	c.listPageSize = size
+}
+
+// SetCluster sets the cluster name recorded on collected pods
+func (c *Client) SetCluster(cluster string) {
+	c.cluster = cluster
+}
+
+// Cluster returns the cluster name recorded on collected pods
+func (c *Client) Cluster() string {
+	return c.cluster
+}
+
+// SetMaxNamespaces caps how many namespaces an all-namespaces collection visits; 0 visits them all
+func (c *Client) SetMaxNamespaces(limit int) {
+	c.maxNamespaces = limit
+}
+
+// SetStrict makes all-namespaces collections fail when any namespace cannot be listed
+// By default such namespaces are logged or reported as forbidden and the rest is collected
+func (c *Client) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// SetAnnotationsAsLabels copies the given annotations into each pod's labels so grouping, filtering
+// and display treat them as labels; a label with the same key keeps its value
+func (c *Client) SetAnnotationsAsLabels(keys []string) {
+	c.annotationKeys = keys
+}
+
+// SetNodeName restricts pod listing to pods scheduled on the given node; empty lists all pods
+func (c *Client) SetNodeName(nodeName string) {
+	c.nodeName = nodeName
+}
+
+// SetLabelSelector restricts pod and pod metrics listing to pods matching the selector; empty lists all pods
+func (c *Client) SetLabelSelector(selector string) {
+	c.labelSelector = selector
+}
+
 // HealthCheck verifies the client can connect to the cluster
 func (c *Client) HealthCheck(_ context.Context) error {
 	_, err := c.clientset.Discovery().ServerVersion()
@@ -0,0 +1,487 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func newTestPod(namespace, name string, phase corev1.PodPhase, request, limit string) *corev1.Pod {
+	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	if request != "" {
+		resources.Requests[corev1.ResourceMemory] = resource.MustParse(request)
+	}
+	if limit != "" {
+		resources.Limits[corev1.ResourceMemory] = resource.MustParse(limit)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: resources}}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newTestPodMetrics(namespace, name, usage string) *metricsv1beta1.PodMetrics {
+	return &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(usage)}},
+		},
+	}
+}
+
+// newFakeClient builds a client backed by fake clientsets
+// Metrics are served through a reactor because the fake metrics tracker
+// registers PodMetrics under a resource name that List does not query
+func newFakeClient(objects []runtime.Object, metrics ...*metricsv1beta1.PodMetrics) *Client {
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metricsv1beta1.PodMetricsList{}
+		for _, m := range metrics {
+			if action.GetNamespace() == "" || action.GetNamespace() == m.Namespace {
+				list.Items = append(list.Items, *m)
+			}
+		}
+		return true, list, nil
+	})
+	return NewClientWithInterfaces(fake.NewSimpleClientset(objects...), metricsClient)
+}
+
+func TestGetPodsMemoryInfo_AllNamespacesSummary(t *testing.T) {
+	c := newFakeClient(
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("a", "batch", corev1.PodPending, "", ""),
+			newTestPod("b", "api", corev1.PodRunning, "50Mi", ""),
+		},
+		newTestPodMetrics("a", "web", "80Mi"),
+		newTestPodMetrics("b", "api", "20Mi"),
+	)
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(pods) != 3 || summary.TotalPods != 3 {
+		t.Errorf("expected 3 pods, got %d (summary %d)", len(pods), summary.TotalPods)
+	}
+	if summary.NamespaceCount != 2 {
+		t.Errorf("expected 2 namespaces, got %d", summary.NamespaceCount)
+	}
+	if summary.RunningPods != 2 || summary.PodsWithMetrics != 2 {
+		t.Errorf("expected 2 running pods with metrics, got running=%d metrics=%d",
+			summary.RunningPods, summary.PodsWithMetrics)
+	}
+	if summary.PodsWithRequests != 2 || summary.PodsWithLimits != 1 {
+		t.Errorf("expected 2 pods with requests and 1 with limits, got requests=%d limits=%d",
+			summary.PodsWithRequests, summary.PodsWithLimits)
+	}
+	if summary.TotalMemoryUsage.Value() != 100*1024*1024 {
+		t.Errorf("expected total usage 100Mi, got %s", summary.TotalMemoryUsage.String())
+	}
+}
+
+func TestGetPodsMemoryInfo_SingleNamespace(t *testing.T) {
+	c := newFakeClient(
+		[]runtime.Object{
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("b", "api", corev1.PodRunning, "50Mi", "100Mi"),
+		},
+		newTestPodMetrics("a", "web", "80Mi"),
+	)
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "a", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(pods) != 1 || pods[0].PodName != "web" {
+		t.Fatalf("expected only pod a/web, got %v", pods)
+	}
+	if summary.NamespaceCount != 1 || summary.TotalMemoryLimit.Value() != 200*1024*1024 {
+		t.Errorf("unexpected summary: namespaces=%d limit=%s",
+			summary.NamespaceCount, summary.TotalMemoryLimit.String())
+	}
+}
+
+func TestGetSinglePodMemoryInfo_FetchesOnePod(t *testing.T) {
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("get", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, newTestPodMetrics("a", "web", "150Mi"), nil
+	})
+	c := NewClientWithInterfaces(
+		fake.NewSimpleClientset(
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("a", "other", corev1.PodRunning, "100Mi", "200Mi"),
+		),
+		metricsClient,
+	)
+
+	pods, summary, err := c.GetSinglePodMemoryInfo(context.Background(), "a", "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].PodName != "web" {
+		t.Fatalf("expected only pod a/web, got %v", pods)
+	}
+	if summary.TotalPods != 1 || summary.PodsWithMetrics != 1 || summary.TotalMemoryUsage.Value() != 150*1024*1024 {
+		t.Errorf("unexpected summary: pods=%d metrics=%d usage=%s",
+			summary.TotalPods, summary.PodsWithMetrics, summary.TotalMemoryUsage.String())
+	}
+}
+
+func TestGetSinglePodMemoryInfo_MissingPod(t *testing.T) {
+	c := newFakeClient(nil)
+
+	if _, _, err := c.GetSinglePodMemoryInfo(context.Background(), "a", "ghost"); err == nil {
+		t.Fatal("expected error for a missing pod")
+	}
+}
+
+func TestGetPodsMemoryInfo_AccumulatesTimingsAcrossNamespaces(t *testing.T) {
+	c := newFakeClient(
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			newTestPod("a", "p1", corev1.PodRunning, "100Mi", "200Mi"),
+		},
+	)
+	var listCalls int
+	c.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listCalls++
+		time.Sleep(5 * time.Millisecond)
+		return false, nil, nil
+	})
+
+	_, summary, err := c.GetPodsMemoryInfo(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if listCalls != 2 {
+		t.Fatalf("expected one pod list per namespace, got %d", listCalls)
+	}
+	if summary.Timings.PodList < 10*time.Millisecond {
+		t.Errorf("expected pod list time summed across namespaces, got %v", summary.Timings.PodList)
+	}
+}
+
+func TestGetPodsMemoryInfo_PagesThroughPods(t *testing.T) {
+	pods := []*corev1.Pod{
+		newTestPod("a", "p1", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("a", "p2", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("a", "p3", corev1.PodPending, "", ""),
+	}
+	c := newFakeClient(nil, newTestPodMetrics("a", "p3", "10Mi"))
+	c.SetListPageSize(2)
+
+	// Serve the pods in pages, using the offset of the next page as continue token
+	var requests []metav1.ListOptions
+	c.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).ListOptions
+		requests = append(requests, opts)
+		offset := 0
+		if opts.Continue != "" {
+			offset = int(opts.Continue[0] - '0')
+		}
+		end := min(offset+int(opts.Limit), len(pods))
+		list := &corev1.PodList{}
+		for _, pod := range pods[offset:end] {
+			list.Items = append(list.Items, *pod)
+		}
+		if end < len(pods) {
+			list.Continue = string(rune('0' + end))
+		}
+		return true, list, nil
+	})
+
+	result, summary, err := c.GetPodsMemoryInfo(context.Background(), "a", false)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if len(requests) != 2 || requests[0].Limit != 2 || requests[1].Continue != "2" {
+		t.Fatalf("expected two paged list calls, got %+v", requests)
+	}
+	if len(result) != 3 || result[2].PodName != "p3" || summary.TotalPods != 3 {
+		t.Fatalf("expected pods from every page, got %d (summary %d)", len(result), summary.TotalPods)
+	}
+	if summary.RunningPods != 2 || summary.PodsWithMetrics != 1 {
+		t.Errorf("expected pages merged into the summary, got running=%d metrics=%d",
+			summary.RunningPods, summary.PodsWithMetrics)
+	}
+}
+
+func TestGetPodsMemoryInfo_FiltersByNode(t *testing.T) {
+	pods := []*corev1.Pod{
+		newTestPod("a", "on-node", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("a", "elsewhere", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("b", "elsewhere", corev1.PodRunning, "100Mi", "200Mi"),
+	}
+	pods[0].Spec.NodeName = "node-5"
+	c := newFakeClient([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	})
+	c.SetNodeName("node-5")
+
+	// The fake tracker ignores field selectors, so apply the selector in the reactor
+	var selectors []string
+	c.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListActionImpl).ListOptions.FieldSelector
+		selectors = append(selectors, selector)
+		list := &corev1.PodList{}
+		for _, pod := range pods {
+			if pod.Namespace == action.GetNamespace() && selector == "spec.nodeName="+pod.Spec.NodeName {
+				list.Items = append(list.Items, *pod)
+			}
+		}
+		return true, list, nil
+	})
+
+	result, summary, err := c.GetPodsMemoryInfo(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if len(selectors) != 2 || selectors[0] != "spec.nodeName=node-5" {
+		t.Errorf("expected the node selector on every namespace list, got %v", selectors)
+	}
+	if len(result) != 1 || result[0].PodName != "on-node" {
+		t.Fatalf("expected only the pod on node-5, got %v", result)
+	}
+	if summary.NamespaceCount != 1 {
+		t.Errorf("expected only namespaces with pods on the node to be counted, got %d", summary.NamespaceCount)
+	}
+}
+
+func TestGetPodsMemoryInfo_ReturnsPartialOnCancel(t *testing.T) {
+	c := newFakeClient([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("b", "api", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("c", "db", corev1.PodRunning, "100Mi", "200Mi"),
+	})
+
+	// Cancel once the first namespace has been listed, as Ctrl-C would mid-cycle
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.clientset.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "a" {
+			cancel()
+		}
+		return false, nil, nil
+	})
+
+	result, summary, err := c.GetPodsMemoryInfo(ctx, "", true)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if !summary.Partial || !summary.Cancelled() || summary.NamespacesCollected != 1 {
+		t.Errorf("expected a cancelled summary after 1 namespace, got partial=%v cancelled=%v collected=%d",
+			summary.Partial, summary.Cancelled(), summary.NamespacesCollected)
+	}
+	if len(result) != 1 || result[0].PodName != "web" {
+		t.Errorf("expected only the pods collected before cancelling, got %v", result)
+	}
+}
+
+func TestGetPodsMemoryInfo_StopsAtMaxNamespaces(t *testing.T) {
+	c := newFakeClient([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("b", "api", corev1.PodRunning, "100Mi", "200Mi"),
+		newTestPod("c", "db", corev1.PodRunning, "100Mi", "200Mi"),
+	})
+	c.SetMaxNamespaces(2)
+
+	result, summary, err := c.GetPodsMemoryInfo(context.Background(), "", true)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if !summary.Truncated || !summary.Partial || summary.NamespacesCollected != 2 || summary.NamespaceCount != 3 {
+		t.Errorf("expected a truncated summary after 2 of 3 namespaces, got truncated=%v partial=%v collected=%d count=%d",
+			summary.Truncated, summary.Partial, summary.NamespacesCollected, summary.NamespaceCount)
+	}
+	if len(result) != 2 || summary.TotalPods != 2 {
+		t.Errorf("expected the pods of the first 2 namespaces, got %v", result)
+	}
+	if summary.Cancelled() {
+		t.Error("expected a truncated collection not to count as cancelled")
+	}
+
+	c.SetMaxNamespaces(3)
+	if _, summary, _ := c.GetPodsMemoryInfo(context.Background(), "", true); summary.Truncated || summary.Partial {
+		t.Error("expected no truncation when the cap covers every namespace")
+	}
+}
+
+func TestGetPodsMemoryInfo_IgnoresMetricsOfDeletedPods(t *testing.T) {
+	// "gone" was deleted between the pod list and the metrics list
+	c := newFakeClient(
+		[]runtime.Object{newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi")},
+		newTestPodMetrics("a", "web", "80Mi"),
+		newTestPodMetrics("a", "gone", "500Mi"),
+	)
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "a", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].PodName != "web" {
+		t.Fatalf("expected only pod a/web, got %v", pods)
+	}
+	if summary.PodsWithMetrics != 1 || summary.TotalMemoryUsage.Value() != 80*1024*1024 {
+		t.Errorf("orphan metrics leaked into summary: with_metrics=%d usage=%s",
+			summary.PodsWithMetrics, summary.TotalMemoryUsage.String())
+	}
+}
+
+func TestGetPodsMemoryInfo_PodWithoutMetrics(t *testing.T) {
+	// "new" was created after the metrics were scraped
+	c := newFakeClient(
+		[]runtime.Object{
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("a", "new", corev1.PodRunning, "50Mi", "100Mi"),
+		},
+		newTestPodMetrics("a", "web", "80Mi"),
+	)
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "a", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected both pods, got %d", len(pods))
+	}
+	for i := range pods {
+		if pods[i].PodName == "new" && pods[i].CurrentUsage != nil {
+			t.Errorf("expected no usage for pod without metrics, got %s", pods[i].CurrentUsage.String())
+		}
+	}
+	if summary.PodsWithMetrics != 1 || summary.TotalMemoryLimit.Value() != 300*1024*1024 {
+		t.Errorf("unexpected summary: with_metrics=%d limit=%s",
+			summary.PodsWithMetrics, summary.TotalMemoryLimit.String())
+	}
+}
+
+func TestOrphanMetrics(t *testing.T) {
+	metrics := map[string]*metricsv1beta1.PodMetrics{
+		"web":    newTestPodMetrics("a", "web", "80Mi"),
+		"gone-b": newTestPodMetrics("a", "gone-b", "10Mi"),
+		"gone-a": newTestPodMetrics("a", "gone-a", "10Mi"),
+	}
+
+	got := orphanMetrics(metrics, map[string]bool{"web": true})
+	if len(got) != 2 || got[0] != "gone-a" || got[1] != "gone-b" {
+		t.Errorf("expected [gone-a gone-b], got %v", got)
+	}
+	if got := orphanMetrics(metrics, map[string]bool{"web": true, "gone-a": true, "gone-b": true}); len(got) != 0 {
+		t.Errorf("expected no orphans, got %v", got)
+	}
+}
+
+// testKubeconfig is a minimal kubeconfig with one cluster, user and context named after name
+func testKubeconfig(name string) string {
+	return `apiVersion: v1
+kind: Config
+clusters:
+- name: ` + name + `
+  cluster:
+    server: https://` + name + `.example.com
+users:
+- name: ` + name + `
+  user:
+    token: secret
+contexts:
+- name: ` + name + `
+  context:
+    cluster: ` + name + `
+    user: ` + name + `
+current-context: ` + name + `
+`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestKubeconfigLoadingRules_MergesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b-staging"), testKubeconfig("staging"))
+	writeFile(t, filepath.Join(dir, "a-prod"), testKubeconfig("prod"))
+	writeFile(t, filepath.Join(dir, ".swp"), "not yaml: [")
+	if err := os.Mkdir(filepath.Join(dir, "archive"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := kubeconfigLoadingRules(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	merged, err := rules.Load()
+	if err != nil {
+		t.Fatalf("failed to load merged kubeconfig: %v", err)
+	}
+	if len(merged.Contexts) != 2 || merged.Contexts["prod"] == nil || merged.Contexts["staging"] == nil {
+		t.Errorf("expected prod and staging contexts, got %v", merged.Contexts)
+	}
+	if merged.CurrentContext != "prod" {
+		t.Errorf("expected the first file in name order to set the current context, got %q", merged.CurrentContext)
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+		&clientcmd.ConfigOverrides{CurrentContext: "staging"}).ClientConfig()
+	if err != nil || config.Host != "https://staging.example.com" {
+		t.Errorf("expected the staging server, got %v (%v)", config, err)
+	}
+}
+
+func TestKubeconfigLoadingRules_Errors(t *testing.T) {
+	empty := t.TempDir()
+	if err := os.Mkdir(filepath.Join(empty, "nested"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kubeconfigLoadingRules(empty); err == nil || !strings.Contains(err.Error(), "contains no kubeconfig files") {
+		t.Errorf("expected an empty directory error, got %v", err)
+	}
+
+	broken := t.TempDir()
+	writeFile(t, filepath.Join(broken, "good"), testKubeconfig("prod"))
+	writeFile(t, filepath.Join(broken, "bad"), "clusters: [unterminated")
+	if _, err := kubeconfigLoadingRules(broken); err == nil || !strings.Contains(err.Error(), filepath.Join(broken, "bad")) {
+		t.Errorf("expected a parse error naming the bad file, got %v", err)
+	}
+}
+
+func TestKubeconfigLoadingRules_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	writeFile(t, path, testKubeconfig("prod"))
+	rules, err := kubeconfigLoadingRules(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules.ExplicitPath != path || len(rules.Precedence) != 0 {
+		t.Errorf("expected the file as explicit path, got %+v", rules)
+	}
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import "time"
+
+// Clock provides the current time for report timestamps and metrics ages
+// Tests replace it to make time-dependent values deterministic
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock backed by time.Now
+type RealClock struct{}
+
+// Now returns the current local time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock replaces the time source used for timestamps
+// Collection timings still measure real elapsed time
+func (c *Client) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// now returns the current time from the client's clock, falling back to real time
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fixedClock always reports the same time
+type fixedClock struct {
+	t time.Time
+}
+
+func (f fixedClock) Now() time.Time {
+	return f.t
+}
+
+func TestClient_NowDefaultsToRealTime(t *testing.T) {
+	before := time.Now()
+	got := (&Client{}).now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("expected the current time, got %v", got)
+	}
+}
+
+func TestGetPodsMemoryInfo_UsesClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pod := newTestPod("default", "app", "Running", "100Mi", "200Mi")
+	c := newFakeClient([]runtime.Object{pod})
+	c.SetClock(fixedClock{now})
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "default", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !summary.Timestamp.Equal(now) {
+		t.Errorf("expected summary timestamp %v, got %v", now, summary.Timestamp)
+	}
+	for _, p := range pods {
+		if !p.Timestamp.Equal(now) {
+			t.Errorf("expected pod timestamp %v, got %v", now, p.Timestamp)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodConditionInfo describes a pod condition that is not satisfied
+type PodConditionInfo struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// conditionPriority orders failing conditions from root cause to symptom:
+// a pod that is not scheduled cannot initialize, and uninitialized pods cannot have ready containers
+var conditionPriority = map[corev1.PodConditionType]int{
+	corev1.PodScheduled:    0,
+	corev1.PodInitialized:  1,
+	corev1.ContainersReady: 2,
+	// Readiness gates fall between ContainersReady and Ready
+	corev1.PodReady: 4,
+}
+
+// readinessGatePriority is used for custom readiness gate conditions
+const readinessGatePriority = 3
+
+// failingConditions returns the unsatisfied pod conditions, most informative first
+// Readiness gates declared in the spec without a matching status condition are reported as missing
+func failingConditions(pod *corev1.Pod) []PodConditionInfo {
+	priorities := make(map[string]int)
+	seen := make(map[corev1.PodConditionType]bool)
+	var failing []PodConditionInfo
+
+	for _, condition := range pod.Status.Conditions {
+		seen[condition.Type] = true
+		if condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		priority, known := conditionPriority[condition.Type]
+		if !known {
+			priority = readinessGatePriority
+		}
+		priorities[string(condition.Type)] = priority
+		failing = append(failing, PodConditionInfo{
+			Type:    string(condition.Type),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	for _, gate := range pod.Spec.ReadinessGates {
+		if seen[gate.ConditionType] {
+			continue
+		}
+		priorities[string(gate.ConditionType)] = readinessGatePriority
+		failing = append(failing, PodConditionInfo{
+			Type:   string(gate.ConditionType),
+			Reason: "ReadinessGateMissing",
+		})
+	}
+
+	sort.SliceStable(failing, func(i, j int) bool {
+		return priorities[failing[i].Type] < priorities[failing[j].Type]
+	})
+	return failing
+}
+
+// UnschedulableCondition returns the PodScheduled condition of a pending pod the scheduler could not place
+// It is nil for pods that are scheduled, or still waiting for a scheduling decision
+func (p *PodMemoryInfo) UnschedulableCondition() *PodConditionInfo {
+	if p.Phase != string(corev1.PodPending) {
+		return nil
+	}
+	for i := range p.NotReadyConditions {
+		condition := &p.NotReadyConditions[i]
+		if condition.Type == string(corev1.PodScheduled) && condition.Reason == corev1.PodReasonUnschedulable {
+			return condition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFailingConditions_OrdersRootCauseFirst(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"},
+				{Type: corev1.ContainersReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"},
+				{Type: corev1.PodInitialized, Status: corev1.ConditionTrue},
+				{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"},
+			},
+		},
+	}
+
+	failing := failingConditions(pod)
+	expected := []string{"PodScheduled", "ContainersReady", "Ready"}
+	if len(failing) != len(expected) {
+		t.Fatalf("expected %d failing conditions, got %+v", len(expected), failing)
+	}
+	for i, conditionType := range expected {
+		if failing[i].Type != conditionType {
+			t.Errorf("position %d: expected %s, got %s", i, conditionType, failing[i].Type)
+		}
+	}
+	if failing[0].Message != "0/3 nodes are available" {
+		t.Errorf("expected scheduler message to be kept, got %q", failing[0].Message)
+	}
+}
+
+func TestFailingConditions_ReadinessGates(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{
+				{ConditionType: "example.com/load-balancer-ready"},
+				{ConditionType: "example.com/feature-ready"},
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ReadinessGatesNotReady"},
+				{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+				{Type: "example.com/feature-ready", Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	failing := failingConditions(pod)
+	if len(failing) != 2 {
+		t.Fatalf("expected 2 failing conditions, got %+v", failing)
+	}
+	if failing[0].Type != "example.com/load-balancer-ready" || failing[0].Reason != "ReadinessGateMissing" {
+		t.Errorf("expected missing readiness gate first, got %+v", failing[0])
+	}
+}
+
+func TestProcessPodMemoryInfo_NotReadyReason(t *testing.T) {
+	pod := newTestPod("ns", "p", corev1.PodPending, "", "")
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable"},
+	}
+
+	info := newFakeClient(nil).processPodMemoryInfo(pod, nil)
+	if got := info.NotReadyReason(); got != "PodScheduled: Unschedulable" {
+		t.Errorf("expected scheduling reason, got %q", got)
+	}
+
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	info = newFakeClient(nil).processPodMemoryInfo(pod, nil)
+	if info.NotReadyReason() != "" || len(info.NotReadyConditions) != 0 {
+		t.Errorf("expected no reason for a ready pod, got %+v", info.NotReadyConditions)
+	}
+}
+
+func TestUnschedulableCondition(t *testing.T) {
+	pod := newTestPod("ns", "p", corev1.PodPending, "", "")
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+			Message: "0/3 nodes are available: 3 Insufficient memory."},
+	}
+	info := newFakeClient(nil).processPodMemoryInfo(pod, nil)
+	condition := info.UnschedulableCondition()
+	if condition == nil || condition.Message != "0/3 nodes are available: 3 Insufficient memory." {
+		t.Fatalf("expected the scheduler message, got %+v", condition)
+	}
+
+	// A pod merely waiting for the scheduler is not unschedulable
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse}}
+	info = newFakeClient(nil).processPodMemoryInfo(pod, nil)
+	if condition := info.UnschedulableCondition(); condition != nil {
+		t.Errorf("expected no unschedulable condition, got %+v", condition)
+	}
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import "path"
+
+// ContainerFilter selects which containers are reported and aggregated per pod
+type ContainerFilter struct {
+	Include []string // When non-empty, only these container names are kept
+	Exclude []string // Container names that are always dropped
+
+	// Name patterns (path.Match syntax) of pause/sandbox containers some metrics setups report,
+	// dropped from rows and pod usage unless IncludeSystem is set
+	System        []string
+	IncludeSystem bool
+}
+
+// IsSystem reports whether the container name matches one of the system container patterns
+func (f ContainerFilter) IsSystem(name string) bool {
+	for _, pattern := range f.System {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the filter restricts containers at all
+func (f ContainerFilter) IsActive() bool {
+	return len(f.Include) > 0 || len(f.Exclude) > 0
+}
+
+// Matches reports whether a container with the given name should be kept
+func (f ContainerFilter) Matches(name string) bool {
+	if !f.IncludeSystem && f.IsSystem(name) {
+		return false
+	}
+	for _, excluded := range f.Exclude {
+		if excluded == name {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, included := range f.Include {
+		if included == name {
+			return true
+		}
+	}
+	return false
+}
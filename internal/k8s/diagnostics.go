@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultDiagnosticNamespace is probed for pod and metrics access when no namespace is configured
+const defaultDiagnosticNamespace = "default"
+
+// DiagnosticResult holds the outcome of a single connectivity or permission check
+type DiagnosticResult struct {
+	Name string
+	Err  error
+}
+
+// Passed reports whether the check succeeded
+func (d DiagnosticResult) Passed() bool {
+	return d.Err == nil
+}
+
+// Diagnose runs connectivity, RBAC and metrics-server checks in sequence
+// Every check is executed even when an earlier one fails so the full picture is reported
+func (c *Client) Diagnose(ctx context.Context, namespace string) []DiagnosticResult {
+	if namespace == "" {
+		namespace = defaultDiagnosticNamespace
+	}
+	probe := metav1.ListOptions{Limit: 1}
+
+	return []DiagnosticResult{
+		{Name: "Connect to API server", Err: c.HealthCheck(ctx)},
+		{Name: "List namespaces", Err: c.listNamespacesProbe(ctx, probe)},
+		{Name: fmt.Sprintf("List pods in namespace %s", namespace), Err: c.listPodsProbe(ctx, namespace, probe)},
+		{Name: fmt.Sprintf("Read pod metrics in namespace %s", namespace), Err: c.listMetricsProbe(ctx, namespace, probe)},
+	}
+}
+
+func (c *Client) listNamespacesProbe(ctx context.Context, opts metav1.ListOptions) error {
+	_, err := c.clientset.CoreV1().Namespaces().List(ctx, opts)
+	return err
+}
+
+func (c *Client) listPodsProbe(ctx context.Context, namespace string, opts metav1.ListOptions) error {
+	_, err := c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	return err
+}
+
+func (c *Client) listMetricsProbe(ctx context.Context, namespace string, opts metav1.ListOptions) error {
+	_, err := c.listPodMetrics(ctx, namespace, opts)
+	return err
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func TestDiagnose_AllChecksPass(t *testing.T) {
+	c := &Client{clientset: fake.NewSimpleClientset(), metricsClient: metricsfake.NewSimpleClientset()}
+
+	results := c.Diagnose(context.Background(), "")
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(results))
+	}
+	for _, r := range results {
+		if !r.Passed() {
+			t.Errorf("expected %q to pass, got %v", r.Name, r.Err)
+		}
+	}
+	if results[2].Name != "List pods in namespace default" {
+		t.Errorf("expected default namespace to be probed, got %q", results[2].Name)
+	}
+}
+
+func TestDiagnose_ReportsFailedCheck(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	c := &Client{clientset: clientset, metricsClient: metricsfake.NewSimpleClientset()}
+
+	results := c.Diagnose(context.Background(), "prod")
+
+	if results[2].Passed() {
+		t.Fatalf("expected pod listing check to fail")
+	}
+	if !results[3].Passed() {
+		t.Errorf("expected metrics check to still run and pass, got %v", results[3].Err)
+	}
+}
@@ -0,0 +1,45 @@
+package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+// SetEnvVars selects container environment variables recorded on each container, e.g. DAG_ID for Airflow tasks
+// Only literal values are read from the spec; variables set through valueFrom are skipped
+func (c *Client) SetEnvVars(names []string) {
+	c.envVarNames = names
+}
+
+// containerEnvVars returns the literal values of the requested variables defined by the container
+func containerEnvVars(container *corev1.Container, names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	var values map[string]string
+	for _, name := range names {
+		// Later definitions of a name win, as in the container runtime
+		for i := len(container.Env) - 1; i >= 0; i-- {
+			env := &container.Env[i]
+			if env.Name != name {
+				continue
+			}
+			if env.ValueFrom == nil {
+				if values == nil {
+					values = make(map[string]string)
+				}
+				values[name] = env.Value
+			}
+			break
+		}
+	}
+	return values
+}
+
+// EnvVar returns the value of an environment variable recorded on the pod's containers
+// Containers are searched in listing order, so the first container defining it wins
+func (p *PodMemoryInfo) EnvVar(name string) (string, bool) {
+	for i := range p.Containers {
+		if value, ok := p.Containers[i].EnvVars[name]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -2,16 +2,23 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
+// suggestedPodListRule is the RBAC rule needed to collect pods from a namespace
+const suggestedPodListRule = `apiGroups: [""], resources: ["pods"], verbs: ["list"]`
+
 // GetAllPodsMemoryInfo retrieves memory information for all pods across all namespaces
 func (c *Client) GetAllPodsMemoryInfo(ctx context.Context) ([]PodMemoryInfo, *MemorySummary, error) {
 	return c.GetPodsMemoryInfo(ctx, "", true)
@@ -26,33 +33,43 @@ func (c *Client) GetPodsMemoryInfo(ctx context.Context, namespace string, allNam
 		return nil, nil, fmt.Errorf("cannot specify both namespace and allNamespaces")
 	}
 
+	c.podCache.begin()
+	var pods []PodMemoryInfo
+	var summary *MemorySummary
+	var err error
 	if namespace != "" {
 		// Monitor specific namespace
 		slog.Info("Starting to collect memory information for specific namespace", "namespace", namespace)
-		return c.getSingleNamespacePodsMemoryInfo(ctx, namespace)
+		pods, summary, err = c.getSingleNamespacePodsMemoryInfo(ctx, namespace)
+	} else {
+		// Monitor all namespaces, also the default when neither is set
+		if allNamespaces {
+			slog.Info("Starting to collect memory information for all namespaces")
+		}
+		pods, summary, err = c.getAllNamespacesPodsMemoryInfo(ctx)
 	}
-
-	if allNamespaces {
-		// Monitor all namespaces
-		slog.Info("Starting to collect memory information for all namespaces")
-		return c.getAllNamespacesPodsMemoryInfo(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	// Default behavior (should not reach here with current config logic)
-	return c.getAllNamespacesPodsMemoryInfo(ctx)
+	c.podCache.commit(summary, c.now())
+	return pods, summary, nil
 }
 
 // getSingleNamespacePodsMemoryInfo gets memory info for pods in a single namespace
 func (c *Client) getSingleNamespacePodsMemoryInfo(ctx context.Context, namespace string) (
 	[]PodMemoryInfo, *MemorySummary, error) {
-	pods, nsUsage, err := c.getNamespacePodsMemoryInfo(ctx, namespace)
+	pods, nsUsage, err := c.getNamespacePodsMemoryInfo(ctx, namespace, nil)
+	if apierrors.IsForbidden(err) {
+		return nil, nil, fmt.Errorf("permission denied for namespace %s, grant the rule {%s}: %w",
+			namespace, suggestedPodListRule, err)
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get pods for namespace %s: %w", namespace, err)
 	}
 
 	// Create summary for single namespace
 	summary := &MemorySummary{
-		Timestamp:          time.Now(),
+		Timestamp:          c.now(),
 		NamespaceCount:     1,
 		TotalPods:          len(pods),
 		TotalMemoryUsage:   nsUsage.TotalMemoryUsage,
@@ -62,6 +79,7 @@ func (c *Client) getSingleNamespacePodsMemoryInfo(ctx context.Context, namespace
 		PodsWithMetrics:    nsUsage.PodsWithMetrics,
 		PodsWithLimits:     nsUsage.PodsWithLimits,
 		PodsWithRequests:   nsUsage.PodsWithRequests,
+		Timings:            nsUsage.Timings,
 	}
 
 	slog.Info("Memory collection completed for namespace",
@@ -69,7 +87,7 @@ func (c *Client) getSingleNamespacePodsMemoryInfo(ctx context.Context, namespace
 		"total_pods", summary.TotalPods,
 		"running_pods", summary.RunningPods,
 		"pods_with_metrics", summary.PodsWithMetrics,
-		"total_usage", FormatMemory(&summary.TotalMemoryUsage))
+		"total_usage", FormatMemoryTotal(&summary.TotalMemoryUsage))
 
 	return pods, summary, nil
 }
@@ -77,6 +95,7 @@ func (c *Client) getSingleNamespacePodsMemoryInfo(ctx context.Context, namespace
 // getAllNamespacesPodsMemoryInfo gets memory info for all namespaces
 func (c *Client) getAllNamespacesPodsMemoryInfo(ctx context.Context) ([]PodMemoryInfo, *MemorySummary, error) {
 	// Get all namespaces
+	c.apiCalls.namespaceLists.Add(1)
 	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
@@ -85,26 +104,56 @@ func (c *Client) getAllNamespacesPodsMemoryInfo(ctx context.Context) ([]PodMemor
 	slog.Info("Found namespaces", "count", len(namespaces.Items))
 
 	var allPods []PodMemoryInfo
+	var nodeNamespaces int
+	var namespaceErrs []error
 	summary := &MemorySummary{
-		Timestamp:          time.Now(),
+		Timestamp:          c.now(),
 		NamespaceCount:     len(namespaces.Items),
 		TotalMemoryUsage:   *resource.NewQuantity(0, resource.BinarySI),
 		TotalMemoryLimit:   *resource.NewQuantity(0, resource.BinarySI),
 		TotalMemoryRequest: *resource.NewQuantity(0, resource.BinarySI),
 	}
 
+	// With sampling, all namespaces share one round of cluster-wide reads
+	sampled, sampleTime := c.clusterSampledMetrics(ctx)
+	summary.Timings.Metrics += sampleTime
+
 	// Process each namespace
 	for i := range namespaces.Items {
+		if ctx.Err() != nil {
+			summary.markPartial(i)
+			break
+		}
+		if c.maxNamespaces > 0 && i >= c.maxNamespaces {
+			summary.markTruncated(i)
+			break
+		}
 		nsName := namespaces.Items[i].Name
 		slog.Debug("Processing namespace", "namespace", nsName)
 
-		pods, nsUsage, err := c.getNamespacePodsMemoryInfo(ctx, nsName)
+		pods, nsUsage, err := c.getNamespacePodsMemoryInfo(ctx, nsName, sampled)
+		if err != nil && ctx.Err() != nil {
+			// The namespace was interrupted part way, so its pods are left out
+			summary.markPartial(i)
+			break
+		}
+		if err != nil && c.strict {
+			namespaceErrs = append(namespaceErrs, fmt.Errorf("namespace %s: %w", nsName, err))
+			continue
+		}
+		if apierrors.IsForbidden(err) {
+			summary.ForbiddenNamespaces = append(summary.ForbiddenNamespaces, nsName)
+			continue
+		}
 		if err != nil {
 			slog.Warn("Failed to get pods for namespace", "namespace", nsName, "error", err)
 			continue
 		}
 
 		allPods = append(allPods, pods...)
+		if c.nodeName != "" && len(pods) > 0 {
+			nodeNamespaces++
+		}
 
 		// Update summary
 		summary.TotalPods += len(pods)
@@ -115,79 +164,246 @@ func (c *Client) getAllNamespacesPodsMemoryInfo(ctx context.Context) ([]PodMemor
 		summary.PodsWithMetrics += nsUsage.PodsWithMetrics
 		summary.PodsWithLimits += nsUsage.PodsWithLimits
 		summary.PodsWithRequests += nsUsage.PodsWithRequests
+		summary.Timings.add(nsUsage.Timings)
+	}
+
+	if len(namespaceErrs) > 0 {
+		return nil, nil, fmt.Errorf("failed to collect %d of %d namespaces: %w",
+			len(namespaceErrs), len(namespaces.Items), errors.Join(namespaceErrs...))
+	}
+
+	if c.nodeName != "" {
+		// Only namespaces with pods on the node are relevant to the report
+		summary.NamespaceCount = nodeNamespaces
+	}
+
+	if summary.Truncated {
+		slog.Warn("Namespace limit reached, results are truncated",
+			"max_namespaces", c.maxNamespaces,
+			"namespaces_total", len(namespaces.Items))
+	} else if summary.Partial {
+		slog.Warn("Collection cancelled, results are partial",
+			"namespaces_collected", summary.NamespacesCollected,
+			"namespaces_total", len(namespaces.Items))
+	}
+	if len(summary.ForbiddenNamespaces) > 0 {
+		slog.Warn("Permission denied listing pods, results are partial",
+			"forbidden_namespaces", summary.ForbiddenNamespaces,
+			"suggested_rule", suggestedPodListRule)
 	}
 
 	slog.Info("Memory collection completed",
 		"total_pods", summary.TotalPods,
 		"running_pods", summary.RunningPods,
 		"pods_with_metrics", summary.PodsWithMetrics,
-		"total_usage", FormatMemory(&summary.TotalMemoryUsage))
+		"total_usage", FormatMemoryTotal(&summary.TotalMemoryUsage))
 
 	return allPods, summary, nil
 }
 
 // getNamespacePodsMemoryInfo gets memory info for pods in a specific namespace
-func (c *Client) getNamespacePodsMemoryInfo(ctx context.Context, namespace string) (
+// sampled holds metrics already sampled cluster-wide, keyed by namespace; nil reads the namespace's own metrics
+func (c *Client) getNamespacePodsMemoryInfo(ctx context.Context, namespace string,
+	sampled map[string]map[string]*metricsv1beta1.PodMetrics) (
 	[]PodMemoryInfo, *MemorySummary, error) {
-	// Get all pods in the namespace
-	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	podInfos := []PodMemoryInfo{}
+	summary := &MemorySummary{
+		TotalMemoryUsage:   *resource.NewQuantity(0, resource.BinarySI),
+		TotalMemoryLimit:   *resource.NewQuantity(0, resource.BinarySI),
+		TotalMemoryRequest: *resource.NewQuantity(0, resource.BinarySI),
+	}
+
+	// Page through the pods in the namespace, processing each page before requesting the next
+	var metricsMap map[string]*metricsv1beta1.PodMetrics
+	listed := make(map[string]bool)
+	opts := metav1.ListOptions{Limit: c.listPageSize, LabelSelector: c.labelSelector}
+	if c.nodeName != "" {
+		// Filter server-side so only the node's pods are transferred
+		opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", c.nodeName).String()
+	}
+	for {
+		start := time.Now()
+		pods, err := withRetry(ctx, c.retryPolicy, "list pods", func() (*corev1.PodList, error) {
+			c.apiCalls.podRequests.Add(1)
+			return c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		})
+		summary.Timings.PodList += time.Since(start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+		c.apiCalls.podsListed.Add(int64(len(pods.Items)))
+
+		// Metrics are only fetched once the namespace is known to be listable and has matching pods
+		if metricsMap == nil && len(pods.Items) > 0 {
+			metricsMap, summary.Timings.Metrics = c.namespacePodMetrics(ctx, namespace, sampled)
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			listed[pod.Name] = true
+			podInfo := c.processPodMemoryInfo(pod, metricsMap[pod.Name])
+			if c.containerFilter.IsActive() && len(podInfo.Containers) == 0 {
+				// No selected containers in this pod, nothing to report
+				continue
+			}
+			podInfos = append(podInfos, podInfo)
+			addPodToSummary(summary, pod, &podInfo)
+			c.podCache.record(pod)
+		}
+
+		if pods.Continue == "" {
+			break
+		}
+		opts.Continue = pods.Continue
 	}
 
-	// Get metrics for the namespace (this might fail if metrics-server is not available)
-	var podMetrics *metricsv1beta1.PodMetricsList
-	podMetrics, err = c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	c.apiCalls.namespacesProcessed.Add(1)
+
+	// With a node filter the metrics still cover every pod of the namespace, so unmatched ones are expected
+	if c.nodeName == "" {
+		if orphans := orphanMetrics(metricsMap, listed); len(orphans) > 0 {
+			slog.Debug("Ignoring metrics of pods that are no longer listed",
+				"namespace", namespace, "pods", orphans)
+		}
+	}
+
+	return podInfos, summary, nil
+}
+
+// orphanMetrics returns the sorted names of pods with metrics but no listed spec,
+// typically pods deleted between the pod list and the metrics list
+func orphanMetrics(metricsMap map[string]*metricsv1beta1.PodMetrics, listed map[string]bool) []string {
+	var orphans []string
+	for name := range metricsMap {
+		if !listed[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// namespacePodMetrics returns the pod metrics of a namespace keyed by pod name, and how long fetching them took
+// A failure is logged and yields an empty map, since limits and requests can still be reported.
+// With sampling, usage is averaged over several reads and the time spent waiting between them is included.
+// Metrics already sampled cluster-wide are looked up instead, their time being counted once by the caller
+func (c *Client) namespacePodMetrics(ctx context.Context, namespace string,
+	sampled map[string]map[string]*metricsv1beta1.PodMetrics) (map[string]*metricsv1beta1.PodMetrics, time.Duration) {
+	if !c.MetricsEnabled() {
+		return make(map[string]*metricsv1beta1.PodMetrics), 0
+	}
+	if sampled != nil {
+		if metricsMap, ok := sampled[namespace]; ok {
+			return metricsMap, 0
+		}
+		return make(map[string]*metricsv1beta1.PodMetrics), 0
+	}
+	start := time.Now()
+	metricsMap := c.readNamespacePodMetrics(ctx, namespace)
+	if metricsMap == nil {
+		// Continue without metrics - we can still show limits/requests
+		metricsMap = make(map[string]*metricsv1beta1.PodMetrics)
+	}
+	metricsMap = c.sampledMetrics(ctx, metricsMap, func() map[string]*metricsv1beta1.PodMetrics {
+		return c.readNamespacePodMetrics(ctx, namespace)
+	})
+	return metricsMap, time.Since(start)
+}
+
+// readNamespacePodMetrics lists the pod metrics of a namespace once, keyed by pod name
+// A failure is logged and yields nil
+func (c *Client) readNamespacePodMetrics(ctx context.Context, namespace string) map[string]*metricsv1beta1.PodMetrics {
+	podMetrics, err := withRetry(ctx, c.retryPolicy, "list pod metrics", func() (*metricsv1beta1.PodMetricsList, error) {
+		// The metrics API honors label selectors, so only the metrics of the selected pods are transferred
+		return c.listPodMetrics(ctx, namespace, metav1.ListOptions{LabelSelector: c.labelSelector})
+	})
 	if err != nil {
 		slog.Warn("Failed to get pod metrics for namespace", "namespace", namespace, "error", err)
-		// Continue without metrics - we can still show limits/requests
+		return nil
+	}
+
+	// Fill the map of pod metrics for quick lookup
+	metricsMap := make(map[string]*metricsv1beta1.PodMetrics, len(podMetrics.Items))
+	for i := range podMetrics.Items {
+		pm := &podMetrics.Items[i]
+		metricsMap[pm.Name] = pm
+	}
+	return metricsMap
+}
+
+// GetSinglePodMemoryInfo retrieves memory information for one pod by name
+// Missing metrics are tolerated so limits and requests are still reported
+func (c *Client) GetSinglePodMemoryInfo(ctx context.Context, namespace, podName string) (
+	[]PodMemoryInfo, *MemorySummary, error) {
+	slog.Info("Starting to collect memory information for pod", "namespace", namespace, "pod", podName)
+
+	var timings CollectionTimings
+	start := time.Now()
+	pod, err := withRetry(ctx, c.retryPolicy, "get pod", func() (*corev1.Pod, error) {
+		c.apiCalls.podRequests.Add(1)
+		return c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	})
+	timings.PodList = time.Since(start)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, podName, err)
 	}
 
-	// Create a map of pod metrics for quick lookup
-	metricsMap := make(map[string]*metricsv1beta1.PodMetrics)
-	if podMetrics != nil {
-		for i := range podMetrics.Items {
-			pm := &podMetrics.Items[i]
-			metricsMap[pm.Name] = pm
+	var podMetrics *metricsv1beta1.PodMetrics
+	if c.MetricsEnabled() {
+		start = time.Now()
+		read := func() map[string]*metricsv1beta1.PodMetrics {
+			pm, err := withRetry(ctx, c.retryPolicy, "get pod metrics", func() (*metricsv1beta1.PodMetrics, error) {
+				return c.getPodMetrics(ctx, namespace, podName)
+			})
+			if err != nil {
+				slog.Warn("Failed to get pod metrics", "namespace", namespace, "pod", podName, "error", err)
+				return nil
+			}
+			return map[string]*metricsv1beta1.PodMetrics{podName: pm}
 		}
+		podMetrics = c.sampledMetrics(ctx, read(), read)[podName]
+		timings.Metrics = time.Since(start)
 	}
 
-	podInfos := make([]PodMemoryInfo, 0, len(pods.Items))
+	c.apiCalls.podsListed.Add(1)
+	c.apiCalls.namespacesProcessed.Add(1)
+
+	podInfo := c.processPodMemoryInfo(pod, podMetrics)
 	summary := &MemorySummary{
+		Timestamp:          c.now(),
+		NamespaceCount:     1,
+		TotalPods:          1,
 		TotalMemoryUsage:   *resource.NewQuantity(0, resource.BinarySI),
 		TotalMemoryLimit:   *resource.NewQuantity(0, resource.BinarySI),
 		TotalMemoryRequest: *resource.NewQuantity(0, resource.BinarySI),
+		Timings:            timings,
 	}
+	addPodToSummary(summary, pod, &podInfo)
 
-	// Process each pod
-	for i := range pods.Items {
-		pod := &pods.Items[i]
-		podInfo := c.processPodMemoryInfo(pod, metricsMap[pod.Name])
-		podInfos = append(podInfos, podInfo)
+	return []PodMemoryInfo{podInfo}, summary, nil
+}
 
-		// Update summary
-		if pod.Status.Phase == corev1.PodRunning {
-			summary.RunningPods++
-		}
-		if podInfo.CurrentUsage != nil {
-			summary.PodsWithMetrics++
-			summary.TotalMemoryUsage.Add(*podInfo.CurrentUsage)
-		}
-		if podInfo.MemoryRequest != nil {
-			summary.PodsWithRequests++
-			summary.TotalMemoryRequest.Add(*podInfo.MemoryRequest)
-		}
-		if podInfo.MemoryLimit != nil {
-			summary.PodsWithLimits++
-			summary.TotalMemoryLimit.Add(*podInfo.MemoryLimit)
-		}
+// addPodToSummary accumulates a pod's status and memory figures into the summary counters
+func addPodToSummary(summary *MemorySummary, pod *corev1.Pod, podInfo *PodMemoryInfo) {
+	if pod.Status.Phase == corev1.PodRunning {
+		summary.RunningPods++
+	}
+	if podInfo.CurrentUsage != nil {
+		summary.PodsWithMetrics++
+		summary.TotalMemoryUsage.Add(*podInfo.CurrentUsage)
+	}
+	if podInfo.MemoryRequest != nil {
+		summary.PodsWithRequests++
+		summary.TotalMemoryRequest.Add(*podInfo.MemoryRequest)
+	}
+	if podInfo.MemoryLimit != nil {
+		summary.PodsWithLimits++
+		summary.TotalMemoryLimit.Add(*podInfo.MemoryLimit)
 	}
-
-	return podInfos, summary, nil
 }
 
 func (c *Client) processContainerMemoryInfo(container *corev1.Container, usage corev1.ResourceList) (ContainerMemoryInfo, int64, int64, bool, bool) {
-	info := ContainerMemoryInfo{ContainerName: container.Name}
+	info := ContainerMemoryInfo{ContainerName: container.Name, Image: container.Image}
 	var req, lim int64
 	if r, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
 		req = r.Value()
@@ -203,6 +419,7 @@ func (c *Client) processContainerMemoryInfo(container *corev1.Container, usage c
 		v := u
 		info.CurrentUsage = &v
 	}
+	info.RequestLimitRatio = requestLimitRatio(info.MemoryRequest, info.MemoryLimit)
 	return info, req, lim, info.MemoryRequest != nil, info.MemoryLimit != nil
 }
 
@@ -234,17 +451,82 @@ func (c *Client) aggregatePodResources(containers []ContainerMemoryInfo) (*resou
 	return reqQ, limQ, hasReq, hasLim
 }
 
+// allocatedContainer returns the container with the resources the kubelet reports as applied in its status
+// After an in-place resize these may differ from the spec; the spec is used when the status has none
+func allocatedContainer(pod *corev1.Pod, container *corev1.Container) *corev1.Container {
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if status.Name != container.Name || status.Resources == nil {
+			continue
+		}
+		allocated := *container
+		allocated.Resources = *status.Resources
+		return &allocated
+	}
+	return container
+}
+
+// mergeAnnotationsIntoLabels adds the values of the given annotations to labels
+// Labels take precedence: an annotation whose key is already a label is ignored
+func mergeAnnotationsIntoLabels(labels, annotations map[string]string, keys []string) {
+	for _, key := range keys {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		if _, isLabel := labels[key]; isLabel {
+			continue
+		}
+		labels[key] = value
+	}
+}
+
+// setRestartInfo copies the container's restart count and the reason its previous run ended from the pod status
+func setRestartInfo(cm *ContainerMemoryInfo, pod *corev1.Pod) {
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if status.Name != cm.ContainerName {
+			continue
+		}
+		cm.RestartCount = status.RestartCount
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			cm.LastTermination = terminated.Reason
+		}
+		return
+	}
+}
+
+// setMetricsAge records when the usage sample was taken and how old its oldest data point is
+// The age covers the whole sample window, since usage is averaged over it
+func setMetricsAge(podInfo *PodMemoryInfo, metrics *metricsv1beta1.PodMetrics) {
+	if metrics.Timestamp.IsZero() {
+		return
+	}
+	timestamp := metrics.Timestamp.Time
+	age := podInfo.Timestamp.Sub(timestamp) + metrics.Window.Duration
+	podInfo.MetricsTimestamp = &timestamp
+	podInfo.MetricsAge = &age
+}
+
 // processPodMemoryInfo creates PodMemoryInfo from pod spec and metrics
 func (c *Client) processPodMemoryInfo(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) PodMemoryInfo {
 	podInfo := PodMemoryInfo{
+		Cluster:     c.cluster,
 		Namespace:   pod.Namespace,
 		PodName:     pod.Name,
-		Timestamp:   time.Now(),
+		Timestamp:   c.now(),
 		Phase:       string(pod.Status.Phase),
 		Ready:       c.isPodReady(pod),
+		NodeName:    pod.Spec.NodeName,
+		QOSClass:    string(pod.Status.QOSClass),
+		CreatedAt:   pod.CreationTimestamp.Time,
+		Workload:    workloadOf(pod),
 		Labels:      make(map[string]string),
 		Annotations: make(map[string]string),
 	}
+	if !podInfo.Ready {
+		podInfo.NotReadyConditions = failingConditions(pod)
+	}
 
 	// Copy pod labels and annotations
 	for k, v := range pod.Labels {
@@ -253,6 +535,7 @@ func (c *Client) processPodMemoryInfo(pod *corev1.Pod, metrics *metricsv1beta1.P
 	for k, v := range pod.Annotations {
 		podInfo.Annotations[k] = v
 	}
+	mergeAnnotationsIntoLabels(podInfo.Labels, pod.Annotations, c.annotationKeys)
 
 	// Build a map of metrics by container name
 	metricsByName := make(map[string]corev1.ResourceList)
@@ -260,15 +543,29 @@ func (c *Client) processPodMemoryInfo(pod *corev1.Pod, metrics *metricsv1beta1.P
 		for _, m := range metrics.Containers {
 			metricsByName[m.Name] = m.Usage
 		}
+		setMetricsAge(&podInfo, metrics)
+		podInfo.MetricSource = MetricSourceWorkingSet
 	}
 
 	podInfo.Containers = make([]ContainerMemoryInfo, 0, len(pod.Spec.Containers))
 	for i := range pod.Spec.Containers {
 		container := &pod.Spec.Containers[i]
+		if !c.containerFilter.Matches(container.Name) {
+			continue
+		}
 		usage := metricsByName[container.Name]
-		cm, _, _, _, _ := c.processContainerMemoryInfo(container, usage)
+		cm, _, _, _, _ := c.processContainerMemoryInfo(allocatedContainer(pod, container), usage)
+		cm.EnvVars = containerEnvVars(container, c.envVarNames)
+		setRestartInfo(&cm, pod)
 		podInfo.Containers = append(podInfo.Containers, cm)
 	}
+	if c.containerFilter.IncludeSystem && metrics != nil {
+		podInfo.Containers = append(podInfo.Containers, c.systemContainers(pod, metrics)...)
+	}
+	// Spec order varies between otherwise identical pods, so containers are listed by name for stable output
+	sort.Slice(podInfo.Containers, func(i, j int) bool {
+		return podInfo.Containers[i].ContainerName < podInfo.Containers[j].ContainerName
+	})
 
 	req, lim, hasReq, hasLim := c.aggregatePodResources(podInfo.Containers)
 	if hasReq {
@@ -278,22 +575,67 @@ func (c *Client) processPodMemoryInfo(pod *corev1.Pod, metrics *metricsv1beta1.P
 		podInfo.MemoryLimit = lim
 	}
 
-	podInfo.CurrentUsage = c.calculatePodUsageFromMetrics(metrics)
+	if c.containerFilter.IsActive() {
+		podInfo.CurrentUsage = sumContainerUsage(podInfo.Containers)
+	} else {
+		podInfo.CurrentUsage = c.calculatePodUsageFromMetrics(metrics)
+	}
 
 	return podInfo
 }
 
+// systemContainers builds rows for the system containers, such as the pause container, that appear
+// in the pod's metrics without being part of its spec
+func (c *Client) systemContainers(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) []ContainerMemoryInfo {
+	inSpec := make(map[string]bool, len(pod.Spec.Containers))
+	for i := range pod.Spec.Containers {
+		inSpec[pod.Spec.Containers[i].Name] = true
+	}
+	var containers []ContainerMemoryInfo
+	for i := range metrics.Containers {
+		m := &metrics.Containers[i]
+		if inSpec[m.Name] || !c.containerFilter.IsSystem(m.Name) || !c.containerFilter.Matches(m.Name) {
+			continue
+		}
+		cm, _, _, _, _ := c.processContainerMemoryInfo(&corev1.Container{Name: m.Name}, m.Usage)
+		containers = append(containers, cm)
+	}
+	return containers
+}
+
+// sumContainerUsage totals the usage of the given containers, or nil when none report usage
+func sumContainerUsage(containers []ContainerMemoryInfo) *resource.Quantity {
+	var total int64
+	var reported bool
+	for i := range containers {
+		if containers[i].CurrentUsage != nil {
+			total += containers[i].CurrentUsage.Value()
+			reported = true
+		}
+	}
+	if !reported {
+		return nil
+	}
+	return resource.NewQuantity(total, resource.BinarySI)
+}
+
 func (c *Client) calculatePodUsageFromMetrics(metrics *metricsv1beta1.PodMetrics) *resource.Quantity {
 	if metrics == nil {
 		return nil
 	}
 	var total int64
+	var reported bool
 	for i := range metrics.Containers {
+		// Without include/exclude lists this only drops system containers, unless they are included
+		if !c.containerFilter.Matches(metrics.Containers[i].Name) {
+			continue
+		}
 		if usage, ok := metrics.Containers[i].Usage[corev1.ResourceMemory]; ok {
 			total += usage.Value()
+			reported = true
 		}
 	}
-	if total == 0 {
+	if !reported {
 		return nil
 	}
 	return resource.NewQuantity(total, resource.BinarySI)
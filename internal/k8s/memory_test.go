@@ -1,12 +1,22 @@
 package k8s
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 )
 
 func TestProcessPodMemoryInfo_PopulatesContainers(t *testing.T) {
@@ -84,9 +94,46 @@ func TestProcessPodMemoryInfo_PopulatesContainers(t *testing.T) {
 	}
 }
 
+func TestProcessPodMemoryInfo_PopulatesPlacement(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, QOSClass: corev1.PodQOSBurstable},
+	}
+
+	info := (&Client{}).processPodMemoryInfo(pod, nil)
+	if info.NodeName != "node-1" || info.QOSClass != "Burstable" || !info.CreatedAt.Equal(created) {
+		t.Errorf("unexpected placement: node=%q qos=%q created=%v", info.NodeName, info.QOSClass, info.CreatedAt)
+	}
+}
+
+func TestProcessPodMemoryInfo_PrefersStatusResources(t *testing.T) {
+	pod := newTestPod("ns", "p", corev1.PodRunning, "100Mi", "200Mi")
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: "app",
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("150Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("300Mi")},
+		},
+	}}
+
+	info := (&Client{}).processPodMemoryInfo(pod, nil)
+	if info.MemoryRequest == nil || info.MemoryRequest.Value() != 150*1024*1024 {
+		t.Errorf("expected request from status.resources, got %v", info.MemoryRequest)
+	}
+	if info.MemoryLimit == nil || info.MemoryLimit.Value() != 300*1024*1024 {
+		t.Errorf("expected limit from status.resources, got %v", info.MemoryLimit)
+	}
+	if pod.Spec.Containers[0].Resources.Requests.Memory().Value() != 100*1024*1024 {
+		t.Errorf("pod spec should not be modified")
+	}
+}
+
 func TestProcessContainerMemoryInfo_PopulatesFields(t *testing.T) {
 	container := &corev1.Container{
-		Name: "app",
+		Name:  "app",
+		Image: "registry.example.com/team/app:1.2.3",
 		Resources: corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")},
 			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
@@ -106,6 +153,12 @@ func TestProcessContainerMemoryInfo_PopulatesFields(t *testing.T) {
 	if info.CurrentUsage == nil || info.CurrentUsage.Value() == 0 {
 		t.Fatalf("usage not set")
 	}
+	if info.Image != "registry.example.com/team/app:1.2.3" {
+		t.Fatalf("image not set, got %q", info.Image)
+	}
+	if info.RequestLimitRatio == nil || *info.RequestLimitRatio != 50 {
+		t.Fatalf("request/limit ratio not set, got %v", info.RequestLimitRatio)
+	}
 }
 
 func TestAggregatePodResources_SumsValues(t *testing.T) {
@@ -139,3 +192,411 @@ func TestCalculatePodUsageFromMetrics_SumsUsage(t *testing.T) {
 		t.Fatalf("wrong usage")
 	}
 }
+
+func TestCalculatePodUsageFromMetrics_KeepsReportedZeroUsage(t *testing.T) {
+	c := &Client{}
+	zero := &metricsv1beta1.PodMetrics{
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("0")}},
+		},
+	}
+	if usage := c.calculatePodUsageFromMetrics(zero); usage == nil || !usage.IsZero() {
+		t.Errorf("expected a reported zero usage, got %v", usage)
+	}
+
+	unreported := &metricsv1beta1.PodMetrics{
+		Containers: []metricsv1beta1.ContainerMetrics{{Usage: corev1.ResourceList{}}},
+	}
+	if usage := c.calculatePodUsageFromMetrics(unreported); usage != nil {
+		t.Errorf("expected no usage when no container reports memory, got %s", usage.String())
+	}
+
+	if usage := sumContainerUsage([]ContainerMemoryInfo{{CurrentUsage: resource.NewQuantity(0, resource.BinarySI)}, {}}); usage == nil || !usage.IsZero() {
+		t.Errorf("expected a reported zero container usage to sum to zero, got %v", usage)
+	}
+	if usage := sumContainerUsage([]ContainerMemoryInfo{{}}); usage != nil {
+		t.Errorf("expected no usage when no container reports it, got %s", usage.String())
+	}
+}
+
+func TestGetAllNamespacesPodsMemoryInfo_CollectsForbiddenNamespaces(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "open"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "locked"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "open"}},
+	)
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "locked" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied"))
+		}
+		return false, nil, nil
+	})
+	c := &Client{clientset: clientset, metricsClient: metricsfake.NewSimpleClientset()}
+
+	pods, summary, err := c.getAllNamespacesPodsMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 {
+		t.Errorf("expected 1 pod from the open namespace, got %d", len(pods))
+	}
+	if len(summary.ForbiddenNamespaces) != 1 || summary.ForbiddenNamespaces[0] != "locked" {
+		t.Errorf("expected forbidden namespaces [locked], got %v", summary.ForbiddenNamespaces)
+	}
+}
+
+func TestGetAllNamespacesPodsMemoryInfo_StrictFailsOnNamespaceErrors(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "open"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "locked"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "broken"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "open"}},
+	)
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action.GetNamespace() {
+		case "locked":
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied"))
+		case "broken":
+			return true, nil, apierrors.NewBadRequest("bad request")
+		}
+		return false, nil, nil
+	})
+	c := &Client{clientset: clientset, metricsClient: metricsfake.NewSimpleClientset()}
+	c.SetStrict(true)
+
+	pods, summary, err := c.getAllNamespacesPodsMemoryInfo(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error, got %d pods and summary %+v", len(pods), summary)
+	}
+	for _, expected := range []string{"failed to collect 2 of 3 namespaces", "namespace locked:", "namespace broken:"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in %q", expected, err)
+		}
+	}
+}
+
+func TestGetNamespacePodsMemoryInfo_LabelSelectorReachesPodAndMetricsLists(t *testing.T) {
+	web := newTestPod("ns", "web", corev1.PodRunning, "100Mi", "")
+	web.Labels = map[string]string{"app": "web"}
+	batch := newTestPod("ns", "batch", corev1.PodRunning, "100Mi", "")
+	batch.Labels = map[string]string{"app": "batch"}
+
+	clientset := fake.NewSimpleClientset(web, batch)
+	var podSelector string
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		podSelector = action.(k8stesting.ListAction).GetListRestrictions().Labels.String()
+		return false, nil, nil
+	})
+	// metrics-server copies the pod labels onto PodMetrics, which is what lets it apply the selector
+	webMetrics := newTestPodMetrics("ns", "web", "50Mi")
+	webMetrics.Labels = web.Labels
+	metricsClient := metricsfake.NewSimpleClientset()
+	var metricsSelector string
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		metricsSelector = action.(k8stesting.ListAction).GetListRestrictions().Labels.String()
+		return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{*webMetrics}}, nil
+	})
+	c := NewClientWithInterfaces(clientset, metricsClient)
+	c.SetLabelSelector("app=web")
+
+	pods, summary, err := c.getNamespacePodsMemoryInfo(context.Background(), "ns", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if podSelector != "app=web" || metricsSelector != "app=web" {
+		t.Errorf("expected the selector on both lists, got pods %q and metrics %q", podSelector, metricsSelector)
+	}
+	if len(pods) != 1 || pods[0].PodName != "web" || pods[0].CurrentUsage == nil {
+		t.Errorf("expected only the web pod with its usage, got %+v", pods)
+	}
+	if summary.TotalMemoryUsage.Value() != 50*1024*1024 {
+		t.Errorf("expected usage of the selected pod only, got %s", summary.TotalMemoryUsage.String())
+	}
+}
+
+func newTwoContainerPod() (*corev1.Pod, *metricsv1beta1.PodMetrics) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+					},
+				},
+				{Name: "istio-proxy"},
+			},
+		},
+	}
+	metrics := &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "p"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("50Mi")}},
+			{Name: "istio-proxy", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("30Mi")}},
+		},
+	}
+	return pod, metrics
+}
+
+func TestProcessPodMemoryInfo_ExcludeContainer(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	c := &Client{containerFilter: ContainerFilter{Exclude: []string{"istio-proxy"}}}
+
+	info := c.processPodMemoryInfo(pod, metrics)
+
+	if len(info.Containers) != 1 || info.Containers[0].ContainerName != "app" {
+		t.Fatalf("expected only the app container, got %v", info.Containers)
+	}
+	if info.CurrentUsage == nil || info.CurrentUsage.Value() != 50*1024*1024 {
+		t.Errorf("expected pod usage to reflect only app (50Mi), got %v", info.CurrentUsage)
+	}
+	if info.MemoryLimit == nil || info.MemoryLimit.Value() != 200*1024*1024 {
+		t.Errorf("expected pod limit from app only, got %v", info.MemoryLimit)
+	}
+}
+
+func TestProcessPodMemoryInfo_EnvVars(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "DAG_ID", Value: "old"},
+		{Name: "DAG_ID", Value: "daily_export"},
+		{Name: "TASK_ID", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+	}
+	c := &Client{}
+	c.SetEnvVars([]string{"DAG_ID", "TASK_ID"})
+
+	info := c.processPodMemoryInfo(pod, metrics)
+
+	app := info.Containers[0]
+	if len(app.EnvVars) != 1 || app.EnvVars["DAG_ID"] != "daily_export" {
+		t.Errorf("expected only the last literal DAG_ID, got %v", app.EnvVars)
+	}
+	if info.Containers[1].EnvVars != nil {
+		t.Errorf("expected no env vars on istio-proxy, got %v", info.Containers[1].EnvVars)
+	}
+	if value, ok := info.EnvVar("DAG_ID"); !ok || value != "daily_export" {
+		t.Errorf("EnvVar(DAG_ID) = %q, %v", value, ok)
+	}
+	if _, ok := info.EnvVar("TASK_ID"); ok {
+		t.Error("expected TASK_ID set through valueFrom to be skipped")
+	}
+}
+
+func TestProcessPodMemoryInfo_RecordsRestarts(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:                 "app",
+			RestartCount:         2,
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+		},
+		{Name: "istio-proxy"},
+	}
+	c := &Client{}
+
+	info := c.processPodMemoryInfo(pod, metrics)
+
+	if app := info.Containers[0]; app.RestartCount != 2 || app.LastTermination != "OOMKilled" {
+		t.Errorf("expected 2 restarts after OOMKilled, got %d %q", app.RestartCount, app.LastTermination)
+	}
+	if proxy := info.Containers[1]; proxy.RestartCount != 0 || proxy.LastTermination != "" {
+		t.Errorf("expected no restarts for istio-proxy, got %d %q", proxy.RestartCount, proxy.LastTermination)
+	}
+}
+
+func TestProcessPodMemoryInfo_AnnotationsAsLabels(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	pod.Labels = map[string]string{"team": "from-label"}
+	pod.Annotations = map[string]string{"team": "from-annotation", "cost-center": "cc-42", "ignored": "x"}
+	c := &Client{}
+	c.SetAnnotationsAsLabels([]string{"team", "cost-center", "missing"})
+
+	info := c.processPodMemoryInfo(pod, metrics)
+
+	expected := map[string]string{"team": "from-label", "cost-center": "cc-42"}
+	if len(info.Labels) != len(expected) {
+		t.Fatalf("expected labels %v, got %v", expected, info.Labels)
+	}
+	for key, value := range expected {
+		if info.Labels[key] != value {
+			t.Errorf("label %s = %q, want %q", key, info.Labels[key], value)
+		}
+	}
+	if info.Annotations["cost-center"] != "cc-42" {
+		t.Errorf("expected annotations to be kept, got %v", info.Annotations)
+	}
+	if _, ok := pod.Labels["cost-center"]; ok {
+		t.Error("expected the pod object labels to be left unchanged")
+	}
+}
+
+func TestProcessPodMemoryInfo_IncludeContainer(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	c := &Client{containerFilter: ContainerFilter{Include: []string{"istio-proxy"}}}
+
+	info := c.processPodMemoryInfo(pod, metrics)
+
+	if len(info.Containers) != 1 || info.Containers[0].ContainerName != "istio-proxy" {
+		t.Fatalf("expected only the istio-proxy container, got %v", info.Containers)
+	}
+	if info.CurrentUsage == nil || info.CurrentUsage.Value() != 30*1024*1024 {
+		t.Errorf("expected pod usage of 30Mi, got %v", info.CurrentUsage)
+	}
+	if info.MemoryLimit != nil {
+		t.Errorf("expected no pod limit since istio-proxy has none, got %v", info.MemoryLimit)
+	}
+}
+
+func TestProcessPodMemoryInfo_SystemContainers(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	metrics.Containers = append(metrics.Containers,
+		metricsv1beta1.ContainerMetrics{Name: "POD", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Mi")}})
+
+	tests := []struct {
+		name          string
+		filter        ContainerFilter
+		expectedNames []string
+		expectedUsage int64
+	}{
+		{"excluded by default", ContainerFilter{System: []string{"POD"}}, []string{"app", "istio-proxy"}, 80},
+		{"included on request", ContainerFilter{System: []string{"POD"}, IncludeSystem: true}, []string{"POD", "app", "istio-proxy"}, 81},
+		{"matched by pattern", ContainerFilter{System: []string{"istio-*", "POD"}}, []string{"app"}, 50},
+		{"included with a container list", ContainerFilter{Include: []string{"app"}, System: []string{"POD"}, IncludeSystem: true}, []string{"app"}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{containerFilter: tt.filter}
+
+			info := c.processPodMemoryInfo(pod, metrics)
+
+			var names []string
+			for _, container := range info.Containers {
+				names = append(names, container.ContainerName)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedNames, ",") {
+				t.Errorf("expected containers %v, got %v", tt.expectedNames, names)
+			}
+			if info.CurrentUsage == nil || info.CurrentUsage.Value() != tt.expectedUsage*1024*1024 {
+				t.Errorf("expected pod usage of %dMi, got %v", tt.expectedUsage, info.CurrentUsage)
+			}
+		})
+	}
+}
+
+func TestProcessPodMemoryInfo_RecordsMetricsAge(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sampled := now.Add(-2 * time.Minute)
+	metrics.Timestamp = metav1.NewTime(sampled)
+	metrics.Window = metav1.Duration{Duration: 30 * time.Second}
+
+	c := &Client{}
+	c.SetClock(fixedClock{now})
+	info := c.processPodMemoryInfo(pod, metrics)
+	if !info.Timestamp.Equal(now) {
+		t.Errorf("expected timestamp from the clock %v, got %v", now, info.Timestamp)
+	}
+	if info.MetricsTimestamp == nil || !info.MetricsTimestamp.Equal(sampled) {
+		t.Fatalf("expected metrics timestamp %v, got %v", sampled, info.MetricsTimestamp)
+	}
+	if info.MetricsAge == nil || *info.MetricsAge != 150*time.Second {
+		t.Errorf("expected an age of 2m30s including the window, got %v", info.MetricsAge)
+	}
+
+	info = c.processPodMemoryInfo(pod, &metricsv1beta1.PodMetrics{})
+	if info.MetricsAge != nil {
+		t.Errorf("expected no age without a metrics timestamp, got %v", *info.MetricsAge)
+	}
+}
+
+func TestProcessPodMemoryInfo_RecordsMetricSource(t *testing.T) {
+	pod, metrics := newTwoContainerPod()
+	c := &Client{}
+	if info := c.processPodMemoryInfo(pod, metrics); info.MetricSource != MetricSourceWorkingSet {
+		t.Errorf("expected metric source %q, got %q", MetricSourceWorkingSet, info.MetricSource)
+	}
+	if info := c.processPodMemoryInfo(pod, nil); info.MetricSource != "" {
+		t.Errorf("expected no metric source without metrics, got %q", info.MetricSource)
+	}
+}
+
+func TestProcessPodMemoryInfo_SortsContainersByName(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "worker"}, {Name: "app"}, {Name: "istio-proxy"},
+		}},
+	}
+	metrics := &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "p"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "istio-proxy", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("30Mi")}},
+			{Name: "worker", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("70Mi")}},
+			{Name: "app", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("50Mi")}},
+		},
+	}
+
+	info := (&Client{}).processPodMemoryInfo(pod, metrics)
+
+	want := []string{"app", "istio-proxy", "worker"}
+	if len(info.Containers) != len(want) {
+		t.Fatalf("expected %d containers, got %d", len(want), len(info.Containers))
+	}
+	for i, name := range want {
+		if info.Containers[i].ContainerName != name {
+			t.Errorf("container %d = %s, want %s", i, info.Containers[i].ContainerName, name)
+		}
+	}
+	if info.Containers[2].CurrentUsage.Cmp(resource.MustParse("70Mi")) != 0 {
+		t.Errorf("expected worker usage to follow its container, got %v", info.Containers[2].CurrentUsage)
+	}
+}
+
+func TestGetPodsMemoryInfo_WithoutMetricsClient(t *testing.T) {
+	c := NewClientWithInterfaces(fake.NewSimpleClientset(
+		newTestPod("ns", "web", corev1.PodRunning, "100Mi", "200Mi"),
+	), nil)
+
+	pods, summary, err := c.GetPodsMemoryInfo(context.Background(), "ns", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].MetricSource != "" {
+		t.Fatalf("expected one pod without metrics, got %+v", pods)
+	}
+	if summary.PodsWithMetrics != 0 || summary.TotalMemoryRequest.Cmp(resource.MustParse("100Mi")) != 0 {
+		t.Errorf("expected requests without usage in the summary, got %+v", summary)
+	}
+
+	if _, _, err := c.GetSinglePodMemoryInfo(context.Background(), "ns", "web"); err != nil {
+		t.Errorf("unexpected error for a single pod: %v", err)
+	}
+	if _, err := c.listPodMetrics(context.Background(), "ns", metav1.ListOptions{}); !errors.Is(err, ErrMetricsDisabled) {
+		t.Errorf("expected ErrMetricsDisabled, got %v", err)
+	}
+}
+
+func TestDisableMetrics_SkipsMetricsCalls(t *testing.T) {
+	c := newFakeClient(
+		[]runtime.Object{newTestPod("ns", "web", corev1.PodRunning, "", "")},
+		newTestPodMetrics("ns", "web", "80Mi"),
+	)
+	metricsClient := c.metricsClient.(*metricsfake.Clientset)
+	c.DisableMetrics()
+
+	pods, _, err := c.GetPodsMemoryInfo(context.Background(), "ns", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].MetricSource != "" {
+		t.Errorf("expected usage to be skipped, got %+v", pods)
+	}
+	if calls := len(metricsClient.Actions()); calls != 0 {
+		t.Errorf("expected no metrics calls, got %d", calls)
+	}
+	if c.MetricsEnabled() {
+		t.Error("expected metrics to report as disabled")
+	}
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// DefaultMetricsAPIGroup is the API group served by metrics-server
+const DefaultMetricsAPIGroup = "metrics.k8s.io"
+
+// ErrMetricsDisabled is returned by metrics reads on a client without a metrics source
+var ErrMetricsDisabled = errors.New("pod metrics are disabled")
+
+// DisableMetrics stops all metrics reads, so pods are reported with requests and limits only
+// This supports offline analysis and clusters without metrics-server
+func (c *Client) DisableMetrics() {
+	c.metricsClient = nil
+	c.metricsDisabled = true
+}
+
+// MetricsEnabled reports whether the client reads pod usage from a metrics API
+func (c *Client) MetricsEnabled() bool {
+	if c.metricsDisabled {
+		return false
+	}
+	return c.metricsClient != nil || (c.usesCustomMetricsGroup() && c.clientset != nil)
+}
+
+// SetMetricsAPIGroup reads pod metrics from another API group serving the metrics.k8s.io/v1beta1 schema
+// This supports clusters where the metrics service is exposed under a renamed group
+func (c *Client) SetMetricsAPIGroup(group string) {
+	c.metricsAPIGroup = group
+}
+
+// usesCustomMetricsGroup reports whether metrics are read from a group other than metrics.k8s.io
+func (c *Client) usesCustomMetricsGroup() bool {
+	return c.metricsAPIGroup != "" && c.metricsAPIGroup != DefaultMetricsAPIGroup
+}
+
+// listPodMetrics lists the pod metrics of a namespace, or of all namespaces, from the configured metrics API group
+func (c *Client) listPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) (
+	*metricsv1beta1.PodMetricsList, error) {
+	if !c.MetricsEnabled() {
+		return nil, ErrMetricsDisabled
+	}
+	c.apiCalls.metricsRequests.Add(1)
+	if !c.usesCustomMetricsGroup() {
+		return c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, opts)
+	}
+	segments := []string{"pods"}
+	if namespace != metav1.NamespaceAll {
+		segments = []string{"namespaces", namespace, "pods"}
+	}
+	list := &metricsv1beta1.PodMetricsList{}
+	if err := c.getCustomMetrics(ctx, list, &opts, segments...); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// getPodMetrics gets the metrics of a single pod from the configured metrics API group
+func (c *Client) getPodMetrics(ctx context.Context, namespace, podName string) (*metricsv1beta1.PodMetrics, error) {
+	if !c.MetricsEnabled() {
+		return nil, ErrMetricsDisabled
+	}
+	c.apiCalls.metricsRequests.Add(1)
+	if !c.usesCustomMetricsGroup() {
+		return c.metricsClient.MetricsV1beta1().PodMetricses(namespace).Get(ctx, podName, metav1.GetOptions{})
+	}
+	metrics := &metricsv1beta1.PodMetrics{}
+	if err := c.getCustomMetrics(ctx, metrics, nil, "namespaces", namespace, "pods", podName); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// getCustomMetrics fetches a metrics resource from the custom API group and decodes it into out
+// The typed metrics client is bound to metrics.k8s.io, so the request is built from the raw path
+func (c *Client) getCustomMetrics(ctx context.Context, out any, opts *metav1.ListOptions, segments ...string) error {
+	path := append([]string{"/apis", c.metricsAPIGroup, metricsv1beta1.SchemeGroupVersion.Version}, segments...)
+	request := c.clientset.Discovery().RESTClient().Get().AbsPath(path...)
+	if opts != nil {
+		request = request.VersionedParams(opts, metav1.ParameterCodec)
+	}
+	body, err := request.DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode metrics from API group %s: %w", c.metricsAPIGroup, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func TestListPodMetrics_CustomAPIGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/custom.metrics.example.com/v1beta1/namespaces/ns/pods" {
+			http.NotFound(w, r)
+			return
+		}
+		list := metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{*newTestPodMetrics("ns", "web", "64Mi")}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+	}))
+	defer server.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create clientset: %v", err)
+	}
+	c := NewClientWithInterfaces(clientset, metricsfake.NewSimpleClientset())
+	c.SetMetricsAPIGroup("custom.metrics.example.com")
+
+	metrics, err := c.listPodMetrics(context.Background(), "ns", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listPodMetrics() failed: %v", err)
+	}
+	if len(metrics.Items) != 1 || metrics.Items[0].Name != "web" {
+		t.Fatalf("expected metrics for pod web, got %v", metrics.Items)
+	}
+	if memory := metrics.Items[0].Containers[0].Usage.Memory(); memory.Value() != 64*1024*1024 {
+		t.Errorf("expected 64Mi usage, got %s", memory.String())
+	}
+}
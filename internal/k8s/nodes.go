@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNodeAllocatableMemory returns the allocatable memory of every node, keyed by node name
+// Nodes that do not report allocatable memory are omitted
+func (c *Client) GetNodeAllocatableMemory(ctx context.Context) (map[string]resource.Quantity, error) {
+	nodes, err := withRetry(ctx, c.retryPolicy, "list nodes", func() (*corev1.NodeList, error) {
+		c.apiCalls.nodeLists.Add(1)
+		return c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	allocatable := make(map[string]resource.Quantity, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if memory, ok := node.Status.Allocatable[corev1.ResourceMemory]; ok {
+			allocatable[node.Name] = memory
+		}
+	}
+	return allocatable, nil
+}
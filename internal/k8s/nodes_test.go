@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetNodeAllocatableMemory(t *testing.T) {
+	c := newFakeClient([]runtime.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourceCPU:    resource.MustParse("4"),
+			}},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+	})
+
+	allocatable, err := c.GetNodeAllocatableMemory(context.Background())
+	if err != nil {
+		t.Fatalf("GetNodeAllocatableMemory() failed: %v", err)
+	}
+	if len(allocatable) != 1 {
+		t.Fatalf("expected only nodes reporting memory, got %v", allocatable)
+	}
+	if memory := allocatable["node-1"]; memory.Value() != 8*1024*1024*1024 {
+		t.Errorf("expected 8Gi allocatable on node-1, got %s", memory.String())
+	}
+}
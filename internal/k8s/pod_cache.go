@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// podCache keeps the pod specs of the last complete collection, so later cycles can refresh
+// usage by listing only pod metrics. Specs rarely change between watch cycles, metrics always do.
+type podCache struct {
+	ttl time.Duration
+
+	// Pods recorded by the collection in progress, promoted by commit once it completes
+	pending    map[string][]corev1.Pod
+	pendingNSs []string
+
+	pods           map[string][]corev1.Pod // Cached pods by namespace
+	namespaces     []string                // Namespaces holding cached pods, in collection order
+	namespaceCount int                     // Namespace count of the collection the pods came from
+	forbidden      []string                // Namespaces the collection could not list
+	storedAt       time.Time
+}
+
+// begin discards anything recorded by an earlier, unfinished collection
+func (pc *podCache) begin() {
+	if pc == nil {
+		return
+	}
+	pc.pending = make(map[string][]corev1.Pod)
+	pc.pendingNSs = nil
+}
+
+// record adds a listed pod to the collection in progress
+func (pc *podCache) record(pod *corev1.Pod) {
+	if pc == nil || pc.pending == nil {
+		return
+	}
+	if _, seen := pc.pending[pod.Namespace]; !seen {
+		pc.pendingNSs = append(pc.pendingNSs, pod.Namespace)
+	}
+	pc.pending[pod.Namespace] = append(pc.pending[pod.Namespace], *pod)
+}
+
+// commit replaces the cached pods with those of the collection that produced summary
+// Partial collections are not cached, so a refresh never reports fewer pods than the last full listing
+func (pc *podCache) commit(summary *MemorySummary, now time.Time) {
+	if pc == nil || pc.pending == nil {
+		return
+	}
+	if !summary.Partial {
+		pc.pods = pc.pending
+		pc.namespaces = pc.pendingNSs
+		pc.namespaceCount = summary.NamespaceCount
+		pc.forbidden = summary.ForbiddenNamespaces
+		pc.storedAt = now
+	}
+	pc.pending = nil
+	pc.pendingNSs = nil
+}
+
+// fresh reports whether cached pods exist and are younger than the TTL
+func (pc *podCache) fresh(now time.Time) bool {
+	return pc != nil && !pc.storedAt.IsZero() && now.Sub(pc.storedAt) < pc.ttl
+}
+
+// SetPodCacheTTL enables caching the pod specs of each complete collection for RefreshMetrics
+// A zero TTL disables the cache
+func (c *Client) SetPodCacheTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		c.podCache = nil
+		return
+	}
+	c.podCache = &podCache{ttl: ttl}
+}
+
+// PodCacheFresh reports whether RefreshMetrics can be used instead of a full collection
+func (c *Client) PodCacheFresh() bool {
+	return c.podCache.fresh(c.now())
+}
+
+// RefreshMetrics rebuilds the last collected pods from their cached specs and freshly listed metrics,
+// skipping the pod list calls. Usage, percentages and summary totals are recomputed as in a full collection,
+// but the pod status is not: phase, readiness, restart counts and termination reasons are those of the
+// last pod listing, at most the cache TTL old. It fails when the cache is disabled, empty or older than its TTL.
+func (c *Client) RefreshMetrics(ctx context.Context) ([]PodMemoryInfo, *MemorySummary, error) {
+	if !c.PodCacheFresh() {
+		return nil, nil, fmt.Errorf("no fresh pod specs cached, a full collection is needed")
+	}
+	cache := c.podCache
+
+	podInfos := []PodMemoryInfo{}
+	summary := &MemorySummary{
+		Timestamp:           c.now(),
+		NamespaceCount:      cache.namespaceCount,
+		ForbiddenNamespaces: cache.forbidden,
+		TotalMemoryUsage:    *resource.NewQuantity(0, resource.BinarySI),
+		TotalMemoryLimit:    *resource.NewQuantity(0, resource.BinarySI),
+		TotalMemoryRequest:  *resource.NewQuantity(0, resource.BinarySI),
+	}
+	// With sampling, several namespaces share one round of cluster-wide reads
+	var sampled map[string]map[string]*metricsv1beta1.PodMetrics
+	if len(cache.namespaces) > 1 {
+		var elapsed time.Duration
+		sampled, elapsed = c.clusterSampledMetrics(ctx)
+		summary.Timings.Metrics += elapsed
+	}
+	for _, namespace := range cache.namespaces {
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("metrics refresh interrupted: %w", ctx.Err())
+		}
+		metricsMap, elapsed := c.namespacePodMetrics(ctx, namespace, sampled)
+		summary.Timings.Metrics += elapsed
+		c.apiCalls.namespacesProcessed.Add(1)
+		pods := cache.pods[namespace]
+		for i := range pods {
+			pod := &pods[i]
+			podInfo := c.processPodMemoryInfo(pod, metricsMap[pod.Name])
+			if c.containerFilter.IsActive() && len(podInfo.Containers) == 0 {
+				// No selected containers in this pod, nothing to report
+				continue
+			}
+			podInfos = append(podInfos, podInfo)
+			addPodToSummary(summary, pod, &podInfo)
+		}
+	}
+	summary.TotalPods = len(podInfos)
+
+	slog.Info("Memory metrics refreshed from cached pod specs",
+		"total_pods", summary.TotalPods,
+		"pods_with_metrics", summary.PodsWithMetrics,
+		"cache_age", c.now().Sub(cache.storedAt).Round(time.Second).String(),
+		"total_usage", FormatMemoryTotal(&summary.TotalMemoryUsage))
+
+	return podInfos, summary, nil
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// podListCalls counts the pod list requests made through a fake-backed client
+func podListCalls(c *Client) int {
+	calls := 0
+	for _, action := range c.clientset.(*fake.Clientset).Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			calls++
+		}
+	}
+	return calls
+}
+
+func TestRefreshMetrics_ReusesCachedPodSpecs(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	webMetrics := newTestPodMetrics("a", "web", "80Mi")
+	c := newFakeClient(
+		[]runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+			newTestPod("a", "web", corev1.PodRunning, "100Mi", "200Mi"),
+			newTestPod("b", "api", corev1.PodRunning, "50Mi", ""),
+		},
+		webMetrics,
+		newTestPodMetrics("b", "api", "20Mi"),
+	)
+	c.SetClock(fixedClock{now})
+	c.SetPodCacheTTL(5 * time.Minute)
+
+	if c.PodCacheFresh() {
+		t.Fatal("expected no cached pods before the first collection")
+	}
+	if _, _, err := c.GetPodsMemoryInfo(context.Background(), "", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	listed := podListCalls(c)
+
+	webMetrics.Containers[0].Usage[corev1.ResourceMemory] = resource.MustParse("190Mi")
+	c.SetClock(fixedClock{now.Add(time.Minute)})
+	if !c.PodCacheFresh() {
+		t.Fatal("expected the cache to be fresh after a complete collection")
+	}
+	pods, summary, err := c.RefreshMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := podListCalls(c); got != listed {
+		t.Errorf("expected no pod list calls during the refresh, got %d more", got-listed)
+	}
+	if len(pods) != 2 || summary.TotalPods != 2 || summary.NamespaceCount != 2 {
+		t.Fatalf("expected both cached pods across 2 namespaces, got %d pods, summary %+v", len(pods), summary)
+	}
+	web := pods[0]
+	if web.PodName != "web" || web.CurrentUsage.Cmp(resource.MustParse("190Mi")) != 0 {
+		t.Fatalf("expected refreshed usage for web, got %s %v", web.PodName, web.CurrentUsage)
+	}
+	if !summary.Timestamp.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the refresh time as timestamp, got %v", summary.Timestamp)
+	}
+
+	c.SetClock(fixedClock{now.Add(5 * time.Minute)})
+	if c.PodCacheFresh() {
+		t.Error("expected the cache to expire after its TTL")
+	}
+	if _, _, err := c.RefreshMetrics(context.Background()); err == nil {
+		t.Error("expected an error refreshing from an expired cache")
+	}
+}
+
+func TestRefreshMetrics_SkipsPartialCollections(t *testing.T) {
+	c := newFakeClient([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		newTestPod("a", "web", corev1.PodRunning, "", ""),
+	})
+	c.SetPodCacheTTL(time.Minute)
+	c.SetMaxNamespaces(1)
+
+	if _, _, err := c.GetPodsMemoryInfo(context.Background(), "", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.PodCacheFresh() {
+		t.Error("expected a truncated collection not to be cached")
+	}
+}
+
+func TestRefreshMetrics_DisabledByDefault(t *testing.T) {
+	c := newFakeClient([]runtime.Object{newTestPod("a", "web", corev1.PodRunning, "", "")})
+	if _, _, err := c.GetPodsMemoryInfo(context.Background(), "a", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.PodCacheFresh() {
+		t.Error("expected no cache unless a TTL is set")
+	}
+}
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryPolicy controls how transient API errors are retried
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt (0 disables retrying)
+	Backoff    time.Duration // Initial delay, doubled after each failed attempt
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxRetries: 2, Backoff: 500 * time.Millisecond}
+}
+
+// withRetry runs op, retrying transient errors with exponential backoff
+func withRetry[T any](ctx context.Context, policy RetryPolicy, operation string, op func() (T, error)) (T, error) {
+	delay := policy.Backoff
+	for attempt := 0; ; attempt++ {
+		result, err := op()
+		if err == nil || attempt >= policy.MaxRetries || !isTransientError(err) {
+			return result, err
+		}
+
+		slog.Debug("Retrying transient API error",
+			"operation", operation,
+			"attempt", attempt+1,
+			"backoff", delay,
+			"error", err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// isTransientError reports whether err is worth retrying
+// Permission and not-found errors are permanent and never retried
+func isTransientError(err error) bool {
+	if apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	if apierrors.IsInternalError(err) && strings.Contains(err.Error(), "etcdserver: request timed out") {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
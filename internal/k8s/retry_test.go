@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func TestGetNamespacePodsMemoryInfo_RetriesTransientErrors(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns"}})
+	failures := 0
+	clientset.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		if failures < 2 {
+			failures++
+			return true, nil, apierrors.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "list", 1)
+		}
+		return false, nil, nil
+	})
+	c := &Client{
+		clientset:     clientset,
+		metricsClient: metricsfake.NewSimpleClientset(),
+		retryPolicy:   RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond},
+	}
+
+	pods, _, err := c.getNamespacePodsMemoryInfo(context.Background(), "ns", nil)
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if len(pods) != 1 || failures != 2 {
+		t.Errorf("expected 1 pod after 2 failures, got %d pods and %d failures", len(pods), failures)
+	}
+}
+
+func TestGetNamespacePodsMemoryInfo_DoesNotRetryForbidden(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	calls := 0
+	clientset.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied"))
+	})
+	c := &Client{
+		clientset:     clientset,
+		metricsClient: metricsfake.NewSimpleClientset(),
+		retryPolicy:   RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond},
+	}
+
+	if _, _, err := c.getNamespacePodsMemoryInfo(context.Background(), "ns", nil); err == nil {
+		t.Fatal("expected forbidden error")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt for forbidden errors, got %d", calls)
+	}
+}
+
+func TestWithRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	calls := 0
+	policy := RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}
+	_, err := withRetry(context.Background(), policy, "test", func() (int, error) {
+		calls++
+		return 0, apierrors.NewTooManyRequests("slow down", 1)
+	})
+	if err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
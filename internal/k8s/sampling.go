@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// SetSampling reads pod metrics the given number of times, waiting interval between reads, and reports
+// the average memory usage of each container; 1 or less keeps a single read
+func (c *Client) SetSampling(samples int, interval time.Duration) {
+	c.samples = samples
+	c.sampleInterval = interval
+}
+
+// sampledMetrics takes the extra metrics samples after the first one and returns the averaged metrics
+// Samples that fail to read return nil and are skipped; a cancelled context stops sampling early
+func (c *Client) sampledMetrics(ctx context.Context, first map[string]*metricsv1beta1.PodMetrics,
+	read func() map[string]*metricsv1beta1.PodMetrics) map[string]*metricsv1beta1.PodMetrics {
+	if c.samples <= 1 {
+		return first
+	}
+	samples := []map[string]*metricsv1beta1.PodMetrics{first}
+	for i := 1; i < c.samples; i++ {
+		select {
+		case <-ctx.Done():
+			return averagePodMetrics(samples)
+		case <-time.After(c.sampleInterval):
+		}
+		if sample := read(); sample != nil {
+			samples = append(samples, sample)
+		}
+	}
+	return averagePodMetrics(samples)
+}
+
+// clusterSampledMetrics takes every metrics sample of a multi-namespace collection up front with cluster-wide
+// reads, so the wait between samples is paid once per cycle rather than once per namespace.
+// It returns the averaged metrics keyed by namespace and pod name, and how long sampling took. Without
+// sampling, or when the cluster-wide read fails, it returns nil and each namespace samples its own metrics
+func (c *Client) clusterSampledMetrics(ctx context.Context) (map[string]map[string]*metricsv1beta1.PodMetrics, time.Duration) {
+	if c.samples <= 1 || !c.MetricsEnabled() {
+		return nil, 0
+	}
+	start := time.Now()
+	first := c.readClusterPodMetrics(ctx)
+	if first == nil {
+		return nil, time.Since(start)
+	}
+	averaged := c.sampledMetrics(ctx, first, func() map[string]*metricsv1beta1.PodMetrics {
+		return c.readClusterPodMetrics(ctx)
+	})
+
+	byNamespace := make(map[string]map[string]*metricsv1beta1.PodMetrics)
+	for _, pm := range averaged {
+		if byNamespace[pm.Namespace] == nil {
+			byNamespace[pm.Namespace] = make(map[string]*metricsv1beta1.PodMetrics)
+		}
+		byNamespace[pm.Namespace][pm.Name] = pm
+	}
+	return byNamespace, time.Since(start)
+}
+
+// readClusterPodMetrics lists the pod metrics of all namespaces once, keyed by namespace/name
+// A failure is logged and yields nil
+func (c *Client) readClusterPodMetrics(ctx context.Context) map[string]*metricsv1beta1.PodMetrics {
+	podMetrics, err := withRetry(ctx, c.retryPolicy, "list pod metrics", func() (*metricsv1beta1.PodMetricsList, error) {
+		return c.listPodMetrics(ctx, metav1.NamespaceAll, metav1.ListOptions{LabelSelector: c.labelSelector})
+	})
+	if err != nil {
+		slog.Warn("Failed to list pod metrics of all namespaces, sampling each namespace separately", "error", err)
+		return nil
+	}
+	metricsMap := make(map[string]*metricsv1beta1.PodMetrics, len(podMetrics.Items))
+	for i := range podMetrics.Items {
+		pm := &podMetrics.Items[i]
+		metricsMap[pm.Namespace+"/"+pm.Name] = pm
+	}
+	return metricsMap
+}
+
+// averagePodMetrics merges metrics samples keyed by pod, averaging each container's memory usage
+// over the samples that report it, so a pod or container missing from some samples is not pulled down.
+// The first sample of each pod provides its timestamp and window, so the metrics age covers all samples
+func averagePodMetrics(samples []map[string]*metricsv1beta1.PodMetrics) map[string]*metricsv1beta1.PodMetrics {
+	type usageTotal struct {
+		sum   int64
+		count int64
+	}
+	averaged := make(map[string]*metricsv1beta1.PodMetrics)
+	totals := make(map[string]map[string]*usageTotal)
+
+	for _, sample := range samples {
+		for name, pm := range sample {
+			merged, ok := averaged[name]
+			if !ok {
+				merged = pm.DeepCopy()
+				merged.Containers = nil
+				averaged[name] = merged
+				totals[name] = make(map[string]*usageTotal)
+			}
+			for i := range pm.Containers {
+				container := &pm.Containers[i]
+				total, seen := totals[name][container.Name]
+				if !seen {
+					total = &usageTotal{}
+					totals[name][container.Name] = total
+					merged.Containers = append(merged.Containers, *container.DeepCopy())
+				}
+				if usage, ok := container.Usage[corev1.ResourceMemory]; ok {
+					total.sum += usage.Value()
+					total.count++
+				}
+			}
+		}
+	}
+
+	for name, merged := range averaged {
+		for i := range merged.Containers {
+			container := &merged.Containers[i]
+			total := totals[name][container.Name]
+			if total.count == 0 {
+				continue
+			}
+			if container.Usage == nil {
+				container.Usage = corev1.ResourceList{}
+			}
+			container.Usage[corev1.ResourceMemory] = *resource.NewQuantity(total.sum/total.count, resource.BinarySI)
+		}
+	}
+	return averaged
+}
@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func TestAveragePodMetrics_SkipsMissingSamples(t *testing.T) {
+	withSidecar := newTestPodMetrics("prod", "web", "100Mi")
+	withSidecar.Containers = append(withSidecar.Containers, metricsv1beta1.ContainerMetrics{
+		Name: "istio-proxy", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("40Mi")},
+	})
+	samples := []map[string]*metricsv1beta1.PodMetrics{
+		{"web": newTestPodMetrics("prod", "web", "100Mi")},
+		{"web": withSidecar, "late": newTestPodMetrics("prod", "late", "10Mi")},
+		{"web": newTestPodMetrics("prod", "web", "400Mi")},
+	}
+
+	averaged := averagePodMetrics(samples)
+
+	tests := []struct {
+		pod       string
+		container string
+		expected  int64
+	}{
+		{"web", "app", 200 * 1024 * 1024},
+		{"web", "istio-proxy", 40 * 1024 * 1024}, // Only reported by one sample
+		{"late", "app", 10 * 1024 * 1024},        // Pod missing from the first sample
+	}
+	for _, tt := range tests {
+		pm, ok := averaged[tt.pod]
+		if !ok {
+			t.Fatalf("expected averaged metrics for pod %s", tt.pod)
+		}
+		var found bool
+		for _, container := range pm.Containers {
+			if container.Name != tt.container {
+				continue
+			}
+			found = true
+			usage := container.Usage[corev1.ResourceMemory]
+			if usage.Value() != tt.expected {
+				t.Errorf("%s/%s: expected usage %d, got %d", tt.pod, tt.container, tt.expected, usage.Value())
+			}
+		}
+		if !found {
+			t.Errorf("expected container %s in pod %s", tt.container, tt.pod)
+		}
+	}
+
+	// The samples themselves are left untouched
+	if usage := samples[0]["web"].Containers[0].Usage[corev1.ResourceMemory]; usage.Cmp(resource.MustParse("100Mi")) != 0 {
+		t.Errorf("expected the first sample to keep its usage, got %s", usage.String())
+	}
+}
+
+func TestGetPodsMemoryInfo_AveragesSamples(t *testing.T) {
+	readings := []string{"100Mi", "200Mi", "600Mi"}
+	var reads int
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		usage := readings[reads%len(readings)]
+		reads++
+		return true, &metricsv1beta1.PodMetricsList{
+			Items: []metricsv1beta1.PodMetrics{*newTestPodMetrics("prod", "web", usage)},
+		}, nil
+	})
+	pod := newTestPod("prod", "web", corev1.PodRunning, "256Mi", "1Gi")
+	client := NewClientWithInterfaces(fake.NewSimpleClientset(pod), metricsClient)
+	client.SetSampling(3, time.Millisecond)
+
+	pods, summary, err := client.GetPodsMemoryInfo(context.Background(), "prod", false)
+	if err != nil {
+		t.Fatalf("GetPodsMemoryInfo() failed: %v", err)
+	}
+	if reads != 3 {
+		t.Errorf("expected 3 metrics reads, got %d", reads)
+	}
+	if len(pods) != 1 || pods[0].CurrentUsage == nil {
+		t.Fatalf("expected one pod with usage, got %+v", pods)
+	}
+	expected := int64(300 * 1024 * 1024)
+	if pods[0].CurrentUsage.Value() != expected || pods[0].Containers[0].CurrentUsage.Value() != expected {
+		t.Errorf("expected an average usage of 300Mi, got pod %s and container %s",
+			pods[0].CurrentUsage.String(), pods[0].Containers[0].CurrentUsage.String())
+	}
+	if summary.TotalMemoryUsage.Value() != expected {
+		t.Errorf("expected the summary to total the averaged usage, got %s", summary.TotalMemoryUsage.String())
+	}
+}
+
+func TestGetAllPodsMemoryInfo_SamplesOncePerCycle(t *testing.T) {
+	readings := []string{"100Mi", "200Mi", "600Mi"}
+	var reads int
+	var namespaces []string
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		usage := readings[reads%len(readings)]
+		reads++
+		namespaces = append(namespaces, action.GetNamespace())
+		return true, &metricsv1beta1.PodMetricsList{
+			Items: []metricsv1beta1.PodMetrics{
+				*newTestPodMetrics("prod", "web", usage),
+				*newTestPodMetrics("staging", "web", usage),
+			},
+		}, nil
+	})
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		newTestPod("prod", "web", corev1.PodRunning, "256Mi", "1Gi"),
+		newTestPod("staging", "web", corev1.PodRunning, "256Mi", "1Gi"),
+	)
+	client := NewClientWithInterfaces(clientset, metricsClient)
+	client.SetSampling(3, time.Millisecond)
+
+	pods, _, err := client.GetAllPodsMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllPodsMemoryInfo() failed: %v", err)
+	}
+	if reads != 3 {
+		t.Errorf("expected 3 metrics reads for the whole cycle, got %d", reads)
+	}
+	for _, ns := range namespaces {
+		if ns != metav1.NamespaceAll {
+			t.Errorf("expected cluster-wide metrics reads, got a read of namespace %q", ns)
+		}
+	}
+	if len(pods) != 2 {
+		t.Fatalf("expected two pods, got %d", len(pods))
+	}
+	for _, pod := range pods {
+		if pod.CurrentUsage == nil || pod.CurrentUsage.Value() != 300*1024*1024 {
+			t.Errorf("expected %s/%s to average 300Mi, got %v", pod.Namespace, pod.PodName, pod.CurrentUsage)
+		}
+	}
+}
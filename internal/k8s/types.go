@@ -1,32 +1,50 @@
 package k8s
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
+// MetricSourceWorkingSet marks usage read from metrics-server, which reports the container working set:
+// anonymous memory plus active page cache, the figure the kubelet compares against limits. It is not RSS.
+const MetricSourceWorkingSet = "working_set"
+
 // PodMemoryInfo contains memory information for a single pod
 type PodMemoryInfo struct {
+	Cluster   string    `json:"cluster,omitempty"` // Kubeconfig context the pod was collected from, multi-cluster runs only
 	Namespace string    `json:"namespace"`
 	PodName   string    `json:"pod_name"`
 	Timestamp time.Time `json:"timestamp"`
 
 	// Current usage (from metrics API)
-	CurrentUsage *resource.Quantity `json:"current_usage,omitempty"`
+	CurrentUsage     *resource.Quantity `json:"current_usage,omitempty"`
+	MetricsTimestamp *time.Time         `json:"metrics_timestamp,omitempty"` // End of the usage sample window
+	MetricsAge       *time.Duration     `json:"-"`                           // Time since the start of the usage sample window
+	MetricSource     string             `json:"metric_source,omitempty"`     // What CurrentUsage measures, e.g. MetricSourceWorkingSet
 
 	// Limits and requests (from pod spec)
 	MemoryRequest *resource.Quantity `json:"memory_request,omitempty"`
 	MemoryLimit   *resource.Quantity `json:"memory_limit,omitempty"`
 
 	// Calculated fields
-	UsagePercent      *float64 `json:"usage_percent,omitempty"`       // Usage vs Request
-	LimitUsagePercent *float64 `json:"limit_usage_percent,omitempty"` // Usage vs Limit
+	UsagePercent      *float64           `json:"usage_percent,omitempty"`       // Usage vs Request
+	LimitUsagePercent *float64           `json:"limit_usage_percent,omitempty"` // Usage vs Limit
+	Headroom          *resource.Quantity `json:"headroom,omitempty"`            // Limit minus usage, negative when over limit
 
 	// Pod status
-	Phase string `json:"phase"`
-	Ready bool   `json:"ready"`
+	Phase              string             `json:"phase"`
+	Ready              bool               `json:"ready"`
+	NotReadyConditions []PodConditionInfo `json:"not_ready_conditions,omitempty"` // Failing conditions, most informative first
+
+	// Placement information
+	NodeName  string    `json:"node_name,omitempty"`
+	QOSClass  string    `json:"qos_class,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Workload  string    `json:"workload,omitempty"` // Controlling workload, e.g. deploy/web; empty for standalone pods
 
 	// Metadata information
 	Labels      map[string]string `json:"labels,omitempty"`
@@ -48,20 +66,122 @@ type MemorySummary struct {
 	TotalMemoryLimit   resource.Quantity `json:"total_memory_limit"`
 	TotalMemoryRequest resource.Quantity `json:"total_memory_request"`
 	NamespaceCount     int               `json:"namespace_count"`
+
+	// Namespaces whose pods could not be listed due to RBAC restrictions
+	ForbiddenNamespaces []string `json:"forbidden_namespaces,omitempty"`
+
+	// Set when the collection was cancelled part way, e.g. by Ctrl-C
+	// Only the pods of the first NamespacesCollected namespaces are included
+	Partial             bool `json:"partial,omitempty"`
+	NamespacesCollected int  `json:"namespaces_collected,omitempty"`
+
+	// Set with Partial when the collection stopped at the --max-namespaces cap rather than being cancelled
+	Truncated bool `json:"truncated,omitempty"`
+	cancelled bool // Set with Partial when the collection was cancelled, see Cancelled
+
+	// Risk counts, filled in by the analysis (zero when only collecting)
+	CriticalPods     int `json:"critical_pods"`
+	WarningPodsCount int `json:"warning_pods_count"`
+	PodsOverLimit    int `json:"pods_over_limit"`
+
+	Timings CollectionTimings `json:"timings"`
+}
+
+// markPartial records that the collection was cancelled after the given number of namespaces
+func (s *MemorySummary) markPartial(collected int) {
+	s.Partial = true
+	s.NamespacesCollected = collected
+	s.cancelled = true
+}
+
+// markTruncated records that the collection stopped at the namespace cap
+func (s *MemorySummary) markTruncated(collected int) {
+	s.Partial = true
+	s.NamespacesCollected = collected
+	s.Truncated = true
+}
+
+// Cancelled reports whether the collection was cancelled part way, so the namespaces it reached
+// may differ from one cycle to the next. A collection only truncated by the namespace cap covers
+// the same namespaces every cycle
+func (s *MemorySummary) Cancelled() bool {
+	return s.cancelled
+}
+
+// Merge adds the counts and totals of another cluster's summary
+// The timestamp is kept, and the total collection time is left for the caller to set
+func (s *MemorySummary) Merge(other *MemorySummary) {
+	if s.Partial || other.Partial {
+		s.NamespacesCollected = s.namespacesCollected() + other.namespacesCollected()
+		s.Partial = true
+	}
+	s.Truncated = s.Truncated || other.Truncated
+	s.cancelled = s.cancelled || other.cancelled
+	s.TotalPods += other.TotalPods
+	s.RunningPods += other.RunningPods
+	s.PodsWithMetrics += other.PodsWithMetrics
+	s.PodsWithLimits += other.PodsWithLimits
+	s.PodsWithRequests += other.PodsWithRequests
+	s.TotalMemoryUsage.Add(other.TotalMemoryUsage)
+	s.TotalMemoryLimit.Add(other.TotalMemoryLimit)
+	s.TotalMemoryRequest.Add(other.TotalMemoryRequest)
+	s.NamespaceCount += other.NamespaceCount
+	s.ForbiddenNamespaces = append(s.ForbiddenNamespaces, other.ForbiddenNamespaces...)
+	s.Timings.add(other.Timings)
+}
+
+// namespacesCollected returns how many namespaces contributed pods to the summary
+func (s *MemorySummary) namespacesCollected() int {
+	if s.Partial {
+		return s.NamespacesCollected
+	}
+	return s.NamespaceCount
+}
+
+// CollectionTimings records how long a collection took
+// PodList and Metrics are summed across namespaces, so they can exceed Total when namespaces are slow
+type CollectionTimings struct {
+	Total   time.Duration // Wall-clock time of the whole collection
+	PodList time.Duration // Time spent listing pods
+	Metrics time.Duration // Time spent fetching metrics
+}
+
+// add accumulates the API phase timings of another collection
+func (t *CollectionTimings) add(other CollectionTimings) {
+	t.PodList += other.PodList
+	t.Metrics += other.Metrics
+}
+
+// MarshalJSON renders the timings in milliseconds
+func (t CollectionTimings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Total   int64 `json:"collection_duration_ms"`
+		PodList int64 `json:"pod_list_duration_ms"`
+		Metrics int64 `json:"metrics_duration_ms"`
+	}{t.Total.Milliseconds(), t.PodList.Milliseconds(), t.Metrics.Milliseconds()})
 }
 
 // ContainerMemoryInfo contains memory information for a single container
 type ContainerMemoryInfo struct {
 	ContainerName     string             `json:"container_name"`
+	Image             string             `json:"image,omitempty"`
 	CurrentUsage      *resource.Quantity `json:"current_usage,omitempty"`
 	MemoryRequest     *resource.Quantity `json:"memory_request,omitempty"`
 	MemoryLimit       *resource.Quantity `json:"memory_limit,omitempty"`
 	UsagePercent      *float64           `json:"usage_percent,omitempty"`       // Usage vs Request
 	LimitUsagePercent *float64           `json:"limit_usage_percent,omitempty"` // Usage vs Limit
+	Headroom          *resource.Quantity `json:"headroom,omitempty"`            // Limit minus usage, negative when over limit
+	SuggestedRequest  *resource.Quantity `json:"suggested_request,omitempty"`   // Request sized from observed usage
+	PodUsageShare     *float64           `json:"pod_usage_share,omitempty"`     // Percent of the pod's total usage, multi-container pods only
+	RequestLimitRatio *float64           `json:"request_limit_ratio,omitempty"` // Request as percent of limit, 100 when they match
+	EnvVars           map[string]string  `json:"env_vars,omitempty"`            // Literal values of the requested environment variables
+	RestartCount      int32              `json:"restart_count,omitempty"`
+	LastTermination   string             `json:"last_termination_reason,omitempty"` // Reason of the previous run's exit, e.g. OOMKilled
 }
 
 // CalculateUsagePercent calculates usage percentage against request or limit for a container
 func (c *ContainerMemoryInfo) CalculateUsagePercent() {
+	c.RequestLimitRatio = requestLimitRatio(c.MemoryRequest, c.MemoryLimit)
 	if c.CurrentUsage == nil {
 		return
 	}
@@ -79,41 +199,163 @@ func (c *ContainerMemoryInfo) CalculateUsagePercent() {
 		percent := (currentValue / limitValue) * 100
 		c.LimitUsagePercent = &percent
 	}
+
+	c.Headroom = calculateHeadroom(c.CurrentUsage, c.MemoryLimit)
+}
+
+// requestLimitRatio returns request as a percentage of limit, or nil when either is missing or the limit is zero
+func requestLimitRatio(request, limit *resource.Quantity) *float64 {
+	if request == nil || limit == nil || limit.Value() <= 0 {
+		return nil
+	}
+	percent := float64(request.Value()) / float64(limit.Value()) * 100
+	return &percent
+}
+
+// calculateHeadroom returns limit minus usage, or nil when either value is missing
+// The result is not clamped so usage above the limit yields a negative headroom
+func calculateHeadroom(usage, limit *resource.Quantity) *resource.Quantity {
+	if usage == nil || limit == nil {
+		return nil
+	}
+	return resource.NewQuantity(limit.Value()-usage.Value(), resource.BinarySI)
+}
+
+// UnitSystem selects how FormatMemory scales and labels byte values
+type UnitSystem int
+
+const (
+	// UnitsLegacy uses 1024-based scaling with KB/MB/GB labels (historical output)
+	UnitsLegacy UnitSystem = iota
+	// UnitsBinary uses 1024-based scaling with KiB/MiB/GiB labels
+	UnitsBinary
+	// UnitsDecimal uses 1000-based scaling with KB/MB/GB labels
+	UnitsDecimal
+)
+
+// unitScale describes the base and labels of a unit system
+type unitScale struct {
+	base       float64
+	kb, mb, gb string
+	tb, pb     string // Only used for aggregated totals, see FormatMemoryTotal
+}
+
+var unitScales = map[UnitSystem]unitScale{
+	UnitsLegacy:  {base: 1024, kb: "KB", mb: "MB", gb: "GB", tb: "TB", pb: "PB"},
+	UnitsBinary:  {base: 1024, kb: "KiB", mb: "MiB", gb: "GiB", tb: "TiB", pb: "PiB"},
+	UnitsDecimal: {base: 1000, kb: "KB", mb: "MB", gb: "GB", tb: "TB", pb: "PB"},
 }
 
-// FormatMemory formats a memory quantity in human-readable format
+// memoryUnits is the unit system used by FormatMemory
+var memoryUnits = UnitsLegacy
+
+// SetUnitSystem sets the unit system used by FormatMemory
+func SetUnitSystem(units UnitSystem) {
+	memoryUnits = units
+}
+
+// FormatMemory formats a memory quantity in human-readable format using the configured unit system
 func FormatMemory(q *resource.Quantity) string {
+	return FormatMemoryWithUnits(q, memoryUnits)
+}
+
+// FormatMemoryWithUnits formats a memory quantity in human-readable format using the given unit system
+// Negative quantities keep their sign and are scaled by their absolute value
+func FormatMemoryWithUnits(q *resource.Quantity, units UnitSystem) string {
 	if q == nil {
 		return "N/A"
 	}
+	if q.Sign() < 0 {
+		abs := q.DeepCopy()
+		abs.Neg()
+		return "-" + FormatMemoryWithUnits(&abs, units)
+	}
 
-	value := q.Value()
-
-	// Convert to appropriate unit
-	const (
-		gb = 1024 * 1024 * 1024
-		mb = 1024 * 1024
-		kb = 1024
-	)
+	value := float64(q.Value())
+	scale := unitScales[units]
+	kb := scale.base
+	mb := kb * scale.base
+	gb := mb * scale.base
 
 	switch {
 	case value >= gb:
-		return fmt.Sprintf("%.2f GB", float64(value)/gb)
+		return fmt.Sprintf("%.2f %s", value/gb, scale.gb)
 	case value >= mb:
-		return fmt.Sprintf("%.1f MB", float64(value)/mb)
+		return fmt.Sprintf("%.1f %s", value/mb, scale.mb)
 	case value >= kb:
-		return fmt.Sprintf("%.1f KB", float64(value)/kb)
+		return fmt.Sprintf("%.1f %s", value/kb, scale.kb)
 	default:
-		return fmt.Sprintf("%d B", value)
+		return fmt.Sprintf("%d B", q.Value())
+	}
+}
+
+// FormatMemoryTotal formats an aggregated quantity, such as a cluster total, using the configured unit system
+func FormatMemoryTotal(q *resource.Quantity) string {
+	return FormatMemoryTotalWithUnits(q, memoryUnits)
+}
+
+// FormatMemoryTotalWithUnits formats an aggregated quantity using the given unit system
+// Totals of a terabyte or more are scaled to TB or PB with integer arithmetic: a float64 cannot hold
+// every byte count at petabyte scale, and sums of many pods can exceed the int64 range of Value
+func FormatMemoryTotalWithUnits(q *resource.Quantity, units UnitSystem) string {
+	if q == nil || q.Sign() < 0 {
+		return FormatMemoryWithUnits(q, units)
 	}
+
+	scale := unitScales[units]
+	base := big.NewInt(int64(scale.base))
+	unit, label := new(big.Int).Exp(base, big.NewInt(4), nil), scale.tb
+	bytes := quantityBytes(q)
+	if bytes.Cmp(unit) < 0 {
+		return FormatMemoryWithUnits(q, units)
+	}
+	if pb := new(big.Int).Mul(unit, base); bytes.Cmp(pb) >= 0 {
+		unit, label = pb, scale.pb
+	}
+
+	// Hundredths of the unit, rounded half up
+	hundredths := new(big.Int).Mul(bytes, big.NewInt(100))
+	hundredths.Add(hundredths, new(big.Int).Rsh(unit, 1))
+	hundredths.Quo(hundredths, unit)
+	whole, frac := new(big.Int).QuoRem(hundredths, big.NewInt(100), new(big.Int))
+	return fmt.Sprintf("%s.%02d %s", whole, frac.Int64(), label)
+}
+
+// quantityBytes returns the exact byte count of q, dropping fractional bytes
+func quantityBytes(q *resource.Quantity) *big.Int {
+	cp := q.DeepCopy()
+	dec := cp.AsDec()
+	bytes := new(big.Int).Set(dec.UnscaledBig())
+	exponent := int64(dec.Scale())
+	if exponent == 0 {
+		return bytes
+	}
+	power := new(big.Int).Exp(big.NewInt(10), big.NewInt(max(exponent, -exponent)), nil)
+	if exponent > 0 {
+		return bytes.Quo(bytes, power)
+	}
+	return bytes.Mul(bytes, power)
+}
+
+// percentPrecision is the number of decimals used by FormatPercent
+var percentPrecision = 1
+
+// SetPercentPrecision sets the number of decimals used when formatting percentages for display
+func SetPercentPrecision(precision int) {
+	percentPrecision = precision
 }
 
-// FormatPercent formats a percentage value
+// FormatPercent formats a percentage value using the configured display precision
 func FormatPercent(percent *float64) string {
+	return FormatPercentWithPrecision(percent, percentPrecision)
+}
+
+// FormatPercentWithPrecision formats a percentage value with the given number of decimals
+func FormatPercentWithPrecision(percent *float64, precision int) string {
 	if percent == nil {
 		return "N/A"
 	}
-	return fmt.Sprintf("%.1f%%", *percent)
+	return fmt.Sprintf("%.*f%%", precision, *percent)
 }
 
 // CalculateUsagePercent calculates usage percentage against request or limit
@@ -137,6 +379,8 @@ func (p *PodMemoryInfo) CalculateUsagePercent() {
 		percent := (currentValue / limitValue) * 100
 		p.LimitUsagePercent = &percent
 	}
+
+	p.Headroom = calculateHeadroom(p.CurrentUsage, p.MemoryLimit)
 }
 
 // String provides a human-readable representation of pod memory info
@@ -156,3 +400,16 @@ func (p *PodMemoryInfo) String() string {
 		FormatPercent(p.LimitUsagePercent),
 	)
 }
+
+// NotReadyReason returns a short explanation of the most informative failing condition
+// It is empty for ready pods or when the pod reports no failing condition
+func (p *PodMemoryInfo) NotReadyReason() string {
+	if p.Ready || len(p.NotReadyConditions) == 0 {
+		return ""
+	}
+	condition := p.NotReadyConditions[0]
+	if condition.Reason == "" {
+		return condition.Type
+	}
+	return condition.Type + ": " + condition.Reason
+}
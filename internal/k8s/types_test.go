@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 	"time"
 
@@ -38,6 +40,21 @@ func TestFormatMemory(t *testing.T) {
 			quantity: resource.NewQuantity(1024*1024*1024*2, resource.BinarySI),
 			expected: "2.00 GB",
 		},
+		{
+			name:     "zero",
+			quantity: resource.NewQuantity(0, resource.BinarySI),
+			expected: "0 B",
+		},
+		{
+			name:     "negative megabytes",
+			quantity: resource.NewQuantity(-5*1024*1024, resource.BinarySI), // -5Mi
+			expected: "-5.0 MB",
+		},
+		{
+			name:     "negative bytes",
+			quantity: resource.NewQuantity(-512, resource.BinarySI),
+			expected: "-512 B",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -232,3 +249,181 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCalculateUsagePercent_Headroom(t *testing.T) {
+	pod := &PodMemoryInfo{
+		CurrentUsage: resource.NewQuantity(300*1024*1024, resource.BinarySI),
+		MemoryLimit:  resource.NewQuantity(256*1024*1024, resource.BinarySI),
+	}
+	pod.CalculateUsagePercent()
+
+	if pod.Headroom == nil || pod.Headroom.Value() != -44*1024*1024 {
+		t.Fatalf("expected negative headroom of 44Mi, got %v", pod.Headroom)
+	}
+	if got := FormatMemory(pod.Headroom); got != "-44.0 MB" {
+		t.Errorf("FormatMemory() of the headroom = %v, want -44.0 MB", got)
+	}
+
+	container := &ContainerMemoryInfo{CurrentUsage: resource.NewQuantity(1, resource.BinarySI)}
+	container.CalculateUsagePercent()
+	if container.Headroom != nil {
+		t.Errorf("expected nil headroom without a limit, got %v", container.Headroom)
+	}
+}
+
+func TestContainerMemoryInfo_RequestLimitRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *resource.Quantity
+		limit    *resource.Quantity
+		expected *float64
+	}{
+		{"guaranteed", resource.NewQuantity(512, resource.BinarySI), resource.NewQuantity(512, resource.BinarySI), floatPtr(100)},
+		{"burstable", resource.NewQuantity(128, resource.BinarySI), resource.NewQuantity(512, resource.BinarySI), floatPtr(25)},
+		{"no request", nil, resource.NewQuantity(512, resource.BinarySI), nil},
+		{"no limit", resource.NewQuantity(128, resource.BinarySI), nil, nil},
+		{"zero limit", resource.NewQuantity(128, resource.BinarySI), resource.NewQuantity(0, resource.BinarySI), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The ratio does not depend on usage, so it is set even without metrics
+			c := &ContainerMemoryInfo{MemoryRequest: tt.request, MemoryLimit: tt.limit}
+			c.CalculateUsagePercent()
+			if (c.RequestLimitRatio == nil) != (tt.expected == nil) {
+				t.Fatalf("RequestLimitRatio = %v, want %v", c.RequestLimitRatio, tt.expected)
+			}
+			if tt.expected != nil && *c.RequestLimitRatio != *tt.expected {
+				t.Errorf("RequestLimitRatio = %.1f, want %.1f", *c.RequestLimitRatio, *tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatPercentWithPrecision(t *testing.T) {
+	percent := 75.456
+	testCases := []struct {
+		precision int
+		expected  string
+	}{
+		{precision: 0, expected: "75%"},
+		{precision: 1, expected: "75.5%"},
+		{precision: 2, expected: "75.46%"},
+	}
+
+	for _, tc := range testCases {
+		if got := FormatPercentWithPrecision(&percent, tc.precision); got != tc.expected {
+			t.Errorf("FormatPercentWithPrecision(%d) = %v, want %v", tc.precision, got, tc.expected)
+		}
+	}
+	if got := FormatPercentWithPrecision(nil, 0); got != "N/A" {
+		t.Errorf("FormatPercentWithPrecision(nil) = %v, want N/A", got)
+	}
+}
+
+func TestFormatMemoryWithUnits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		quantity *resource.Quantity
+		units    UnitSystem
+		expected string
+	}{
+		{name: "legacy megabytes", quantity: resource.NewQuantity(1024*1024*100, resource.BinarySI), units: UnitsLegacy, expected: "100.0 MB"},
+		{name: "binary megabytes", quantity: resource.NewQuantity(1024*1024*100, resource.BinarySI), units: UnitsBinary, expected: "100.0 MiB"},
+		{name: "binary gigabytes", quantity: resource.NewQuantity(1024*1024*1024*2, resource.BinarySI), units: UnitsBinary, expected: "2.00 GiB"},
+		{name: "decimal megabytes", quantity: resource.NewQuantity(100*1000*1000, resource.DecimalSI), units: UnitsDecimal, expected: "100.0 MB"},
+		{name: "decimal kilobytes", quantity: resource.NewQuantity(1500, resource.DecimalSI), units: UnitsDecimal, expected: "1.5 KB"},
+		{name: "bytes", quantity: resource.NewQuantity(512, resource.BinarySI), units: UnitsDecimal, expected: "512 B"},
+		{name: "negative binary", quantity: resource.NewQuantity(-5*1024*1024, resource.BinarySI), units: UnitsBinary, expected: "-5.0 MiB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatMemoryWithUnits(tc.quantity, tc.units); got != tc.expected {
+				t.Errorf("FormatMemoryWithUnits() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFormatMemoryTotalWithUnits(t *testing.T) {
+	const tib, pib, eib = int64(1) << 40, int64(1) << 50, int64(1) << 60
+
+	// Sums beyond the int64 range of Quantity.Value, as with thousands of large pods
+	overflow := resource.NewQuantity(6*eib, resource.BinarySI)
+	overflow.Add(*resource.NewQuantity(6*eib, resource.BinarySI))
+
+	// One byte over 8 PiB, the first byte counts a float64 can no longer represent exactly
+	precise := resource.NewQuantity(8*pib+1, resource.BinarySI)
+
+	testCases := []struct {
+		name     string
+		quantity *resource.Quantity
+		units    UnitSystem
+		expected string
+	}{
+		{name: "nil quantity", quantity: nil, units: UnitsLegacy, expected: "N/A"},
+		{name: "below a terabyte", quantity: resource.NewQuantity(2<<30, resource.BinarySI), units: UnitsLegacy, expected: "2.00 GB"},
+		{name: "legacy terabytes", quantity: resource.NewQuantity(tib+tib/2, resource.BinarySI), units: UnitsLegacy, expected: "1.50 TB"},
+		{name: "binary terabytes", quantity: resource.NewQuantity(3*tib, resource.BinarySI), units: UnitsBinary, expected: "3.00 TiB"},
+		{name: "decimal petabytes", quantity: resource.NewQuantity(2_500_000_000_000_000, resource.DecimalSI), units: UnitsDecimal, expected: "2.50 PB"},
+		{name: "legacy petabytes", quantity: resource.NewQuantity(2*pib+pib/2, resource.BinarySI), units: UnitsLegacy, expected: "2.50 PB"},
+		{name: "rounds to hundredths", quantity: resource.NewQuantity(pib+pib/128, resource.BinarySI), units: UnitsBinary, expected: "1.01 PiB"},
+		{name: "precise byte count", quantity: precise, units: UnitsBinary, expected: "8.00 PiB"},
+		{name: "beyond int64", quantity: overflow, units: UnitsBinary, expected: "12288.00 PiB"},
+		{name: "negative", quantity: resource.NewQuantity(-5*1024*1024, resource.BinarySI), units: UnitsBinary, expected: "-5.0 MiB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatMemoryTotalWithUnits(tc.quantity, tc.units); got != tc.expected {
+				t.Errorf("FormatMemoryTotalWithUnits() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestQuantityBytes_Exact(t *testing.T) {
+	q := resource.NewQuantity(1<<62, resource.BinarySI)
+	q.Add(*resource.NewQuantity(1<<62, resource.BinarySI))
+	q.Add(*resource.NewQuantity(1<<62, resource.BinarySI))
+	q.Add(*resource.NewQuantity(1, resource.BinarySI))
+
+	expected, _ := new(big.Int).SetString("13835058055282163713", 10)
+	if got := quantityBytes(q); got.Cmp(expected) != 0 {
+		t.Errorf("quantityBytes() = %s, want %s", got, expected)
+	}
+	if got := quantityBytes(resource.NewMilliQuantity(1500, resource.DecimalSI)); got.Int64() != 1 {
+		t.Errorf("expected fractional bytes to be dropped, got %s", got)
+	}
+}
+
+func TestCollectionTimings_MarshalJSONInMilliseconds(t *testing.T) {
+	timings := CollectionTimings{Total: 1500 * time.Millisecond, PodList: 900 * time.Millisecond, Metrics: 400 * time.Millisecond}
+
+	data, err := json.Marshal(timings)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	expected := `{"collection_duration_ms":1500,"pod_list_duration_ms":900,"metrics_duration_ms":400}`
+	if string(data) != expected {
+		t.Errorf("Marshal() = %s, want %s", data, expected)
+	}
+}
+
+func TestMemorySummaryMerge(t *testing.T) {
+	summary := &MemorySummary{TotalPods: 2, NamespaceCount: 3, TotalMemoryUsage: resource.MustParse("100Mi")}
+	summary.Merge(&MemorySummary{TotalPods: 4, NamespaceCount: 5, TotalMemoryUsage: resource.MustParse("50Mi"),
+		Partial: true, NamespacesCollected: 2})
+
+	if summary.TotalPods != 6 || summary.NamespaceCount != 8 {
+		t.Errorf("expected 6 pods over 8 namespaces, got %d over %d", summary.TotalPods, summary.NamespaceCount)
+	}
+	if summary.TotalMemoryUsage.Value() != 150*1024*1024 {
+		t.Errorf("expected 150Mi usage, got %s", summary.TotalMemoryUsage.String())
+	}
+	if !summary.Partial || summary.NamespacesCollected != 5 {
+		t.Errorf("expected a partial summary covering 5 namespaces, got partial=%v collected=%d",
+			summary.Partial, summary.NamespacesCollected)
+	}
+}
@@ -0,0 +1,39 @@
+package k8s
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadKinds maps controller kinds to the short names kubectl uses
+var workloadKinds = map[string]string{
+	"Deployment":  "deploy",
+	"ReplicaSet":  "rs",
+	"StatefulSet": "sts",
+	"DaemonSet":   "ds",
+	"Job":         "job",
+}
+
+// workloadOf identifies the workload a pod belongs to, e.g. "deploy/web", or empty for standalone pods
+// ReplicaSets created by a Deployment are attributed to the Deployment by dropping the pod-template-hash suffix
+func workloadOf(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+
+	kind, name := owner.Kind, owner.Name
+	if hash := pod.Labels["pod-template-hash"]; kind == "ReplicaSet" && hash != "" {
+		if deployment, ok := strings.CutSuffix(name, "-"+hash); ok {
+			kind, name = "Deployment", deployment
+		}
+	}
+
+	short, ok := workloadKinds[kind]
+	if !ok {
+		short = strings.ToLower(kind)
+	}
+	return short + "/" + name
+}
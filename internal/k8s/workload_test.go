@@ -0,0 +1,39 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkloadOf(t *testing.T) {
+	controller := true
+	owned := func(kind, name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected string
+	}{
+		{"standalone pod", &corev1.Pod{}, ""},
+		{"deployment replica", owned("ReplicaSet", "web-7d9f8b6c5", map[string]string{"pod-template-hash": "7d9f8b6c5"}), "deploy/web"},
+		{"bare replicaset", owned("ReplicaSet", "web", nil), "rs/web"},
+		{"statefulset", owned("StatefulSet", "db", nil), "sts/db"},
+		{"daemonset", owned("DaemonSet", "agent", nil), "ds/agent"},
+		{"unknown kind", owned("Rollout", "canary", nil), "rollout/canary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workloadOf(tt.pod); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
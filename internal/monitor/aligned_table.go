@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// alignedTable collects rows and renders them with tab-aligned columns
+// Status symbols are kept outside the aligned cells because emoji render two columns wide
+// while tabwriter counts them as one, and colors are applied after alignment so escape
+// sequences do not skew the column widths
+type alignedTable struct {
+	useColor bool
+	indent   string // Prefix added to every row
+	symbols  []string
+	statuses []string
+	rows     []string
+	notes    map[int][]string // Unaligned lines printed after the row with the given index
+}
+
+// newAlignedTable creates an empty table, colorizing rows by status when useColor is set
+func newAlignedTable(useColor bool) *alignedTable {
+	return &alignedTable{useColor: useColor}
+}
+
+// addRow appends a row prefixed by symbol (empty for none) and colored by status (empty for none)
+func (t *alignedTable) addRow(symbol, status string, cells ...string) {
+	t.symbols = append(t.symbols, symbol)
+	t.statuses = append(t.statuses, status)
+	t.rows = append(t.rows, strings.Join(cells, "\t"))
+}
+
+// addNote appends a line printed verbatim after the last added row, without affecting alignment
+func (t *alignedTable) addNote(line string) {
+	if t.notes == nil {
+		t.notes = make(map[int][]string)
+	}
+	row := len(t.rows) - 1
+	t.notes[row] = append(t.notes[row], line)
+}
+
+// String renders the table, one line per row
+func (t *alignedTable) String() string {
+	if len(t.rows) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, row := range t.rows {
+		fmt.Fprintln(w, row)
+	}
+	_ = w.Flush()
+
+	// Custom symbols may differ in width, so pad them all to the widest
+	symbolColumn := 2
+	for _, symbol := range t.symbols {
+		symbolColumn = max(symbolColumn, symbolWidth(symbol))
+	}
+
+	var out strings.Builder
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		symbol := t.symbols[i] + strings.Repeat(" ", symbolColumn-symbolWidth(t.symbols[i]))
+		line = symbol + " " + strings.TrimRight(line, " ")
+		if t.useColor {
+			line = colorize(line, t.statuses[i])
+		}
+		out.WriteString(t.indent + line + "\n")
+		for _, note := range t.notes[i] {
+			out.WriteString(note + "\n")
+		}
+	}
+	return out.String()
+}
+
+// symbolWidth approximates the terminal columns taken by a status symbol:
+// emoji and CJK characters take two, variation selectors none, anything else one
+func symbolWidth(symbol string) int {
+	width := 0
+	for _, r := range symbol {
+		switch {
+		case r >= 0xFE00 && r <= 0xFE0F:
+		case r >= 0x1F000, r >= 0x2600 && r <= 0x27BF, r >= 0x2E80 && r <= 0xA4CF, r >= 0xAC00 && r <= 0xD7A3:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
@@ -23,9 +23,14 @@ func (r *AnalysisReporter) PrintAnalysis(analysis *AnalysisResult, cfg *config.C
 	r.printProblems(analysis)
 	r.printHighUsagePods(analysis, cfg)
 	r.printWarningPods(analysis, cfg)
+	r.printContainerHotspots(analysis, cfg)
+	r.printUnschedulablePods(analysis)
+	if !cfg.NoMetrics {
+		analysis.Report.PrintUnmonitoredPods()
+	}
 
 	fmt.Printf("\n")
-	printRecommendations(analysis)
+	printRecommendations(analysis, cfg)
 }
 
 // printProblems prints the detected problems
@@ -35,10 +40,13 @@ func (r *AnalysisReporter) printProblems(analysis *AnalysisResult) {
 		return
 	}
 
-	fmt.Printf("🚨 Found %d potential issues:\n\n", len(analysis.ProblemsFound))
+	fmt.Printf("🚨 Found %d potential issues:\n\n", analysis.ProblemCount())
 	for i, problem := range analysis.ProblemsFound {
 		fmt.Printf("%d. %s\n", i+1, problem)
 	}
+	if analysis.DroppedProblems > 0 {
+		fmt.Printf("... and %d more (raise --max-problems to list them)\n", analysis.DroppedProblems)
+	}
 }
 
 // printHighUsagePods prints pods with high memory usage
@@ -51,7 +59,7 @@ func (r *AnalysisReporter) printHighUsagePods(analysis *AnalysisResult, cfg *con
 	fmt.Printf("\n🔥 High Memory Usage Pods (%d):\n", len(filteredHigh))
 	for i := range filteredHigh {
 		pod := &filteredHigh[i]
-		fmt.Printf("  %s\n", formatPodInfo(pod, cfg))
+		fmt.Printf("  %s\n", formatPodInfo(pod, cfg, analysis.Report.MultiCluster()))
 	}
 }
 
@@ -68,8 +76,61 @@ func (r *AnalysisReporter) printWarningPods(analysis *AnalysisResult, cfg *confi
 	for i := range filteredWarn {
 		pod := &filteredWarn[i]
 		if !contains(filteredHigh, pod) {
-			fmt.Printf("  %s\n", formatPodInfo(pod, cfg))
+			fmt.Printf("  %s\n", formatPodInfo(pod, cfg, analysis.Report.MultiCluster()))
+		}
+	}
+}
+
+// printContainerHotspots prints containers in a critical state regardless of their pod's limit state,
+// so containers over their own limit in partially limited pods are not hidden by the pod sections.
+// Pods are prefixed with their cluster in multi-cluster reports
+func (r *AnalysisReporter) printContainerHotspots(analysis *AnalysisResult, cfg *config.Config) {
+	multiCluster := analysis.Report.MultiCluster()
+	var lines []string
+	for i := range analysis.Report.Pods {
+		pod := &analysis.Report.Pods[i]
+		id := podIDOf(pod)
+		if !multiCluster {
+			id.Cluster = ""
+		}
+		for j := range pod.Containers {
+			container := &pod.Containers[j]
+			container.CalculateUsagePercent()
+			if getContainerMemoryStatus(pod, container, cfg).Status != "critical" {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s/%s | Usage: %s | Request: %s (%s) | Limit: %s (%s)",
+				symbolOrDefault(cfg.SymbolCritical, config.DefaultSymbolCritical),
+				id, container.ContainerName,
+				k8s.FormatMemory(container.CurrentUsage),
+				k8s.FormatMemory(container.MemoryRequest), k8s.FormatPercent(container.UsagePercent),
+				k8s.FormatMemory(container.MemoryLimit), k8s.FormatPercent(container.LimitUsagePercent)))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🌡️  Container Hotspots (%d):\n", len(lines))
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+// printUnschedulablePods prints pending pods the scheduler could not place, with its explanation
+func (r *AnalysisReporter) printUnschedulablePods(analysis *AnalysisResult) {
+	if len(analysis.Unschedulable) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⏳ Unschedulable Pods (%d):\n", len(analysis.Unschedulable))
+	for i := range analysis.Unschedulable {
+		pod := &analysis.Unschedulable[i]
+		line := fmt.Sprintf("  %s/%s | Request: %s", pod.Namespace, pod.PodName, k8s.FormatMemory(pod.MemoryRequest))
+		if condition := pod.UnschedulableCondition(); condition != nil && condition.Message != "" {
+			line += " | " + condition.Message
 		}
+		fmt.Println(line)
 	}
 }
 
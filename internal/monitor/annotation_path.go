@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxRawAnnotationRunes caps the raw value shown when a JSON path cannot be extracted, since annotations
+// such as kubectl.kubernetes.io/last-applied-configuration often hold several KB of JSON
+const maxRawAnnotationRunes = 64
+
+// annotationValue returns the value of a requested annotation, e.g. "team.io/owner" or "meta.io/info:$.team"
+// When the request carries a JSON path after ':' the extracted field is returned, or the raw value
+// truncated to maxRawAnnotationRunes if it cannot be extracted
+func annotationValue(podAnnotations map[string]string, requested string) (string, bool) {
+	key, path, hasPath := strings.Cut(requested, ":")
+	value, exists := podAnnotations[key]
+	if !exists || !hasPath {
+		return value, exists
+	}
+	if extracted, err := evalJSONPath(value, path); err == nil {
+		return extracted, true
+	}
+	if runes := []rune(value); len(runes) > maxRawAnnotationRunes {
+		value = string(runes[:maxRawAnnotationRunes]) + "…"
+	}
+	return value, true
+}
+
+// annotationColumn returns the CSV column name of a requested annotation, built from the key and the
+// fields of its JSON path, e.g. "annotation_meta_io/info_team" for "meta.io/info:$.team"
+func annotationColumn(requested string) string {
+	key, path, hasPath := strings.Cut(requested, ":")
+	column := "annotation_" + strings.ReplaceAll(key, ".", "_")
+	if !hasPath {
+		return column
+	}
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		// Keep the raw path readable, without the JSON path punctuation
+		segments = strings.FieldsFunc(path, func(r rune) bool { return strings.ContainsRune("$.[]'\"", r) })
+	}
+	for _, segment := range segments {
+		column += "_" + strings.ReplaceAll(segment, ".", "_")
+	}
+	return column
+}
+
+// evalJSONPath evaluates a simple JSON path such as $.spec.containers[0].name against a JSON document
+// Scalars are returned as plain text; objects and arrays as compact JSON
+func evalJSONPath(document, path string) (string, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var node any
+	if err := decoder.Decode(&node); err != nil {
+		return "", fmt.Errorf("failed to parse annotation JSON: %w", err)
+	}
+
+	for _, segment := range segments {
+		switch current := node.(type) {
+		case map[string]any:
+			field, ok := current[segment]
+			if !ok {
+				return "", fmt.Errorf("field %q not found", segment)
+			}
+			node = field
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(current) {
+				return "", fmt.Errorf("index %q out of range", segment)
+			}
+			node = current[index]
+		default:
+			return "", fmt.Errorf("cannot select %q from a scalar", segment)
+		}
+	}
+
+	switch value := node.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case nil:
+		return "", nil
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return "", fmt.Errorf("failed to encode JSON value: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	}
+}
+
+// parseJSONPath splits a path like $.a.b[0]['c.d'] into its field names and indexes
+func parseJSONPath(path string) ([]string, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("JSON path %q must start with '$'", path)
+	}
+
+	var segments []string
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			if end == 1 {
+				return nil, fmt.Errorf("empty field name in JSON path %q", path)
+			}
+			segments = append(segments, rest[1:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in JSON path %q", path)
+			}
+			segment := rest[1:end]
+			if quoted, err := strconv.Unquote(strings.ReplaceAll(segment, "'", `"`)); err == nil {
+				segment = quoted
+			} else if _, err := strconv.Atoi(segment); err != nil {
+				return nil, fmt.Errorf("invalid index %q in JSON path %q", segment, path)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSON path %q", rest[0], path)
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	document := `{"team":"platform","replicas":3,"debug":false,"owner":null,` +
+		`"containers":[{"name":"app"},{"name":"sidecar"}],"a.b":{"c":"dotted"}}`
+
+	tests := []struct {
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		{"$.team", "platform", false},
+		{"$.replicas", "3", false},
+		{"$.debug", "false", false},
+		{"$.owner", "", false},
+		{"$.containers[1].name", "sidecar", false},
+		{"$['a.b'].c", "dotted", false},
+		{"$.containers[0]", `{"name":"app"}`, false},
+		{"$.missing", "", true},
+		{"$.containers[5]", "", true},
+		{"$.team.name", "", true},
+		{"team", "", true},
+		{"$.", "", true},
+		{"$[x]", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := evalJSONPath(document, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalJSONPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("evalJSONPath(%q) = %q, want %q", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnnotationValue(t *testing.T) {
+	annotations := map[string]string{
+		"owner":        "alice",
+		"meta.io/info": `{"team":"payments"}`,
+		"meta.io/raw":  "not json",
+	}
+
+	tests := []struct {
+		requested string
+		expected  string
+		exists    bool
+	}{
+		{"owner", "alice", true},
+		{"meta.io/info:$.team", "payments", true},
+		{"meta.io/info:$.missing", `{"team":"payments"}`, true},
+		{"meta.io/raw:$.team", "not json", true},
+		{"absent:$.team", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			got, exists := annotationValue(annotations, tt.requested)
+			if got != tt.expected || exists != tt.exists {
+				t.Errorf("annotationValue(%q) = %q, %v; want %q, %v", tt.requested, got, exists, tt.expected, tt.exists)
+			}
+		})
+	}
+}
+
+func TestAnnotationValue_TruncatesRawFallback(t *testing.T) {
+	applied := `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","labels":{"app":"web"}},` +
+		`"spec":{"containers":[{"name":"app","image":"registry.example.com/team/app:1.2.3"}]}}`
+	annotations := map[string]string{"kubectl.kubernetes.io/last-applied-configuration": strings.Repeat(applied, 50)}
+
+	got, exists := annotationValue(annotations, "kubectl.kubernetes.io/last-applied-configuration:$.metdata.name")
+	if !exists {
+		t.Fatal("expected the annotation to exist")
+	}
+	expected := string([]rune(applied)[:maxRawAnnotationRunes]) + "…"
+	if got != expected {
+		t.Errorf("expected the raw value truncated to %d runes, got %q", maxRawAnnotationRunes, got)
+	}
+
+	// Short raw values are kept whole
+	if got, _ := annotationValue(map[string]string{"meta.io/raw": "not json"}, "meta.io/raw:$.team"); got != "not json" {
+		t.Errorf("expected a short raw value to be kept, got %q", got)
+	}
+}
+
+func TestAnnotationColumn(t *testing.T) {
+	tests := []struct {
+		requested string
+		expected  string
+	}{
+		{"owner", "annotation_owner"},
+		{"team.io/owner", "annotation_team_io/owner"},
+		{"meta.io/info:$.team", "annotation_meta_io/info_team"},
+		{"meta.io/info:$.containers[0].name", "annotation_meta_io/info_containers_0_name"},
+		{"meta.io/info:$['a.b'].c", "annotation_meta_io/info_a_b_c"},
+		{"meta.io/info:team", "annotation_meta_io/info_team"}, // Invalid path, still readable
+	}
+	for _, tt := range tests {
+		if got := annotationColumn(tt.requested); got != tt.expected {
+			t.Errorf("annotationColumn(%q) = %q, want %q", tt.requested, got, tt.expected)
+		}
+	}
+
+	header := NewCSVFormatter().buildHeader(&config.Config{Annotations: []string{"meta.io/info:$.team"}}, false)
+	if last := header[len(header)-1]; last != "annotation_meta_io/info_team" {
+		t.Errorf("expected the annotation column in the CSV header, got %q", last)
+	}
+}
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// APICallTotals accumulates the API requests of every cycle and serves them as Prometheus counters
+// It is shared between the monitor, which adds each cycle's counts, and the /metrics handler
+type APICallTotals struct {
+	mu     sync.Mutex
+	totals k8s.APICallCounts
+}
+
+// NewAPICallTotals creates totals starting at zero
+func NewAPICallTotals() *APICallTotals {
+	return &APICallTotals{}
+}
+
+// add accumulates the counts of one cycle; nil totals ignore them
+func (t *APICallTotals) add(calls k8s.APICallCounts) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.totals.Add(calls)
+}
+
+// WriteMetrics writes the totals in the Prometheus text exposition format
+func (t *APICallTotals) WriteMetrics(w io.Writer) error {
+	t.mu.Lock()
+	totals := t.totals
+	t.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP k8s_memory_api_requests_total Requests sent to the Kubernetes API server, by request type.\n")
+	b.WriteString("# TYPE k8s_memory_api_requests_total counter\n")
+	for _, request := range []struct {
+		name  string
+		count int64
+	}{
+		{"namespace_list", totals.NamespaceLists},
+		{"pod", totals.PodRequests},
+		{"metrics", totals.MetricsRequests},
+		{"node_list", totals.NodeLists},
+	} {
+		fmt.Fprintf(&b, "k8s_memory_api_requests_total{request=\"%s\"} %d\n", request.name, request.count)
+	}
+	b.WriteString("# HELP k8s_memory_pods_listed_total Pods returned by pod list requests.\n")
+	b.WriteString("# TYPE k8s_memory_pods_listed_total counter\n")
+	fmt.Fprintf(&b, "k8s_memory_pods_listed_total %d\n", totals.PodsListed)
+	b.WriteString("# HELP k8s_memory_namespaces_processed_total Namespaces whose pods were collected.\n")
+	b.WriteString("# TYPE k8s_memory_namespaces_processed_total counter\n")
+	fmt.Fprintf(&b, "k8s_memory_namespaces_processed_total %d\n", totals.NamespacesProcessed)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// MetricsHandler serves /metrics: the OOMKilled restarts and the API requests sent so far
+func (m *MemoryMonitor) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := m.oomKills.WriteMetrics(w); err != nil {
+			return
+		}
+		_ = m.apiCalls.WriteMetrics(w)
+	})
+}
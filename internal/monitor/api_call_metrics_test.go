@@ -0,0 +1,39 @@
+package monitor
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler_ServesAPICallTotals(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(), testPod{namespace: "prod", name: "web", usage: "100Mi", request: "256Mi", limit: "512Mi"})
+	m.oomKills = NewOOMCounter()
+	m.apiCalls = NewAPICallTotals()
+	for i := 0; i < 2; i++ {
+		if _, err := m.CollectMemoryInfo(context.Background()); err != nil {
+			t.Fatalf("CollectMemoryInfo() failed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	m.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain content type, got %q", ct)
+	}
+	// The counts accumulate across cycles rather than being reset like the per-cycle log
+	for _, expected := range []string{
+		"# TYPE k8s_memory_oom_total counter",
+		`k8s_memory_api_requests_total{request="pod"} 2`,
+		`k8s_memory_api_requests_total{request="metrics"} 2`,
+		"k8s_memory_pods_listed_total 2",
+		"k8s_memory_namespaces_processed_total 2",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %s in:\n%s", expected, body)
+		}
+	}
+}
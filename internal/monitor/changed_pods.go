@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"math"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// changedUsageBucketPercent is the width of the usage steps compared by --watch-pods-changed-only
+// Smaller usage movements are not worth a line in the report
+const changedUsageBucketPercent = 10
+
+// podSnapshot is what --watch-pods-changed-only compares between cycles
+type podSnapshot struct {
+	status string
+	bucket int
+}
+
+// usageBucket places the pod's primary usage percentage in changedUsageBucketPercent steps, -1 without one
+func usageBucket(percent *float64) int {
+	if percent == nil {
+		return -1
+	}
+	return int(math.Floor(*percent / changedUsageBucketPercent))
+}
+
+// detectChangedPods returns the pods whose status or usage bucket differs from previous, with the current snapshots
+// Pods missing from previous are new and therefore changed
+func detectChangedPods(previous map[podID]podSnapshot, pods []k8s.PodMemoryInfo,
+	snapshotOf func(*k8s.PodMemoryInfo) podSnapshot) (map[podID]bool, map[podID]podSnapshot) {
+	changed := make(map[podID]bool)
+	current := make(map[podID]podSnapshot, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		id := podIDOf(pod)
+		snapshot := snapshotOf(pod)
+		current[id] = snapshot
+		if last, known := previous[id]; !known || last != snapshot {
+			changed[id] = true
+		}
+	}
+	return changed, current
+}
+
+// trackChangedPods records which pods changed since the previous cycle for the table report
+// The first cycle leaves the report unfiltered so every pod is printed once; after a partial
+// collection the snapshots of namespaces not reached are kept
+func (m *MemoryMonitor) trackChangedPods(analysis *AnalysisResult) {
+	if !m.config.WatchChangedOnly {
+		return
+	}
+
+	changed, current := detectChangedPods(m.podSnapshots, analysis.Report.Pods, func(pod *k8s.PodMemoryInfo) podSnapshot {
+		pod.CalculateUsagePercent()
+		return podSnapshot{
+			status: getMemoryStatus(pod, m.config).Status,
+			bucket: usageBucket(primaryPercent(m.config, pod.UsagePercent, pod.LimitUsagePercent)),
+		}
+	})
+	if m.podSnapshots == nil {
+		m.podSnapshots = current
+		return
+	}
+	if analysis.Report.Summary.Partial {
+		for id, snapshot := range current {
+			m.podSnapshots[id] = snapshot
+		}
+	} else {
+		m.podSnapshots = current
+	}
+	analysis.Report.ChangedPods = changed
+}
+
+// filterChangedPods keeps the pods marked as changed, in their report order
+func filterChangedPods(pods []k8s.PodMemoryInfo, changed map[podID]bool) []k8s.PodMemoryInfo {
+	var result []k8s.PodMemoryInfo
+	for i := range pods {
+		if changed[podIDOf(&pods[i])] {
+			result = append(result, pods[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestUsageBucket(t *testing.T) {
+	tests := []struct {
+		percent  *float64
+		expected int
+	}{
+		{nil, -1},
+		{pct(0), 0},
+		{pct(49.9), 4},
+		{pct(50), 5},
+		{pct(120), 12},
+	}
+	for _, tt := range tests {
+		if got := usageBucket(tt.percent); got != tt.expected {
+			t.Errorf("usageBucket(%v) = %d, expected %d", tt.percent, got, tt.expected)
+		}
+	}
+}
+
+func TestAnalyzeMemoryUsage_TracksChangedPods(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.Watch = true
+	cfg.WatchChangedOnly = true
+	m := newTestMonitor(cfg,
+		testPod{namespace: "prod", name: "api", usage: "50Mi", request: "100Mi", limit: "200Mi"},
+		testPod{namespace: "prod", name: "worker", usage: "30Mi", request: "100Mi", limit: "200Mi"},
+	)
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	if analysis.Report.ChangedPods != nil {
+		t.Errorf("expected the first cycle to list every pod, got %v", analysis.Report.ChangedPods)
+	}
+
+	// api moves to another usage bucket, worker only moves within its bucket
+	m.k8sClient = newTestClient(
+		testPod{namespace: "prod", name: "api", usage: "65Mi", request: "100Mi", limit: "200Mi"},
+		testPod{namespace: "prod", name: "worker", usage: "35Mi", request: "100Mi", limit: "200Mi"},
+	)
+	analysis, err = m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	output := captureStdout(t, func() { analysis.Report.PrintDetailedReport(cfg) })
+	if !strings.Contains(output, "prod/api") || strings.Contains(output, "prod/worker") {
+		t.Errorf("expected only the changed pod to be listed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Total Pods: 2") {
+		t.Errorf("expected the summary to still count every pod, got:\n%s", output)
+	}
+
+	analysis, err = m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	output = captureStdout(t, func() { analysis.Report.PrintDetailedReport(cfg) })
+	if !strings.Contains(output, "No pod changes since the previous cycle.") {
+		t.Errorf("expected no pods to be listed for an unchanged cycle, got:\n%s", output)
+	}
+}
+
+func TestDetectChangedPods_KeepsClustersApart(t *testing.T) {
+	snapshots := map[string]podSnapshot{"prod-eu": {status: "ok", bucket: 3}, "prod-us": {status: "critical", bucket: 9}}
+	snapshotOf := func(pod *k8s.PodMemoryInfo) podSnapshot { return snapshots[pod.Cluster] }
+	pods := []k8s.PodMemoryInfo{
+		{Cluster: "prod-eu", Namespace: "prod", PodName: "api"},
+		{Cluster: "prod-us", Namespace: "prod", PodName: "api"},
+	}
+
+	_, previous := detectChangedPods(nil, pods, snapshotOf)
+	changed, _ := detectChangedPods(previous, pods, snapshotOf)
+
+	if len(changed) != 0 {
+		t.Errorf("expected unchanged same-named pods of different clusters to stay unchanged, got %v", changed)
+	}
+	if filtered := filterChangedPods(pods, map[podID]bool{{Cluster: "prod-us", Namespace: "prod", Name: "api"}: true}); len(filtered) != 1 ||
+		filtered[0].Cluster != "prod-us" {
+		t.Errorf("expected only the prod-us pod to be kept, got %+v", filtered)
+	}
+}
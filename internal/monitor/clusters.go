@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// clusterCollection holds what one cluster returned in a cycle
+type clusterCollection struct {
+	pods    []k8s.PodMemoryInfo
+	summary *k8s.MemorySummary
+	err     error
+}
+
+// collectClusters collects from every cluster in parallel and merges the results in context order
+// A failure in any cluster fails the cycle, so a fleet report never silently misses a cluster
+func (m *MemoryMonitor) collectClusters(ctx context.Context) ([]k8s.PodMemoryInfo, *k8s.MemorySummary, error) {
+	clients := m.clients()
+	if len(clients) == 1 {
+		return m.collectFrom(ctx, clients[0])
+	}
+
+	results := make([]clusterCollection, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *k8s.Client) {
+			defer wg.Done()
+			pods, summary, err := m.collectFrom(ctx, client)
+			if err != nil {
+				err = fmt.Errorf("cluster %s: %w", client.Cluster(), err)
+			}
+			results[i] = clusterCollection{pods: pods, summary: summary, err: err}
+		}(i, client)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	pods := results[0].pods
+	summary := results[0].summary
+	for _, r := range results[1:] {
+		pods = append(pods, r.pods...)
+		summary.Merge(r.summary)
+	}
+	return pods, summary, nil
+}
+
+// clusterNames lists the contexts being merged, nil unless several clusters are monitored
+func (m *MemoryMonitor) clusterNames() []string {
+	clients := m.clients()
+	if len(clients) < 2 {
+		return nil
+	}
+	names := make([]string, 0, len(clients))
+	for _, client := range clients {
+		names = append(names, client.Cluster())
+	}
+	return names
+}
+
+// collectFrom collects the pods selected by the configuration from one cluster
+func (m *MemoryMonitor) collectFrom(ctx context.Context, client *k8s.Client) (
+	[]k8s.PodMemoryInfo, *k8s.MemorySummary, error) {
+	switch {
+	case m.config.RefreshMetricsOnly && m.config.PodName == "" && client.PodCacheFresh():
+		// Pod specs from a recent cycle are reused, only metrics are listed again
+		return client.RefreshMetrics(ctx)
+	case m.config.PodName != "":
+		// Monitor a single pod
+		return client.GetSinglePodMemoryInfo(ctx, m.config.Namespace, m.config.PodName)
+	case m.config.Namespace != "":
+		// Monitor specific namespace
+		return client.GetPodsMemoryInfo(ctx, m.config.Namespace, false)
+	case m.config.AllNamespaces:
+		// Monitor all namespaces
+		return client.GetPodsMemoryInfo(ctx, "", true)
+	default:
+		// Default to all namespaces
+		return client.GetAllPodsMemoryInfo(ctx)
+	}
+}
+
+// collectNodeAllocatable gathers node allocatable memory from every cluster, keyed by nodeKey
+// The node report still works without allocatable values, so failures are only logged
+func (m *MemoryMonitor) collectNodeAllocatable(ctx context.Context) map[string]resource.Quantity {
+	var allocatable map[string]resource.Quantity
+	for _, client := range m.clients() {
+		nodes, err := client.GetNodeAllocatableMemory(ctx)
+		if err != nil {
+			slog.Warn("Failed to get node allocatable memory", "cluster", client.Cluster(), "error", err)
+			continue
+		}
+		if allocatable == nil {
+			allocatable = make(map[string]resource.Quantity, len(nodes))
+		}
+		for node, memory := range nodes {
+			allocatable[nodeKey(client.Cluster(), node)] = memory
+		}
+	}
+	return allocatable
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// newTestClusterMonitor builds a multi-cluster monitor with one fake client per cluster name
+func newTestClusterMonitor(clusters map[string][]testPod, order ...string) *MemoryMonitor {
+	m := newTestMonitor(testMonitorConfig())
+	m.clusters = nil
+	for _, name := range order {
+		client := newTestClient(clusters[name]...)
+		client.SetCluster(name)
+		m.clusters = append(m.clusters, client)
+	}
+	m.k8sClient = m.clusters[0]
+	return m
+}
+
+func TestCollectMemoryInfo_MergesClusters(t *testing.T) {
+	m := newTestClusterMonitor(map[string][]testPod{
+		"prod-eu": {{namespace: "prod", name: "api", usage: "100Mi", request: "200Mi", limit: "400Mi"}},
+		"prod-us": {
+			{namespace: "prod", name: "api", usage: "300Mi", request: "200Mi", limit: "400Mi"},
+			{namespace: "prod", name: "worker", usage: "50Mi", request: "100Mi", limit: "200Mi"},
+		},
+	}, "prod-eu", "prod-us")
+
+	report, err := m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+
+	if report.Summary.TotalPods != 3 || report.Summary.NamespaceCount != 2 {
+		t.Errorf("expected 3 pods over 2 namespaces, got %d pods over %d", report.Summary.TotalPods, report.Summary.NamespaceCount)
+	}
+	if !report.MultiCluster() || report.Clusters[0] != "prod-eu" || report.Clusters[1] != "prod-us" {
+		t.Errorf("expected the report to list both clusters, got %v", report.Clusters)
+	}
+	if got := report.Summary.TotalMemoryUsage.Value(); got != 450*mi {
+		t.Errorf("expected 450Mi total usage, got %d", got)
+	}
+
+	var got []string
+	for _, pod := range report.Pods {
+		got = append(got, pod.Cluster+"/"+pod.Namespace+"/"+pod.PodName)
+	}
+	expected := []string{"prod-eu/prod/api", "prod-us/prod/api", "prod-us/prod/worker"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected pods %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected pods %v, got %v", expected, got)
+			break
+		}
+	}
+}
+
+func TestCollectMemoryInfo_NumbersCycles(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(), testPod{namespace: "prod", name: "api", usage: "100Mi"})
+
+	for expected := 1; expected <= 3; expected++ {
+		report, err := m.CollectMemoryInfo(context.Background())
+		if err != nil {
+			t.Fatalf("CollectMemoryInfo() failed: %v", err)
+		}
+		if report.Cycle != expected {
+			t.Errorf("expected cycle %d, got %d", expected, report.Cycle)
+		}
+	}
+}
+
+func TestCollectMemoryInfo_SingleClusterLeavesClusterEmpty(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(), testPod{namespace: "prod", name: "api", usage: "100Mi"})
+
+	report, err := m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	if report.MultiCluster() {
+		t.Errorf("expected a single-cluster report, got clusters %v", report.Clusters)
+	}
+	if len(report.Pods) != 1 || report.Pods[0].Cluster != "" {
+		t.Errorf("expected one pod without a cluster, got %+v", report.Pods)
+	}
+}
+
+func TestAnalyzeReport_KeepsSameNamedPodsOfClustersApart(t *testing.T) {
+	pods := []k8s.PodMemoryInfo{}
+	for _, cluster := range []string{"prod-eu", "prod-us"} {
+		for _, name := range []string{"api-1", "api-2"} {
+			pods = append(pods, k8s.PodMemoryInfo{
+				Cluster: cluster, Namespace: "prod", PodName: name, Workload: "deploy/api", NodeName: "node-a",
+				CurrentUsage: qty(500 * mi), MemoryRequest: qty(200 * mi), MemoryLimit: qty(1024 * mi),
+			})
+		}
+	}
+	report := &MemoryReport{
+		Pods:     pods,
+		Clusters: []string{"prod-eu", "prod-us"},
+		NodeAllocatable: map[string]resource.Quantity{
+			"prod-eu/node-a": *qty(300 * mi),
+			"prod-us/node-a": *qty(4096 * mi),
+		},
+	}
+
+	analysis := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0, DedupeProblems: true})
+
+	// Replicas are collapsed within each cluster only
+	var collapsed []Problem
+	for _, p := range analysis.Problems {
+		if p.Kind == ProblemKindRequestUsage && p.Count == 2 {
+			collapsed = append(collapsed, p)
+		}
+	}
+	if len(collapsed) != 2 || collapsed[0].Cluster == collapsed[1].Cluster {
+		t.Errorf("expected one collapsed problem per cluster, got %+v", collapsed)
+	}
+	if criticalPodKey(Problem{Cluster: "prod-eu", Namespace: "prod", PodName: "api-1"}) ==
+		criticalPodKey(Problem{Cluster: "prod-us", Namespace: "prod", PodName: "api-1"}) {
+		t.Error("expected notification keys to differ between clusters")
+	}
+
+	// Same-named nodes keep their own allocatable memory, so only the small one is overcommitted
+	nodes := analysis.Report.NodeUsages()
+	if len(nodes) != 2 || nodes[0].Name() != "prod-eu/node-a" || nodes[1].Name() != "prod-us/node-a" {
+		t.Fatalf("expected node-a once per cluster, got %+v", nodes)
+	}
+	overcommitted := analysis.Report.RequestOvercommittedNodes()
+	if len(overcommitted) != 1 || overcommitted[0].Cluster != "prod-eu" {
+		t.Errorf("expected only prod-eu/node-a overcommitted, got %+v", overcommitted)
+	}
+}
+
+func TestPodBaseCells_ShowsClusterWhenMultiCluster(t *testing.T) {
+	pod := &k8s.PodMemoryInfo{Cluster: "prod-eu", Namespace: "prod", PodName: "api"}
+	if got := podBaseCells(pod, true)[0]; got != "prod-eu/prod/api" {
+		t.Errorf("expected the cluster in multi-cluster rows, got %q", got)
+	}
+	if got := podBaseCells(pod, false)[0]; got != "prod/api" {
+		t.Errorf("expected no cluster in single-cluster rows, got %q", got)
+	}
+
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{*pod}, Clusters: []string{"prod-eu", "prod-us"}}
+	out := report.renderWideTable(report.Pods, &config.Config{MemoryWarningPercent: 80.0})
+	if !strings.HasPrefix(strings.TrimSpace(out), "CLUSTER") || !strings.Contains(out, "prod-eu") {
+		t.Errorf("expected a CLUSTER column in the wide table:\n%s", out)
+	}
+}
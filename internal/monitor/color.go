@@ -0,0 +1,29 @@
+package monitor
+
+// ANSI escape sequences used to colorize table output
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiGreen  = "\033[32m"
+)
+
+// statusColors maps memory statuses to the ANSI color used for the pod line
+var statusColors = map[string]string{
+	"critical":   ansiRed,
+	"not_ready":  ansiRed,
+	"warning":    ansiYellow,
+	"no_config":  ansiYellow,
+	"no_request": ansiYellow,
+	"no_limit":   ansiYellow,
+	"ok":         ansiGreen,
+}
+
+// colorize wraps text in the color matching status; unknown statuses are left unchanged
+func colorize(text, status string) string {
+	color, ok := statusColors[status]
+	if !ok {
+		return text
+	}
+	return color + text + ansiReset
+}
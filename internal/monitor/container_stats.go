@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// containerStatsTitle introduces the container usage statistics under a pod
+const containerStatsTitle = "      📊 Container stats:"
+
+// containerUsageStats summarizes the usage of a pod's containers that have metrics
+type containerUsageStats struct {
+	Containers int // Containers with metrics
+	Max        resource.Quantity
+	MaxName    string
+	Mean       resource.Quantity
+	P95        resource.Quantity
+}
+
+// podContainerStats computes the max, mean and p95 usage of the pod's containers
+// It returns false when no container has metrics
+func podContainerStats(pod *k8s.PodMemoryInfo) (containerUsageStats, bool) {
+	var usages []int64
+	var stats containerUsageStats
+	var total int64
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		if c.CurrentUsage == nil {
+			continue
+		}
+		value := c.CurrentUsage.Value()
+		if len(usages) == 0 || value > stats.Max.Value() {
+			stats.Max = *resource.NewQuantity(value, resource.BinarySI)
+			stats.MaxName = c.ContainerName
+		}
+		usages = append(usages, value)
+		total += value
+	}
+	if len(usages) == 0 {
+		return stats, false
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i] < usages[j] })
+	stats.Containers = len(usages)
+	stats.Mean = *resource.NewQuantity(total/int64(len(usages)), resource.BinarySI)
+	stats.P95 = *resource.NewQuantity(percentile(usages, 95), resource.BinarySI)
+	return stats, true
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// formatContainerStats returns the container usage statistics line of a pod, empty unless --container-stats is set
+func formatContainerStats(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
+	if !cfg.ContainerStats {
+		return ""
+	}
+	stats, ok := podContainerStats(pod)
+	if !ok {
+		return containerStatsTitle + " no container metrics"
+	}
+	return fmt.Sprintf("%s max %s (%s) | mean %s | p95 %s | %d containers", containerStatsTitle,
+		k8s.FormatMemory(&stats.Max), stats.MaxName, k8s.FormatMemory(&stats.Mean),
+		k8s.FormatMemory(&stats.P95), stats.Containers)
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestPodContainerStats(t *testing.T) {
+	pod := &k8s.PodMemoryInfo{Containers: []k8s.ContainerMemoryInfo{
+		{ContainerName: "app", CurrentUsage: qty(100 * mi)},
+		{ContainerName: "proxy", CurrentUsage: qty(20 * mi)},
+		{ContainerName: "cache", CurrentUsage: qty(600 * mi)},
+		{ContainerName: "init"},
+		{ContainerName: "logger", CurrentUsage: qty(40 * mi)},
+	}}
+
+	stats, ok := podContainerStats(pod)
+	if !ok {
+		t.Fatal("expected stats for a pod with container metrics")
+	}
+	if stats.Containers != 4 || stats.MaxName != "cache" || stats.Max.Value() != 600*mi {
+		t.Errorf("unexpected max: %d containers, %s at %d", stats.Containers, stats.MaxName, stats.Max.Value())
+	}
+	if stats.Mean.Value() != 190*mi {
+		t.Errorf("expected mean 190Mi, got %d", stats.Mean.Value())
+	}
+	if stats.P95.Value() != 600*mi {
+		t.Errorf("expected p95 600Mi, got %d", stats.P95.Value())
+	}
+
+	if _, ok := podContainerStats(&k8s.PodMemoryInfo{Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app"}}}); ok {
+		t.Error("expected no stats without container metrics")
+	}
+	if _, ok := podContainerStats(&k8s.PodMemoryInfo{}); ok {
+		t.Error("expected no stats for a pod without containers")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	tests := []struct {
+		p    float64
+		want int64
+	}{{95, 19}, {50, 10}, {100, 20}, {0, 1}}
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestFormatContainerStats(t *testing.T) {
+	pod := &k8s.PodMemoryInfo{Containers: []k8s.ContainerMemoryInfo{
+		{ContainerName: "app", CurrentUsage: qty(300 * mi)},
+		{ContainerName: "proxy", CurrentUsage: qty(100 * mi)},
+	}}
+
+	if got := formatContainerStats(pod, &config.Config{}); got != "" {
+		t.Errorf("expected no stats without --container-stats, got %q", got)
+	}
+
+	cfg := &config.Config{ContainerStats: true}
+	got := formatContainerStats(pod, cfg)
+	want := "📊 Container stats: max 300.0 MB (app) | mean 200.0 MB | p95 300.0 MB | 2 containers"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if out := formatPodInfo(pod, cfg, false); !strings.Contains(out, want) {
+		t.Errorf("expected stats in pod info, got:\n%s", out)
+	}
+
+	if got := formatContainerStats(&k8s.PodMemoryInfo{}, cfg); !strings.Contains(got, "no container metrics") {
+		t.Errorf("expected a note for pods without container metrics, got %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"log/slog"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// EventThresholdCrossing is the event logged with --watch-threshold-crossings
+const EventThresholdCrossing = "threshold_crossing"
+
+// Memory statuses tracked for threshold crossings
+const (
+	statusOK       = "ok"
+	statusWarning  = "warning"
+	statusCritical = "critical"
+)
+
+// statusRank orders the tracked statuses; other statuses such as no_data are not thresholds
+var statusRank = map[string]int{statusOK: 0, statusWarning: 1, statusCritical: 2}
+
+// StatusTransition records a pod whose memory status changed since the previous cycle
+type StatusTransition struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	PodName   string `json:"pod_name"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+// Recovery reports whether the pod moved to a less severe status
+func (t StatusTransition) Recovery() bool {
+	return statusRank[t.To] < statusRank[t.From]
+}
+
+// detectCrossings compares each pod's status with the previous cycle and returns the changes
+// Pods missing from previous start out as ok, so a new pod that is already warning is reported
+// Statuses other than ok, warning and critical keep the last tracked status
+func detectCrossings(previous map[podID]string, pods []k8s.PodMemoryInfo, statusOf func(*k8s.PodMemoryInfo) string) (
+	[]StatusTransition, map[podID]string) {
+	current := make(map[podID]string, len(pods))
+	var transitions []StatusTransition
+	for i := range pods {
+		pod := &pods[i]
+		id := podIDOf(pod)
+		last, known := previous[id]
+		if !known {
+			last = statusOK
+		}
+
+		status := statusOf(pod)
+		if _, tracked := statusRank[status]; !tracked {
+			current[id] = last
+			continue
+		}
+		current[id] = status
+		if status != last {
+			transitions = append(transitions, StatusTransition{Cluster: pod.Cluster, Namespace: pod.Namespace,
+				PodName: pod.PodName, From: last, To: status})
+		}
+	}
+	return transitions, current
+}
+
+// trackThresholdCrossings records the analysis' status transitions and logs each one
+// The first cycle only records the baseline; pods that vanish are evicted from the state,
+// except after a partial collection where their namespace may simply not have been reached
+func (m *MemoryMonitor) trackThresholdCrossings(analysis *AnalysisResult) {
+	if !m.config.WatchCrossings {
+		return
+	}
+
+	transitions, current := detectCrossings(m.podStatuses, analysis.Report.Pods, func(pod *k8s.PodMemoryInfo) string {
+		return getMemoryStatus(pod, m.config).Status
+	})
+	if m.podStatuses == nil {
+		m.podStatuses = current
+		return
+	}
+	if analysis.Report.Summary.Partial {
+		for id, status := range current {
+			m.podStatuses[id] = status
+		}
+	} else {
+		m.podStatuses = current
+	}
+
+	analysis.Transitions = transitions
+	for _, t := range transitions {
+		log := slog.Warn
+		if t.Recovery() {
+			log = slog.Info
+		}
+		id := podID{Cluster: t.Cluster, Namespace: t.Namespace, Name: t.PodName}
+		attrs := append([]any{"event", EventThresholdCrossing}, id.logAttrs()...)
+		log("Memory status changed", append(attrs, "from", t.From, "to", t.To)...)
+	}
+}
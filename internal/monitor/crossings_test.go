@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestDetectCrossings(t *testing.T) {
+	statuses := map[string]string{"api": "warning", "worker": "critical", "batch": "no_data", "new": "warning", "calm": "ok"}
+	statusOf := func(pod *k8s.PodMemoryInfo) string { return statuses[pod.PodName] }
+	previous := map[podID]string{
+		{Namespace: "prod", Name: "api"}:    "ok",
+		{Namespace: "prod", Name: "worker"}: "critical",
+		{Namespace: "prod", Name: "batch"}:  "warning",
+		{Namespace: "prod", Name: "calm"}:   "warning",
+		{Namespace: "prod", Name: "gone"}:   "critical",
+	}
+	var pods []k8s.PodMemoryInfo
+	for _, name := range []string{"api", "worker", "batch", "new", "calm"} {
+		pods = append(pods, k8s.PodMemoryInfo{Namespace: "prod", PodName: name})
+	}
+
+	transitions, current := detectCrossings(previous, pods, statusOf)
+
+	expected := []StatusTransition{
+		{Namespace: "prod", PodName: "api", From: "ok", To: "warning"},
+		{Namespace: "prod", PodName: "new", From: "ok", To: "warning"},
+		{Namespace: "prod", PodName: "calm", From: "warning", To: "ok"},
+	}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %d transitions, got %+v", len(expected), transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("transition %d: expected %+v, got %+v", i, expected[i], transitions[i])
+		}
+	}
+	if !transitions[2].Recovery() || transitions[0].Recovery() {
+		t.Errorf("expected only the warning to ok transition to be a recovery")
+	}
+	if current[podID{Namespace: "prod", Name: "batch"}] != "warning" {
+		t.Errorf("expected a pod without data to keep its last status, got %q", current[podID{Namespace: "prod", Name: "batch"}])
+	}
+	if _, ok := current[podID{Namespace: "prod", Name: "gone"}]; ok {
+		t.Error("expected vanished pods to be evicted")
+	}
+}
+
+func TestDetectCrossings_KeepsClustersApart(t *testing.T) {
+	statuses := map[string]string{"prod-eu": "ok", "prod-us": "critical"}
+	statusOf := func(pod *k8s.PodMemoryInfo) string { return statuses[pod.Cluster] }
+	pods := []k8s.PodMemoryInfo{
+		{Cluster: "prod-eu", Namespace: "prod", PodName: "api"},
+		{Cluster: "prod-us", Namespace: "prod", PodName: "api"},
+	}
+
+	_, previous := detectCrossings(nil, pods, statusOf)
+	transitions, _ := detectCrossings(previous, pods, statusOf)
+
+	if len(transitions) != 0 {
+		t.Errorf("expected same-named pods of different clusters to keep their own status, got %+v", transitions)
+	}
+	if previous[podID{Cluster: "prod-us", Namespace: "prod", Name: "api"}] != "critical" {
+		t.Errorf("expected prod-us/prod/api to be tracked as critical, got %v", previous)
+	}
+}
+
+func TestAnalyzeMemoryUsage_ReportsThresholdCrossings(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.WatchCrossings = true
+	m := newTestMonitor(cfg, testPod{namespace: "prod", name: "api", usage: "50Mi", request: "100Mi", limit: "200Mi"})
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	if len(analysis.Transitions) != 0 {
+		t.Errorf("expected the first cycle to only record the baseline, got %+v", analysis.Transitions)
+	}
+
+	m.k8sClient = newTestClient(testPod{namespace: "prod", name: "api", usage: "90Mi", request: "100Mi", limit: "200Mi"})
+	analysis, err = m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	if len(analysis.Transitions) != 1 || analysis.Transitions[0].From != "ok" || analysis.Transitions[0].To != "warning" {
+		t.Errorf("expected an ok to warning transition, got %+v", analysis.Transitions)
+	}
+
+	analysis, err = m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+	if len(analysis.Transitions) != 0 {
+		t.Errorf("expected no transition while the status is unchanged, got %+v", analysis.Transitions)
+	}
+}
+
+func TestAnalyzeMemoryReport_InjectedReportsWithoutCluster(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.WatchCrossings = true
+	m := &MemoryMonitor{config: cfg, session: newSessionStats(time.Now())}
+
+	report := func(usage int64) *MemoryReport {
+		return &MemoryReport{
+			Summary: k8s.MemorySummary{Timestamp: time.Now(), TotalPods: 1, RunningPods: 1},
+			Pods: []k8s.PodMemoryInfo{{
+				Namespace: "prod", PodName: "api", Phase: "Running", Ready: true,
+				CurrentUsage: qty(usage * mi), MemoryRequest: qty(100 * mi), MemoryLimit: qty(200 * mi),
+			}},
+		}
+	}
+
+	if analysis := m.AnalyzeMemoryReport(report(50)); len(analysis.WarningPods) != 0 || len(analysis.Transitions) != 0 {
+		t.Errorf("expected a quiet baseline cycle, got warnings %d and transitions %+v", len(analysis.WarningPods), analysis.Transitions)
+	}
+	analysis := m.AnalyzeMemoryReport(report(90))
+	if len(analysis.WarningPods) != 1 {
+		t.Errorf("expected the pod at 90%% of its request to warn, got %d warning pods", len(analysis.WarningPods))
+	}
+	if len(analysis.Transitions) != 1 || analysis.Transitions[0].To != "warning" {
+		t.Errorf("expected an ok to warning transition, got %+v", analysis.Transitions)
+	}
+	if m.Session().Cycles != 2 {
+		t.Errorf("expected both injected reports in the session, got %d cycles", m.Session().Cycles)
+	}
+}
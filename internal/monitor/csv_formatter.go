@@ -4,8 +4,8 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/eduardoferro/k8s-memory-watch/internal/config"
 	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
@@ -28,25 +28,59 @@ func (f *CSVFormatter) FormatReport(report *MemoryReport, cfg *config.Config, sh
 	defer f.writer.Flush()
 
 	if showHeader {
-		f.writeHeader(cfg)
+		f.writeHeader(cfg, report.MultiCluster())
 	}
 
 	f.writeData(report, cfg)
+
+	if cfg.CSVTotals {
+		f.writeTotals(report, cfg)
+	}
+}
+
+// csvTotalsStatus fills the memory_status column of the totals row
+const csvTotalsStatus = "total"
+
+// writeTotals writes a closing row with the summary's usage, request and limit bytes
+// Identity and percentage fields are left empty; the row has as many columns as the header
+func (f *CSVFormatter) writeTotals(report *MemoryReport, cfg *config.Config) {
+	if err := f.writer.Write(buildCSVTotalsRecord(report, cfg)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing CSV totals: %v\n", err)
+	}
+}
+
+// buildCSVTotalsRecord lays the summary totals out in the columns of the header
+func buildCSVTotalsRecord(report *MemoryReport, cfg *config.Config) []string {
+	header := (&CSVFormatter{}).buildHeader(cfg, report.MultiCluster())
+	summary := &report.Summary
+	values := map[string]string{
+		"timestamp":     formatTimestamp(summary.Timestamp, cfg),
+		"memory_status": csvTotalsStatus,
+		"usage_bytes":   formatBytesForCSV(&summary.TotalMemoryUsage),
+		"request_bytes": formatBytesForCSV(&summary.TotalMemoryRequest),
+		"limit_bytes":   formatBytesForCSV(&summary.TotalMemoryLimit),
+		"cycle":         strconv.Itoa(report.Cycle),
+	}
+	record := make([]string, len(header))
+	for i, column := range header {
+		record[i] = values[column]
+	}
+	return record
 }
 
 // writeHeader writes the CSV header row
-func (f *CSVFormatter) writeHeader(cfg *config.Config) {
-	header := f.buildHeader(cfg)
+func (f *CSVFormatter) writeHeader(cfg *config.Config, multiCluster bool) {
+	header := f.buildHeader(cfg, multiCluster)
 	if err := f.writer.Write(header); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing CSV header: %v\n", err)
 	}
 }
 
 // buildHeader creates the CSV header based on configuration
-func (f *CSVFormatter) buildHeader(cfg *config.Config) []string {
-	header := []string{
-		"timestamp",
-		"memory_status",
+// Multi-cluster reports get a cluster column right after memory_status
+func (f *CSVFormatter) buildHeader(cfg *config.Config, multiCluster bool) []string {
+	header := csvLeadingColumnNames(multiCluster)
+	header = append(header,
 		"namespace",
 		"pod_name",
 		"phase",
@@ -57,6 +91,13 @@ func (f *CSVFormatter) buildHeader(cfg *config.Config) []string {
 		"usage_percent",
 		"limit_usage_percent",
 		"container_name",
+		"headroom_bytes",
+	)
+	if cfg.ShowImages {
+		header = append(header, "image")
+	}
+	if cfg.ShowRequestRatio {
+		header = append(header, "request_limit_percent")
 	}
 
 	// Add label columns
@@ -66,12 +107,64 @@ func (f *CSVFormatter) buildHeader(cfg *config.Config) []string {
 
 	// Add annotation columns
 	for _, annotation := range cfg.Annotations {
-		header = append(header, "annotation_"+strings.ReplaceAll(annotation, ".", "_"))
+		header = append(header, annotationColumn(annotation))
 	}
 
+	// Add environment variable columns
+	for _, name := range cfg.EnvVars {
+		header = append(header, "env_"+name)
+	}
+
+	if cfg.CSVCycle {
+		header = append(header, "cycle")
+	}
+
+	return uniqueColumnNames(header)
+}
+
+// uniqueColumnNames suffixes repeated column names with _2, _3, ... so strict CSV parsers accept the header
+// Repeats come from duplicated options or from names that collide once dots are replaced
+func uniqueColumnNames(header []string) []string {
+	seen := make(map[string]bool, len(header))
+	for _, column := range header {
+		seen[column] = true
+	}
+	counts := make(map[string]int, len(header))
+	for i, column := range header {
+		counts[column]++
+		if counts[column] == 1 {
+			continue
+		}
+		for n := counts[column]; ; n++ {
+			candidate := fmt.Sprintf("%s_%d", column, n)
+			if !seen[candidate] {
+				header[i] = candidate
+				seen[candidate] = true
+				counts[column] = n
+				break
+			}
+		}
+	}
 	return header
 }
 
+// csvLeadingColumnNames returns the header of the columns written by csvLeadingColumns
+func csvLeadingColumnNames(multiCluster bool) []string {
+	if multiCluster {
+		return []string{"timestamp", "memory_status", "cluster"}
+	}
+	return []string{"timestamp", "memory_status"}
+}
+
+// csvLeadingColumns returns the timestamp, status and, in multi-cluster reports, cluster columns
+// The cluster column is left out for a single cluster so existing CSV consumers keep their column positions
+func csvLeadingColumns(timestamp, status, cluster string, multiCluster bool) []string {
+	if multiCluster {
+		return []string{timestamp, status, cluster}
+	}
+	return []string{timestamp, status}
+}
+
 // writeData writes the pod data rows
 func (f *CSVFormatter) writeData(report *MemoryReport, cfg *config.Config) {
 	for i := range report.Pods {
@@ -79,18 +172,19 @@ func (f *CSVFormatter) writeData(report *MemoryReport, cfg *config.Config) {
 		pod.CalculateUsagePercent()
 
 		if len(pod.Containers) > 0 {
-			f.writeContainerRows(pod, cfg, report.Summary.Timestamp)
+			f.writeContainerRows(pod, cfg, report)
 		} else {
-			f.writePodRow(pod, cfg, report.Summary.Timestamp)
+			f.writePodRow(pod, cfg, report)
 		}
 	}
 }
 
 // writeContainerRows writes one row per container
-func (f *CSVFormatter) writeContainerRows(pod *k8s.PodMemoryInfo, cfg *config.Config, timestamp time.Time) {
+func (f *CSVFormatter) writeContainerRows(pod *k8s.PodMemoryInfo, cfg *config.Config, report *MemoryReport) {
 	for _, c := range pod.Containers {
 		c.CalculateUsagePercent()
-		record := buildCSVRecord(pod, &c, cfg, timestamp)
+		record := buildCSVRecord(pod, &c, cfg, report.Summary.Timestamp, report.MultiCluster())
+		record = appendCycleColumn(record, report, cfg)
 		if err := f.writer.Write(record); err != nil {
 			fmt.Fprintf(os.Stderr, "Error writing CSV record: %v\n", err)
 		}
@@ -98,9 +192,40 @@ func (f *CSVFormatter) writeContainerRows(pod *k8s.PodMemoryInfo, cfg *config.Co
 }
 
 // writePodRow writes a single row for the pod
-func (f *CSVFormatter) writePodRow(pod *k8s.PodMemoryInfo, cfg *config.Config, timestamp time.Time) {
-	record := buildCSVRecordForPod(pod, cfg, timestamp)
+func (f *CSVFormatter) writePodRow(pod *k8s.PodMemoryInfo, cfg *config.Config, report *MemoryReport) {
+	record := buildCSVRecordForPod(pod, cfg, report.Summary.Timestamp, report.MultiCluster())
+	record = appendCycleColumn(record, report, cfg)
 	if err := f.writer.Write(record); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing CSV record: %v\n", err)
 	}
 }
+
+// appendCycleColumn ends a row with the report's cycle number when the cycle column is enabled
+func appendCycleColumn(record []string, report *MemoryReport, cfg *config.Config) []string {
+	if !cfg.CSVCycle {
+		return record
+	}
+	return append(record, strconv.Itoa(report.Cycle))
+}
+
+// AppendCSV appends the report rows to the CSV file at path, creating it if needed
+// The header is only written when the file is empty, so one file grows across restarts
+func (r *MemoryReport) AppendCSV(cfg *config.Config, path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat CSV file: %w", err)
+	}
+
+	formatter := &CSVFormatter{writer: csv.NewWriter(file)}
+	formatter.FormatReport(r, cfg, info.Size() == 0)
+	if err := formatter.writer.Error(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestAppendCSV_WritesHeaderOnlyOnce(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputFormatCSV}
+	report := MemoryReport{
+		Summary: k8s.MemorySummary{Timestamp: time.Now()},
+		Pods: []k8s.PodMemoryInfo{
+			{Namespace: "ns", PodName: "p1", Phase: "Running", Ready: true},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "samples.csv")
+
+	// The second call simulates a restarted process appending to the same file
+	for i := 0; i < 2; i++ {
+		if err := report.AppendCSV(cfg, path); err != nil {
+			t.Fatalf("AppendCSV() failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read CSV file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %d lines:\n%s", len(lines), data)
+	}
+	if !strings.HasPrefix(lines[0], "timestamp,") {
+		t.Errorf("expected header first, got %q", lines[0])
+	}
+	if strings.HasPrefix(lines[2], "timestamp,") {
+		t.Errorf("header should not be repeated, got %q", lines[2])
+	}
+}
+
+func TestAppendCSV_ExistingFileSkipsHeader(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputFormatCSV}
+	report := MemoryReport{
+		Summary: k8s.MemorySummary{Timestamp: time.Now()},
+		Pods:    []k8s.PodMemoryInfo{{Namespace: "ns", PodName: "p1"}},
+	}
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	if err := os.WriteFile(path, []byte("timestamp,previous\n"), 0o644); err != nil {
+		t.Fatalf("failed to seed CSV file: %v", err)
+	}
+
+	if err := report.AppendCSV(cfg, path); err != nil {
+		t.Fatalf("AppendCSV() failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if got := strings.Count(string(data), "timestamp,"); got != 1 {
+		t.Errorf("expected only the existing header, found %d:\n%s", got, data)
+	}
+}
+
+func TestBuildCSVTotalsRecord_MatchesHeaderColumns(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputFormatCSV, CSVTotals: true, ShowImages: true, Labels: []string{"app"}}
+	report := &MemoryReport{
+		Summary: k8s.MemorySummary{
+			Timestamp:          time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			TotalMemoryUsage:   *qty(300 * mi),
+			TotalMemoryRequest: *qty(512 * mi),
+			TotalMemoryLimit:   *qty(1024 * mi),
+		},
+		Pods: []k8s.PodMemoryInfo{{Namespace: "ns", PodName: "p1", Phase: "Running", Ready: true, CurrentUsage: qty(300 * mi)}},
+	}
+
+	header := (&CSVFormatter{}).buildHeader(cfg, false)
+	record := buildCSVTotalsRecord(report, cfg)
+	if len(record) != len(header) {
+		t.Fatalf("totals row has %d columns, header has %d", len(record), len(header))
+	}
+	want := map[string]string{
+		"timestamp":     "2024-05-01T12:00:00Z",
+		"memory_status": "total",
+		"namespace":     "",
+		"pod_name":      "",
+		"usage_bytes":   "314572800",
+		"request_bytes": "536870912",
+		"limit_bytes":   "1073741824",
+		"image":         "",
+		"label_app":     "",
+	}
+	for i, column := range header {
+		if expected, ok := want[column]; ok && record[i] != expected {
+			t.Errorf("%s = %q, want %q", column, record[i], expected)
+		}
+	}
+}
+
+func TestFormatReport_TotalsRowLast(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputFormatCSV, CSVTotals: true}
+	report := MemoryReport{
+		Summary: k8s.MemorySummary{Timestamp: time.Now(), TotalMemoryUsage: *qty(mi)},
+		Pods: []k8s.PodMemoryInfo{
+			{Namespace: "ns", PodName: "p1", Phase: "Running", Ready: true},
+			{Namespace: "ns", PodName: "p2", Phase: "Running", Ready: true},
+		},
+	}
+	var buf bytes.Buffer
+	(&CSVFormatter{writer: csv.NewWriter(&buf)}).FormatReport(&report, cfg, true)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, two rows and totals, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if fields := strings.Split(lines[3], ","); fields[1] != "total" || len(fields) != len(strings.Split(lines[0], ",")) {
+		t.Errorf("expected a totals row with the header's column count last, got %q", lines[3])
+	}
+}
+
+func TestAppendCSV_CycleColumn(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputFormatCSV, CSVCycle: true}
+	report := MemoryReport{
+		Summary: k8s.MemorySummary{Timestamp: time.Now()},
+		Pods: []k8s.PodMemoryInfo{
+			{Namespace: "ns", PodName: "p1", Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app"}}},
+			{Namespace: "ns", PodName: "p2"},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "samples.csv")
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		report.Cycle = cycle
+		if err := report.AppendCSV(cfg, path); err != nil {
+			t.Fatalf("AppendCSV() failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read CSV file: %v", err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 5 || rows[0][len(rows[0])-1] != "cycle" {
+		t.Fatalf("expected a header ending in cycle and four rows, got %v", rows)
+	}
+	for i, expected := range []string{"1", "1", "2", "2"} {
+		row := rows[i+1]
+		if len(row) != len(rows[0]) || row[len(row)-1] != expected {
+			t.Errorf("row %d: expected cycle %s in the last of %d columns, got %v", i+1, expected, len(rows[0]), row)
+		}
+	}
+}
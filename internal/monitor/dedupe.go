@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// problemGroup identifies problems that only differ by the replica they were found on
+type problemGroup struct {
+	Severity  string
+	Kind      string
+	Cluster   string
+	Namespace string
+	Workload  string
+	Container string
+	Detail    string
+}
+
+// problemDetail returns the problem message without its leading pod and container identity
+func problemDetail(p *Problem) string {
+	prefix := fmt.Sprintf("Pod %s/%s ", p.Namespace, p.PodName)
+	if p.Container != "" {
+		prefix += fmt.Sprintf("container %s ", p.Container)
+	}
+	return strings.TrimPrefix(p.Message, prefix)
+}
+
+// pluralDetail turns a singular problem detail into its plural form, e.g. "has no limit" into "have no limit"
+func pluralDetail(detail string) string {
+	if rest, ok := strings.CutPrefix(detail, "has "); ok {
+		return "have " + rest
+	}
+	if rest, ok := strings.CutPrefix(detail, "is "); ok {
+		return "are " + rest
+	}
+	return detail
+}
+
+// dedupeProblems collapses problems with the same kind and message found on several replicas of a workload
+// The collapsed problem keeps the first pod's name and records how many pods share it
+// Problems of standalone pods and problems unique to one replica are kept as they are
+func (a *AnalysisResult) dedupeProblems() {
+	workloads := make(map[podID]string, len(a.Report.Pods))
+	for i := range a.Report.Pods {
+		pod := &a.Report.Pods[i]
+		workloads[podIDOf(pod)] = pod.Workload
+	}
+
+	groupOf := func(p *Problem) (problemGroup, bool) {
+		workload := workloads[p.podID()]
+		if workload == "" {
+			return problemGroup{}, false
+		}
+		return problemGroup{p.Severity, p.Kind, p.Cluster, p.Namespace, workload, p.Container, problemDetail(p)}, true
+	}
+
+	counts := make(map[problemGroup]int)
+	for i := range a.Problems {
+		if group, ok := groupOf(&a.Problems[i]); ok {
+			counts[group]++
+		}
+	}
+
+	problems := a.Problems
+	a.Problems, a.ProblemsFound = []Problem{}, []string{}
+	emitted := make(map[problemGroup]bool)
+	for i := range problems {
+		p := problems[i]
+		group, ok := groupOf(&p)
+		if ok && counts[group] > 1 {
+			if emitted[group] {
+				continue
+			}
+			emitted[group] = true
+			p.Workload = group.Workload
+			p.Count = counts[group]
+			p.Message = fmt.Sprintf("%d pods of %s/%s ", p.Count, group.Namespace, group.Workload)
+			if group.Container != "" {
+				p.Message += fmt.Sprintf("container %s ", group.Container)
+			}
+			p.Message += pluralDetail(group.Detail)
+		}
+		a.addProblem(p)
+	}
+}
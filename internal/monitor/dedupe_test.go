@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestDedupeProblems_CollapsesReplicas(t *testing.T) {
+	analysis := &AnalysisResult{Report: MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "web-1", Workload: "deploy/web"},
+		{Namespace: "prod", PodName: "web-2", Workload: "deploy/web"},
+		{Namespace: "prod", PodName: "web-3", Workload: "deploy/web"},
+		{Namespace: "prod", PodName: "debug"},
+	}}}
+	for _, pod := range []string{"web-1", "web-2", "web-3", "debug"} {
+		analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "prod", pod, "has no memory limit defined"))
+	}
+	analysis.addProblem(newContainerProblem(SeverityWarning, ProblemKindNoRequest, "prod", "web-1", "app", "has no memory request defined"))
+	analysis.addProblem(newContainerProblem(SeverityWarning, ProblemKindNoRequest, "prod", "web-2", "app", "has no memory request defined"))
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", "web-1", "is using %.1f%% of its memory limit", 95.0))
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", "web-2", "is using %.1f%% of its memory limit", 97.0))
+
+	analysis.dedupeProblems()
+
+	expected := []string{
+		"3 pods of prod/deploy/web have no memory limit defined",
+		"Pod prod/debug has no memory limit defined",
+		"2 pods of prod/deploy/web container app have no memory request defined",
+		"Pod prod/web-1 is using 95.0% of its memory limit",
+		"Pod prod/web-2 is using 97.0% of its memory limit",
+	}
+	if len(analysis.ProblemsFound) != len(expected) || len(analysis.Problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %q", len(expected), analysis.ProblemsFound)
+	}
+	for i := range expected {
+		if analysis.ProblemsFound[i] != expected[i] {
+			t.Errorf("problem %d: expected %q, got %q", i, expected[i], analysis.ProblemsFound[i])
+		}
+	}
+	collapsed := analysis.Problems[0]
+	if collapsed.Count != 3 || collapsed.Workload != "deploy/web" || collapsed.PodName != "web-1" {
+		t.Errorf("expected a collapsed problem for 3 pods of deploy/web, got %+v", collapsed)
+	}
+	if analysis.Problems[1].Count != 0 {
+		t.Errorf("expected the standalone pod's problem to be kept as is, got %+v", analysis.Problems[1])
+	}
+}
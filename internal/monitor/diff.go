@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Pod changes between two reports
+const (
+	PodChangeAdded   = "added"
+	PodChangeRemoved = "removed"
+	PodChangeChanged = "changed"
+)
+
+// PodUsageDelta is the change in a pod's memory usage between two reports
+type PodUsageDelta struct {
+	Cluster   string             `json:"cluster,omitempty"`
+	Namespace string             `json:"namespace"`
+	PodName   string             `json:"pod_name"`
+	Change    string             `json:"change"` // added, removed or changed
+	OldUsage  *resource.Quantity `json:"old_usage,omitempty"`
+	NewUsage  *resource.Quantity `json:"new_usage,omitempty"`
+	Delta     *resource.Quantity `json:"delta,omitempty"` // New minus old usage, only when both are known
+}
+
+// ReportDiff compares a captured report with a newer one, e.g. before and after a deployment
+type ReportDiff struct {
+	OldTimestamp     time.Time       `json:"old_timestamp"`
+	NewTimestamp     time.Time       `json:"new_timestamp"`
+	Pods             []PodUsageDelta `json:"pods"`
+	NewProblems      []Problem       `json:"new_problems"`
+	ResolvedProblems []Problem       `json:"resolved_problems"`
+}
+
+// DiffReports matches the pods of both analyses by cluster, namespace and name and compares their usage and problems
+func DiffReports(old, current *AnalysisResult) *ReportDiff {
+	diff := &ReportDiff{
+		OldTimestamp:     old.Report.Summary.Timestamp,
+		NewTimestamp:     current.Report.Summary.Timestamp,
+		Pods:             []PodUsageDelta{},
+		NewProblems:      problemsMissingFrom(current.Problems, old.Problems),
+		ResolvedProblems: problemsMissingFrom(old.Problems, current.Problems),
+	}
+
+	oldPods := podsByID(old.Report.Pods)
+	newPods := podsByID(current.Report.Pods)
+	for id, pod := range newPods {
+		delta := PodUsageDelta{Cluster: id.Cluster, Namespace: id.Namespace, PodName: id.Name, Change: PodChangeAdded,
+			NewUsage: pod.CurrentUsage}
+		if oldPod, ok := oldPods[id]; ok {
+			delta.Change = PodChangeChanged
+			delta.OldUsage = oldPod.CurrentUsage
+			delta.Delta = usageDelta(oldPod.CurrentUsage, pod.CurrentUsage)
+		}
+		diff.Pods = append(diff.Pods, delta)
+	}
+	for id, pod := range oldPods {
+		if _, ok := newPods[id]; !ok {
+			diff.Pods = append(diff.Pods, PodUsageDelta{Cluster: id.Cluster, Namespace: id.Namespace, PodName: id.Name,
+				Change: PodChangeRemoved, OldUsage: pod.CurrentUsage})
+		}
+	}
+	sort.Slice(diff.Pods, func(i, j int) bool {
+		if diff.Pods[i].Cluster != diff.Pods[j].Cluster {
+			return diff.Pods[i].Cluster < diff.Pods[j].Cluster
+		}
+		if diff.Pods[i].Namespace != diff.Pods[j].Namespace {
+			return diff.Pods[i].Namespace < diff.Pods[j].Namespace
+		}
+		return diff.Pods[i].PodName < diff.Pods[j].PodName
+	})
+
+	return diff
+}
+
+// podsByID indexes pods by cluster, namespace and name
+func podsByID(pods []k8s.PodMemoryInfo) map[podID]*k8s.PodMemoryInfo {
+	byID := make(map[podID]*k8s.PodMemoryInfo, len(pods))
+	for i := range pods {
+		byID[podIDOf(&pods[i])] = &pods[i]
+	}
+	return byID
+}
+
+// usageDelta returns newUsage minus oldUsage, nil when either is unknown
+func usageDelta(oldUsage, newUsage *resource.Quantity) *resource.Quantity {
+	if oldUsage == nil || newUsage == nil {
+		return nil
+	}
+	delta := newUsage.DeepCopy()
+	delta.Sub(*oldUsage)
+	return &delta
+}
+
+// problemKey identifies a problem across reports, ignoring its message which embeds the current values
+func problemKey(p *Problem) string {
+	return p.Kind + "|" + p.Cluster + "|" + p.Namespace + "|" + p.PodName + "|" + p.Container + "|" + p.Node
+}
+
+// problemsMissingFrom returns the problems of problems that have no match in other
+func problemsMissingFrom(problems, other []Problem) []Problem {
+	known := make(map[string]bool, len(other))
+	for i := range other {
+		known[problemKey(&other[i])] = true
+	}
+	missing := []Problem{}
+	for i := range problems {
+		if !known[problemKey(&problems[i])] {
+			missing = append(missing, problems[i])
+		}
+	}
+	return missing
+}
+
+// PrintDiff prints the usage deltas and the problems that appeared or were resolved
+func (d *ReportDiff) PrintDiff(cfg *config.Config) {
+	fmt.Printf("=== Memory Report Diff ===\n")
+	fmt.Printf("Old report: %s\n", formatTimestamp(d.OldTimestamp, cfg))
+	fmt.Printf("New report: %s\n", formatTimestamp(d.NewTimestamp, cfg))
+
+	fmt.Printf("\nPods: %d\n", len(d.Pods))
+	for i := range d.Pods {
+		p := &d.Pods[i]
+		id := podID{Cluster: p.Cluster, Namespace: p.Namespace, Name: p.PodName}
+		fmt.Printf("  %s %s | Usage: %s\n", podChangeSymbol(p), id, formatUsageChange(p))
+	}
+
+	fmt.Printf("\nNew problems: %d\n", len(d.NewProblems))
+	for _, p := range d.NewProblems {
+		fmt.Printf("  - %s\n", p.Message)
+	}
+	fmt.Printf("Resolved problems: %d\n", len(d.ResolvedProblems))
+	for _, p := range d.ResolvedProblems {
+		fmt.Printf("  - %s\n", p.Message)
+	}
+	fmt.Printf("\n")
+}
+
+// PrintJSON prints the diff as a single JSON document
+func (d *ReportDiff) PrintJSON() {
+	NewJSONFormatter().write(d)
+}
+
+func podChangeSymbol(p *PodUsageDelta) string {
+	switch {
+	case p.Change == PodChangeAdded:
+		return "🆕"
+	case p.Change == PodChangeRemoved:
+		return "🗑️"
+	case p.Delta != nil && p.Delta.Sign() > 0:
+		return "📈"
+	case p.Delta != nil && p.Delta.Sign() < 0:
+		return "📉"
+	default:
+		return "➖"
+	}
+}
+
+// formatUsageChange formats the old and new usage, with the signed delta when both are known
+func formatUsageChange(p *PodUsageDelta) string {
+	switch p.Change {
+	case PodChangeAdded:
+		return "new pod, " + k8s.FormatMemory(p.NewUsage)
+	case PodChangeRemoved:
+		return k8s.FormatMemory(p.OldUsage) + ", pod gone"
+	}
+	change := fmt.Sprintf("%s -> %s", k8s.FormatMemory(p.OldUsage), k8s.FormatMemory(p.NewUsage))
+	if p.Delta != nil {
+		sign := ""
+		if p.Delta.Sign() >= 0 {
+			sign = "+"
+		}
+		change += fmt.Sprintf(" (%s%s)", sign, k8s.FormatMemory(p.Delta))
+	}
+	return change
+}
@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func diffTestPod(name string, usage int64, limit *resource.Quantity) k8s.PodMemoryInfo {
+	return k8s.PodMemoryInfo{
+		Namespace:     "prod",
+		PodName:       name,
+		CurrentUsage:  resource.NewQuantity(usage*mi, resource.BinarySI),
+		MemoryRequest: resource.NewQuantity(512*mi, resource.BinarySI),
+		MemoryLimit:   limit,
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80}
+	limit := resource.NewQuantity(1024*mi, resource.BinarySI)
+
+	// The old report goes through the JSON encoding, as when loaded with --diff
+	var buf bytes.Buffer
+	newJSONFormatterTo(&buf).FormatAnalysis(AnalyzeReport(&MemoryReport{Pods: []k8s.PodMemoryInfo{
+		diffTestPod("api", 300, limit),
+		diffTestPod("web", 200, nil),
+		diffTestPod("worker", 100, limit),
+	}}, cfg), cfg)
+	saved, err := decodeReports(&buf)
+	if err != nil {
+		t.Fatalf("decodeReports() failed: %v", err)
+	}
+	old := AnalyzeReport(&saved[0], cfg)
+
+	current := AnalyzeReport(&MemoryReport{Pods: []k8s.PodMemoryInfo{
+		diffTestPod("api", 250, nil),
+		diffTestPod("batch", 64, limit),
+		diffTestPod("web", 264, limit),
+	}}, cfg)
+
+	diff := DiffReports(old, current)
+
+	want := []struct {
+		name   string
+		change string
+		delta  int64
+	}{
+		{"api", PodChangeChanged, -50 * mi},
+		{"batch", PodChangeAdded, 0},
+		{"web", PodChangeChanged, 64 * mi},
+		{"worker", PodChangeRemoved, 0},
+	}
+	if len(diff.Pods) != len(want) {
+		t.Fatalf("expected %d pods, got %+v", len(want), diff.Pods)
+	}
+	for i, w := range want {
+		p := diff.Pods[i]
+		if p.PodName != w.name || p.Change != w.change {
+			t.Errorf("pod %d: expected %s %s, got %s %s", i, w.name, w.change, p.PodName, p.Change)
+		}
+		if w.change == PodChangeChanged && (p.Delta == nil || p.Delta.Value() != w.delta) {
+			t.Errorf("pod %s: expected delta %d, got %v", p.PodName, w.delta, p.Delta)
+		}
+	}
+
+	if len(diff.NewProblems) != 1 || diff.NewProblems[0].PodName != "api" || diff.NewProblems[0].Kind != ProblemKindNoLimit {
+		t.Errorf("expected api's missing limit as the only new problem, got %+v", diff.NewProblems)
+	}
+	if len(diff.ResolvedProblems) != 1 || diff.ResolvedProblems[0].PodName != "web" {
+		t.Errorf("expected web's missing limit to be resolved, got %+v", diff.ResolvedProblems)
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	diff := &ReportDiff{
+		Pods: []PodUsageDelta{
+			{Namespace: "prod", PodName: "web", Change: PodChangeChanged,
+				OldUsage: qty(200 * mi), NewUsage: qty(264 * mi), Delta: qty(64 * mi)},
+			{Namespace: "prod", PodName: "batch", Change: PodChangeAdded, NewUsage: qty(64 * mi)},
+		},
+		NewProblems: []Problem{{Message: "Pod prod/api has no memory limit defined"}},
+	}
+
+	output := captureStdout(t, func() { diff.PrintDiff(&config.Config{}) })
+
+	for _, want := range []string{
+		"📈 prod/web | Usage: 200.0 MB -> 264.0 MB (+64.0 MB)",
+		"🆕 prod/batch | Usage: new pod, 64.0 MB",
+		"New problems: 1",
+		"  - Pod prod/api has no memory limit defined",
+		"Resolved problems: 0",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
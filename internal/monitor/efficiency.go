@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// wastefulEfficiencyPercent is the usage/request ratio below which a namespace is flagged as over-requesting
+const wastefulEfficiencyPercent = 30.0
+
+// NamespaceEfficiency holds how much of its requested memory a namespace actually uses
+type NamespaceEfficiency struct {
+	Namespace         string            `json:"namespace"`
+	TotalUsage        resource.Quantity `json:"total_usage"`
+	TotalRequest      resource.Quantity `json:"total_request"`
+	EfficiencyPercent *float64          `json:"efficiency_percent,omitempty"` // nil when nothing is requested
+}
+
+// IsWasteful reports whether the namespace uses less than the wasteful threshold of its requests
+func (e *NamespaceEfficiency) IsWasteful() bool {
+	return e.EfficiencyPercent != nil && *e.EfficiencyPercent < wastefulEfficiencyPercent
+}
+
+// NamespaceEfficiencies computes usage/request efficiency per namespace
+// Only pods with both metrics and a request contribute, so missing data does not skew the ratio
+// Results are ordered from least to most efficient, with namespaces lacking requests last
+func (r *MemoryReport) NamespaceEfficiencies() []NamespaceEfficiency {
+	byNamespace := make(map[string]*NamespaceEfficiency)
+	var order []string
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		eff, ok := byNamespace[pod.Namespace]
+		if !ok {
+			eff = &NamespaceEfficiency{Namespace: pod.Namespace}
+			byNamespace[pod.Namespace] = eff
+			order = append(order, pod.Namespace)
+		}
+		if pod.CurrentUsage != nil && pod.MemoryRequest != nil {
+			eff.TotalUsage.Add(*pod.CurrentUsage)
+			eff.TotalRequest.Add(*pod.MemoryRequest)
+		}
+	}
+
+	result := make([]NamespaceEfficiency, 0, len(order))
+	for _, ns := range order {
+		eff := byNamespace[ns]
+		if request := eff.TotalRequest.Value(); request > 0 {
+			percent := float64(eff.TotalUsage.Value()) / float64(request) * 100
+			eff.EfficiencyPercent = &percent
+		}
+		result = append(result, *eff)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, pj := result[i].EfficiencyPercent, result[j].EfficiencyPercent
+		if pi == nil || pj == nil {
+			return pj == nil && pi != nil
+		}
+		return *pi < *pj
+	})
+	return result
+}
+
+// PrintEfficiencyReport prints the per-namespace request efficiency, highlighting wasteful namespaces
+func (r *MemoryReport) PrintEfficiencyReport() {
+	efficiencies := r.NamespaceEfficiencies()
+	if len(efficiencies) == 0 {
+		return
+	}
+
+	fmt.Printf("=== Namespace Memory Request Efficiency ===\n")
+	wasteful := 0
+	for i := range efficiencies {
+		eff := &efficiencies[i]
+		marker := "  "
+		if eff.IsWasteful() {
+			marker = "💸"
+			wasteful++
+		}
+		efficiency := "N/A (no requests)"
+		if eff.EfficiencyPercent != nil {
+			efficiency = k8s.FormatPercent(eff.EfficiencyPercent)
+		}
+		fmt.Printf("%s %s | Usage: %s | Request: %s | Efficiency: %s\n",
+			marker, eff.Namespace,
+			k8s.FormatMemory(&eff.TotalUsage), k8s.FormatMemory(&eff.TotalRequest), efficiency)
+	}
+	if wasteful > 0 {
+		fmt.Printf("\n%d namespaces use less than %.0f%% of their requested memory - consider lowering requests\n",
+			wasteful, wastefulEfficiencyPercent)
+	}
+	fmt.Printf("\n")
+}
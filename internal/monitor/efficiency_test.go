@@ -0,0 +1,33 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestNamespaceEfficiencies(t *testing.T) {
+	report := &MemoryReport{
+		Pods: []k8s.PodMemoryInfo{
+			{Namespace: "busy", PodName: "a", CurrentUsage: qty(90), MemoryRequest: qty(100)},
+			{Namespace: "idle", PodName: "b", CurrentUsage: qty(10), MemoryRequest: qty(100)},
+			{Namespace: "idle", PodName: "c", MemoryRequest: qty(500)}, // no metrics, ignored
+			{Namespace: "unrequested", PodName: "d", CurrentUsage: qty(50)},
+		},
+	}
+
+	efficiencies := report.NamespaceEfficiencies()
+
+	if len(efficiencies) != 3 {
+		t.Fatalf("expected 3 namespaces, got %d", len(efficiencies))
+	}
+	if efficiencies[0].Namespace != "idle" || *efficiencies[0].EfficiencyPercent != 10.0 {
+		t.Errorf("expected idle at 10%% first, got %s", efficiencies[0].Namespace)
+	}
+	if !efficiencies[0].IsWasteful() || efficiencies[1].IsWasteful() {
+		t.Errorf("expected only idle to be wasteful")
+	}
+	if efficiencies[2].Namespace != "unrequested" || efficiencies[2].EfficiencyPercent != nil {
+		t.Errorf("expected namespace without requests last with nil efficiency, got %+v", efficiencies[2])
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// expectedMemoryDriftPercent is how far usage may stray from the expected memory before it is flagged
+const expectedMemoryDriftPercent = 20.0
+
+// expectedMemorySectionTitle introduces the expected vs actual line under a pod
+const expectedMemorySectionTitle = "      🎯 Expected:"
+
+// expectedMemory parses the pod's expected memory annotation.
+// It returns nil without error when the annotation is not configured or not set.
+func expectedMemory(pod *k8s.PodMemoryInfo, annotation string) (*resource.Quantity, error) {
+	if annotation == "" {
+		return nil, nil
+	}
+	value, ok := pod.Annotations[annotation]
+	if !ok {
+		return nil, nil
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %w", annotation, value, err)
+	}
+	return &q, nil
+}
+
+// expectedMemoryDrift returns how far usage is from the expected memory, in percent of the expected value
+func expectedMemoryDrift(expected, usage *resource.Quantity) (float64, bool) {
+	if expected == nil || usage == nil || expected.Value() <= 0 {
+		return 0, false
+	}
+	return float64(usage.Value()-expected.Value()) / float64(expected.Value()) * 100, true
+}
+
+// formatExpectedMemory returns the expected vs actual line for a pod, empty when it has no expectation.
+// Unparseable annotations are reported on the line instead of failing the report.
+func formatExpectedMemory(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
+	expected, err := expectedMemory(pod, cfg.ExpectedMemory)
+	if err != nil {
+		return fmt.Sprintf("%s ⚠️ %v", expectedMemorySectionTitle, err)
+	}
+	if expected == nil {
+		return ""
+	}
+
+	line := fmt.Sprintf("%s %s vs Actual: %s", expectedMemorySectionTitle,
+		k8s.FormatMemory(expected), k8s.FormatMemory(pod.CurrentUsage))
+	drift, ok := expectedMemoryDrift(expected, pod.CurrentUsage)
+	if !ok {
+		return line
+	}
+	line += fmt.Sprintf(" (%+.1f%%)", drift)
+	if math.Abs(drift) > expectedMemoryDriftPercent {
+		line += " ⚠️ drift"
+	}
+	return line
+}
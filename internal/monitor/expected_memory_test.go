@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestFormatExpectedMemory(t *testing.T) {
+	const annotation = "team.io/expected-memory"
+	cfg := &config.Config{ExpectedMemory: annotation}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		usage       int64
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:        "within tolerance",
+			annotations: map[string]string{annotation: "512Mi"},
+			usage:       550 * mi,
+			want:        []string{"Expected:", "vs Actual:", "(+7.4%)"},
+			notWant:     []string{"drift"},
+		},
+		{
+			name:        "usage above expectation",
+			annotations: map[string]string{annotation: "512Mi"},
+			usage:       768 * mi,
+			want:        []string{"(+50.0%)", "drift"},
+		},
+		{
+			name:        "usage below expectation",
+			annotations: map[string]string{annotation: "1Gi"},
+			usage:       256 * mi,
+			want:        []string{"(-75.0%)", "drift"},
+		},
+		{
+			name:        "unparseable annotation",
+			annotations: map[string]string{annotation: "lots"},
+			usage:       256 * mi,
+			want:        []string{"invalid team.io/expected-memory annotation \"lots\""},
+			notWant:     []string{"vs Actual"},
+		},
+		{
+			name:        "annotation missing",
+			annotations: map[string]string{"owner": "team-a"},
+			usage:       256 * mi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &k8s.PodMemoryInfo{Annotations: tt.annotations, CurrentUsage: qty(tt.usage)}
+			got := formatExpectedMemory(pod, cfg)
+			if len(tt.want) == 0 && got != "" {
+				t.Fatalf("Expected no line, got %q", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in %q", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Did not expect %q in %q", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatMetadataSection_ShowsExpectedMemoryWithoutOtherMetadata(t *testing.T) {
+	cfg := &config.Config{ExpectedMemory: "team.io/expected-memory"}
+	pod := &k8s.PodMemoryInfo{
+		Annotations:  map[string]string{"team.io/expected-memory": "256Mi"},
+		CurrentUsage: qty(256 * mi),
+	}
+
+	got := formatMetadataSection(pod, cfg)
+	if !strings.HasPrefix(got, expectedMemorySectionTitle) || !strings.Contains(got, "(+0.0%)") {
+		t.Errorf("Expected expected-memory line, got %q", got)
+	}
+}
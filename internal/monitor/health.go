@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthStatus tracks the outcome of the watch cycles for the /healthz and /readyz probes
+// It is shared between the main loop, which records cycles, and the HTTP server
+type HealthStatus struct {
+	mu          sync.Mutex
+	window      time.Duration // How long a successful cycle keeps the watcher ready
+	now         func() time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// NewHealthStatus creates a status that stays ready for window after each successful cycle
+func NewHealthStatus(window time.Duration, now func() time.Time) *HealthStatus {
+	return &HealthStatus{window: window, now: now}
+}
+
+// RecordCycle records the result of a watch cycle; a nil status ignores it
+func (h *HealthStatus) RecordCycle(err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+	if err == nil {
+		h.lastSuccess = h.now()
+	}
+}
+
+// Ready reports whether a cycle succeeded within the readiness window, with a short explanation
+func (h *HealthStatus) Ready() (bool, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastSuccess.IsZero() {
+		if h.lastErr != nil {
+			return false, fmt.Sprintf("no successful cycle yet, last error: %v", h.lastErr)
+		}
+		return false, "no cycle completed yet"
+	}
+	age := h.now().Sub(h.lastSuccess)
+	if age > h.window {
+		// The last cycle may have succeeded with none run since, e.g. when a cycle outlasts the window
+		reason := fmt.Sprintf("last successful cycle %s ago", age.Round(time.Second))
+		if h.lastErr != nil {
+			reason += fmt.Sprintf(", last error: %v", h.lastErr)
+		}
+		return false, reason
+	}
+	return true, fmt.Sprintf("last successful cycle %s ago", age.Round(time.Second))
+}
+
+// Handler serves /healthz, answering as long as the process is up, and /readyz
+func (h *HealthStatus) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		ready, reason := h.Ready()
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprintln(w, reason)
+	})
+	return mux
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthStatus_Ready(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewHealthStatus(time.Minute, func() time.Time { return now })
+
+	if ready, reason := h.Ready(); ready || reason != "no cycle completed yet" {
+		t.Errorf("expected not ready before the first cycle, got %v %q", ready, reason)
+	}
+
+	h.RecordCycle(errors.New("metrics unavailable"))
+	if ready, reason := h.Ready(); ready || !strings.Contains(reason, "metrics unavailable") {
+		t.Errorf("expected not ready after a failed first cycle, got %v %q", ready, reason)
+	}
+
+	h.RecordCycle(nil)
+	if ready, _ := h.Ready(); !ready {
+		t.Error("expected ready after a successful cycle")
+	}
+
+	// A failed cycle is tolerated while the last success is within the window
+	now = now.Add(45 * time.Second)
+	h.RecordCycle(errors.New("timeout"))
+	if ready, _ := h.Ready(); !ready {
+		t.Error("expected ready within the window after a failed cycle")
+	}
+
+	now = now.Add(30 * time.Second)
+	if ready, reason := h.Ready(); ready || !strings.Contains(reason, "1m15s ago, last error: timeout") {
+		t.Errorf("expected not ready once the window passed, got %v %q", ready, reason)
+	}
+
+	// Without a failed cycle since, the reason has no error clause
+	h.RecordCycle(nil)
+	now = now.Add(2 * time.Minute)
+	if ready, reason := h.Ready(); ready || reason != "last successful cycle 2m0s ago" {
+		t.Errorf("expected a stale success without an error, got %v %q", ready, reason)
+	}
+}
+
+func TestHealthStatus_Handler(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := NewHealthStatus(time.Minute, func() time.Time { return now })
+	handler := h.Handler()
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("expected /healthz to be OK, got %d", code)
+	}
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected /readyz to be unavailable before the first cycle, got %d", code)
+	}
+
+	h.RecordCycle(nil)
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Errorf("expected /readyz to be OK after a successful cycle, got %d", code)
+	}
+}
+
+func TestHealthStatus_NilIgnoresCycles(t *testing.T) {
+	var h *HealthStatus
+	h.RecordCycle(nil)
+}
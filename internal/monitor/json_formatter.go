@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// ReportSchemaVersion identifies the layout of the JSON report document
+// Bump it whenever fields are renamed, removed or change meaning
+const ReportSchemaVersion = "1"
+
+// reportEnvelope wraps the analysis with the schema version so consumers can detect format changes
+type reportEnvelope struct {
+	SchemaVersion string    `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	*AnalysisResult
+}
+
+// newReportEnvelope wraps the analysis, dated at the report's collection time
+func newReportEnvelope(analysis *AnalysisResult) reportEnvelope {
+	return reportEnvelope{
+		SchemaVersion:  ReportSchemaVersion,
+		GeneratedAt:    analysis.Report.Summary.Timestamp,
+		AnalysisResult: analysis,
+	}
+}
+
+// summaryDocument is the compact --summary-only document, without the per-pod details
+type summaryDocument struct {
+	SchemaVersion    string            `json:"schema_version"`
+	GeneratedAt      time.Time         `json:"generated_at"`
+	Summary          k8s.MemorySummary `json:"summary"`
+	CriticalProblems int               `json:"critical_problems"`
+	WarningProblems  int               `json:"warning_problems"`
+}
+
+// newSummaryDocument extracts the summary and problem counts of the analysis
+// The summary already carries the pods-at-risk counts filled in by the analysis, and the problem
+// counts include those dropped by --max-problems
+func newSummaryDocument(analysis *AnalysisResult) summaryDocument {
+	doc := summaryDocument{
+		SchemaVersion: ReportSchemaVersion,
+		GeneratedAt:   analysis.Report.Summary.Timestamp,
+		Summary:       analysis.Report.Summary,
+	}
+	doc.CriticalProblems, doc.WarningProblems = analysis.SeverityCounts()
+	return doc
+}
+
+// JSONFormatter handles JSON Lines output, writing one JSON document per line
+type JSONFormatter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONFormatter creates a new JSON formatter
+func NewJSONFormatter() *JSONFormatter {
+	return newJSONFormatterTo(os.Stdout)
+}
+
+// newJSONFormatterTo creates a JSON formatter writing to w
+func newJSONFormatterTo(w io.Writer) *JSONFormatter {
+	return &JSONFormatter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// FormatAnalysis writes the analysis as a single JSON line, one line per problem in problems-only mode,
+// or only the summary in summary-only mode
+func (f *JSONFormatter) FormatAnalysis(analysis *AnalysisResult, cfg *config.Config) {
+	if cfg.ProblemsOnly {
+		f.writeProblems(analysis.Problems)
+		return
+	}
+	if cfg.SummaryOnly {
+		f.write(newSummaryDocument(analysis))
+		return
+	}
+	f.write(newReportEnvelope(analysis))
+}
+
+// writeProblems writes each structured problem on its own line
+func (f *JSONFormatter) writeProblems(problems []Problem) {
+	for i := range problems {
+		f.write(&problems[i])
+	}
+}
+
+func (f *JSONFormatter) write(v any) {
+	if err := f.encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON record: %v\n", err)
+	}
+}
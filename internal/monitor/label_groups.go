@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// noLabelValue is the group of pods that do not carry the grouping label
+const noLabelValue = "(none)"
+
+// LabelGroupUsage aggregates the memory of the listed pods sharing one value of a label
+type LabelGroupUsage struct {
+	Value        string            `json:"value"`
+	Pods         int               `json:"pods"`
+	TotalUsage   resource.Quantity `json:"total_usage"`
+	TotalRequest resource.Quantity `json:"total_request"`
+	TotalLimit   resource.Quantity `json:"total_limit"`
+	UsageShare   *float64          `json:"usage_share,omitempty"` // Percent of the usage of all listed pods
+}
+
+// LabelGroupUsages sums usage, requests and limits of the listed pods per value of label
+// Groups are ordered by usage, largest first, with pods lacking the label last
+func (r *MemoryReport) LabelGroupUsages(label string) []LabelGroupUsage {
+	byValue := make(map[string]*LabelGroupUsage)
+	var total resource.Quantity
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		value, ok := pod.Labels[label]
+		if !ok {
+			value = noLabelValue
+		}
+		group, ok := byValue[value]
+		if !ok {
+			group = &LabelGroupUsage{Value: value}
+			byValue[value] = group
+		}
+		group.Pods++
+		if pod.CurrentUsage != nil {
+			group.TotalUsage.Add(*pod.CurrentUsage)
+			total.Add(*pod.CurrentUsage)
+		}
+		if pod.MemoryRequest != nil {
+			group.TotalRequest.Add(*pod.MemoryRequest)
+		}
+		if pod.MemoryLimit != nil {
+			group.TotalLimit.Add(*pod.MemoryLimit)
+		}
+	}
+
+	result := make([]LabelGroupUsage, 0, len(byValue))
+	for _, group := range byValue {
+		if total.Value() > 0 {
+			group.UsageShare = percentOf(&group.TotalUsage, &total)
+		}
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Value == noLabelValue) != (result[j].Value == noLabelValue) {
+			return result[j].Value == noLabelValue
+		}
+		if cmp := result[i].TotalUsage.Cmp(result[j].TotalUsage); cmp != 0 {
+			return cmp > 0
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// PrintLabelGroupReport prints memory totals per value of a pod label, e.g. per team for showback
+func (r *MemoryReport) PrintLabelGroupReport(label string) {
+	groups := r.LabelGroupUsages(label)
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Printf("=== Memory by Label %s ===\n", label)
+	table := newAlignedTable(false)
+	for i := range groups {
+		g := &groups[i]
+		table.addRow("", "",
+			g.Value,
+			fmt.Sprintf("Pods: %d", g.Pods),
+			fmt.Sprintf("Usage: %s (%s of total)", k8s.FormatMemoryTotal(&g.TotalUsage), k8s.FormatPercent(g.UsageShare)),
+			"Requests: "+k8s.FormatMemoryTotal(&g.TotalRequest),
+			"Limits: "+k8s.FormatMemoryTotal(&g.TotalLimit),
+		)
+	}
+	fmt.Print(table.String())
+	fmt.Printf("\n")
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func labelGroupTestReport() *MemoryReport {
+	return &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "api", Labels: map[string]string{"team": "payments"},
+			CurrentUsage: qty(300 * mi), MemoryRequest: qty(256 * mi), MemoryLimit: qty(512 * mi)},
+		{Namespace: "prod", PodName: "worker", Labels: map[string]string{"team": "payments"},
+			CurrentUsage: qty(100 * mi), MemoryRequest: qty(128 * mi)},
+		{Namespace: "prod", PodName: "search", Labels: map[string]string{"team": "discovery"},
+			CurrentUsage: qty(500 * mi), MemoryLimit: qty(1024 * mi)},
+		{Namespace: "prod", PodName: "idle", Labels: map[string]string{"team": "discovery"}},
+		{Namespace: "dev", PodName: "scratch", CurrentUsage: qty(100 * mi)},
+	}}
+}
+
+func TestLabelGroupUsages(t *testing.T) {
+	groups := labelGroupTestReport().LabelGroupUsages("team")
+
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", groups)
+	}
+	expected := []struct {
+		value   string
+		pods    int
+		usage   int64
+		request int64
+		limit   int64
+		share   float64
+	}{
+		{"discovery", 2, 500, 0, 1024, 50},
+		{"payments", 2, 400, 384, 512, 40},
+		{noLabelValue, 1, 100, 0, 0, 10},
+	}
+	for i, want := range expected {
+		g := groups[i]
+		if g.Value != want.value || g.Pods != want.pods {
+			t.Errorf("group %d: expected %s with %d pods, got %s with %d", i, want.value, want.pods, g.Value, g.Pods)
+		}
+		if g.TotalUsage.Value() != want.usage*mi || g.TotalRequest.Value() != want.request*mi || g.TotalLimit.Value() != want.limit*mi {
+			t.Errorf("group %s: unexpected totals usage %v, request %v, limit %v", g.Value, &g.TotalUsage, &g.TotalRequest, &g.TotalLimit)
+		}
+		if g.UsageShare == nil || *g.UsageShare != want.share {
+			t.Errorf("group %s: expected %.0f%% usage share, got %v", g.Value, want.share, g.UsageShare)
+		}
+	}
+}
+
+func TestLabelGroupUsages_NoUsage(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{{Namespace: "prod", PodName: "api", MemoryRequest: qty(mi)}}}
+
+	groups := report.LabelGroupUsages("team")
+
+	if len(groups) != 1 || groups[0].Value != noLabelValue || groups[0].UsageShare != nil {
+		t.Errorf("expected one unlabelled group without a usage share, got %+v", groups)
+	}
+}
+
+func TestPrintLabelGroupReport(t *testing.T) {
+	out := captureStdout(t, func() { labelGroupTestReport().PrintLabelGroupReport("team") })
+	for _, want := range []string{
+		"=== Memory by Label team ===",
+		"discovery",
+		"Usage: 400.0 MB (40.0% of total)",
+		"Requests: 384.0 MB",
+		"(none)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "discovery") > strings.Index(out, "payments") {
+		t.Errorf("expected the largest group first:\n%s", out)
+	}
+}
@@ -19,7 +19,7 @@ func pct(v float64) *float64 {
 
 func TestGetMemoryStatus_NoData(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "no_data" {
 		t.Errorf("expected no_data, got %s", status)
 	}
@@ -27,7 +27,7 @@ func TestGetMemoryStatus_NoData(t *testing.T) {
 
 func TestGetMemoryStatus_NoConfig(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{CurrentUsage: qty(1)}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "no_config" {
 		t.Errorf("expected no_config, got %s", status)
 	}
@@ -35,7 +35,7 @@ func TestGetMemoryStatus_NoConfig(t *testing.T) {
 
 func TestGetMemoryStatus_NoRequest(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryLimit: qty(1)}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "no_request" {
 		t.Errorf("expected no_request, got %s", status)
 	}
@@ -43,7 +43,7 @@ func TestGetMemoryStatus_NoRequest(t *testing.T) {
 
 func TestGetMemoryStatus_NoLimit(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1)}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "no_limit" {
 		t.Errorf("expected no_limit, got %s", status)
 	}
@@ -56,7 +56,7 @@ func TestGetMemoryStatus_CriticalByUsage(t *testing.T) {
 		MemoryLimit:   qty(1),
 		UsagePercent:  pct(95),
 	}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "critical" {
 		t.Errorf("expected critical, got %s", status)
 	}
@@ -69,7 +69,7 @@ func TestGetMemoryStatus_CriticalByLimitUsage(t *testing.T) {
 		MemoryLimit:       qty(1),
 		LimitUsagePercent: pct(90),
 	}
-	status := getMemoryStatus(pod, &config.Config{})
+	status := getMemoryStatus(pod, &config.Config{}).Status
 	if status != "critical" {
 		t.Errorf("expected critical, got %s", status)
 	}
@@ -83,12 +83,46 @@ func TestGetMemoryStatus_Warning(t *testing.T) {
 		UsagePercent:  pct(80),
 	}
 	cfg := &config.Config{MemoryWarningPercent: 70}
-	status := getMemoryStatus(pod, cfg)
+	status := getMemoryStatus(pod, cfg).Status
 	if status != "warning" {
 		t.Errorf("expected warning, got %s", status)
 	}
 }
 
+func TestGetMemoryStatus_PrimaryMetric(t *testing.T) {
+	tests := []struct {
+		name          string
+		primaryMetric string
+		usagePercent  float64
+		limitPercent  float64
+		expected      string
+	}{
+		{"request over threshold", config.PrimaryMetricRequest, 85, 40, "warning"},
+		{"default is request", "", 85, 40, "warning"},
+		{"request ignores limit", config.PrimaryMetricRequest, 40, 85, "ok"},
+		{"limit over threshold", config.PrimaryMetricLimit, 40, 85, "warning"},
+		{"limit ignores request", config.PrimaryMetricLimit, 85, 40, "ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &k8s.PodMemoryInfo{
+				CurrentUsage:      qty(1),
+				MemoryRequest:     qty(1),
+				MemoryLimit:       qty(1),
+				UsagePercent:      pct(tt.usagePercent),
+				LimitUsagePercent: pct(tt.limitPercent),
+				Ready:             true,
+				Phase:             "Running",
+			}
+			cfg := &config.Config{MemoryWarningPercent: 80, PrimaryMetric: tt.primaryMetric}
+			if status := getMemoryStatus(pod, cfg).Status; status != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, status)
+			}
+		})
+	}
+}
+
 func TestGetMemoryStatus_NotReady(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{
 		CurrentUsage:  qty(1),
@@ -99,12 +133,51 @@ func TestGetMemoryStatus_NotReady(t *testing.T) {
 		Phase:         "Pending",
 	}
 	cfg := &config.Config{MemoryWarningPercent: 80}
-	status := getMemoryStatus(pod, cfg)
+	status := getMemoryStatus(pod, cfg).Status
 	if status != "not_ready" {
 		t.Errorf("expected not_ready, got %s", status)
 	}
 }
 
+func TestGetMemoryStatus_Succeeded(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80}
+	tests := []struct {
+		name string
+		pod  k8s.PodMemoryInfo
+	}{
+		{"without metrics", k8s.PodMemoryInfo{Phase: "Succeeded"}},
+		{"with a last sample", k8s.PodMemoryInfo{Phase: "Succeeded", CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := getMemoryStatus(&tt.pod, cfg).Status; status != "completed" {
+				t.Errorf("expected completed, got %s", status)
+			}
+			container := &k8s.ContainerMemoryInfo{ContainerName: "job", CurrentUsage: tt.pod.CurrentUsage}
+			if status := getContainerMemoryStatus(&tt.pod, container, cfg).Status; status != "completed" {
+				t.Errorf("expected completed container, got %s", status)
+			}
+			if state := podStateInfo(&tt.pod); state != "Succeeded/Completed" {
+				t.Errorf("expected Succeeded/Completed, got %s", state)
+			}
+		})
+	}
+}
+
+func TestPodsWithProblems_SkipsCompletedPods(t *testing.T) {
+	pods := []k8s.PodMemoryInfo{
+		{Namespace: "batch", PodName: "job-done", Phase: "Succeeded"},
+		{Namespace: "batch", PodName: "job-failed", Phase: "Failed", CurrentUsage: qty(1), MemoryRequest: qty(2), MemoryLimit: qty(3)},
+	}
+
+	problems := podsWithProblems(pods, &config.Config{MemoryWarningPercent: 80})
+
+	if len(problems) != 1 || problems[0].PodName != "job-failed" {
+		t.Errorf("expected only the failed job, got %v", problems)
+	}
+}
+
 func TestGetMemoryStatus_Ok(t *testing.T) {
 	pod := &k8s.PodMemoryInfo{
 		CurrentUsage:  qty(1),
@@ -115,7 +188,7 @@ func TestGetMemoryStatus_Ok(t *testing.T) {
 		Phase:         "Running",
 	}
 	cfg := &config.Config{MemoryWarningPercent: 80}
-	status := getMemoryStatus(pod, cfg)
+	status := getMemoryStatus(pod, cfg).Status
 	if status != "ok" {
 		t.Errorf("expected ok, got %s", status)
 	}
@@ -155,20 +228,91 @@ func TestGetContainerMemoryStatus_PerContainerEvaluation(t *testing.T) {
 	cfg := &config.Config{MemoryWarningPercent: 80}
 
 	// Container with config should be "ok"
-	status1 := getContainerMemoryStatus(pod, containerWithConfig, cfg)
+	status1 := getContainerMemoryStatus(pod, containerWithConfig, cfg).Status
 	if status1 != "ok" {
 		t.Errorf("container with config should be 'ok', got %s", status1)
 	}
 
 	// Container without config should be "no_config"
-	status2 := getContainerMemoryStatus(pod, containerWithoutConfig, cfg)
+	status2 := getContainerMemoryStatus(pod, containerWithoutConfig, cfg).Status
 	if status2 != "no_config" {
 		t.Errorf("container without config should be 'no_config', got %s", status2)
 	}
 
 	// Using old getMemoryStatus on pod would incorrectly return "no_config" for both
-	podStatus := getMemoryStatus(pod, cfg)
+	podStatus := getMemoryStatus(pod, cfg).Status
 	if podStatus != "no_data" { // pod has no CurrentUsage
 		t.Errorf("pod without usage should be 'no_data', got %s", podStatus)
 	}
 }
+
+func TestGetMemoryStatus_Reason(t *testing.T) {
+	tests := []struct {
+		name          string
+		pod           k8s.PodMemoryInfo
+		primaryMetric string
+		expected      memoryStatus
+	}{
+		{
+			name:     "limit critical",
+			pod:      k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(60), LimitUsagePercent: pct(92), Ready: true, Phase: "Running"},
+			expected: memoryStatus{"critical", "limit_usage 92.0% ≥ 90%"},
+		},
+		{
+			name:     "request critical takes precedence",
+			pod:      k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(97), LimitUsagePercent: pct(92), Ready: true, Phase: "Running"},
+			expected: memoryStatus{"critical", "request_usage 97.0% ≥ 95%"},
+		},
+		{
+			name:          "warning on the primary metric",
+			pod:           k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(40), LimitUsagePercent: pct(85), Ready: true, Phase: "Running"},
+			primaryMetric: config.PrimaryMetricLimit,
+			expected:      memoryStatus{"warning", "limit_usage 85.0% ≥ 80%"},
+		},
+		{
+			name:     "pending pod",
+			pod:      k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(40), LimitUsagePercent: pct(20), Phase: "Pending"},
+			expected: memoryStatus{"not_ready", "phase Pending"},
+		},
+		{
+			name: "running but not ready",
+			pod: k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(40), LimitUsagePercent: pct(20), Phase: "Running",
+				NotReadyConditions: []k8s.PodConditionInfo{{Type: "ContainersReady", Reason: "ContainersNotReady"}}},
+			expected: memoryStatus{"not_ready", "not ready (ContainersReady: ContainersNotReady)"},
+		},
+		{
+			name:     "healthy",
+			pod:      k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(40), LimitUsagePercent: pct(20), Ready: true, Phase: "Running"},
+			expected: memoryStatus{"ok", "request_usage 40.0% < 80%"},
+		},
+		{
+			name:     "missing limit",
+			pod:      k8s.PodMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), UsagePercent: pct(99), Ready: true, Phase: "Running"},
+			expected: memoryStatus{"no_limit", "no memory limit"},
+		},
+		{
+			name:     "no metrics",
+			pod:      k8s.PodMemoryInfo{MemoryRequest: qty(1), MemoryLimit: qty(1), Ready: true, Phase: "Running"},
+			expected: memoryStatus{"no_data", "no usage metrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{MemoryWarningPercent: 80, PrimaryMetric: tt.primaryMetric}
+			if status := getMemoryStatus(&tt.pod, cfg); status != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestGetContainerMemoryStatus_Reason(t *testing.T) {
+	pod := &k8s.PodMemoryInfo{Ready: true, Phase: "Running"}
+	container := &k8s.ContainerMemoryInfo{CurrentUsage: qty(1), MemoryRequest: qty(1), MemoryLimit: qty(1), UsagePercent: pct(50), LimitUsagePercent: pct(95)}
+
+	status := getContainerMemoryStatus(pod, container, &config.Config{MemoryWarningPercent: 80})
+	if status.explanation() != "(critical: limit_usage 95.0% ≥ 90%)" {
+		t.Errorf("unexpected explanation %q", status.explanation())
+	}
+}
@@ -2,9 +2,12 @@ package monitor
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
 	"log/slog"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/eduardoferro/k8s-memory-watch/internal/config"
 	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
@@ -13,109 +16,293 @@ import (
 // MemoryMonitor orchestrates memory monitoring operations
 type MemoryMonitor struct {
 	k8sClient *k8s.Client
+	clusters  []*k8s.Client // One client per kubeconfig context in multi-cluster mode, k8sClient is the first
 	config    *config.Config
+
+	slack        *SlackNotifier
+	webhook      *WebhookSink
+	notifiedPods map[string]bool       // Pods already reported as critical, keyed by criticalPodKey
+	session      *SessionStats         // Totals accumulated across cycles
+	knownPods    map[podID]bool        // Pods seen in the previous cycle, nil before the first one
+	podStatuses  map[podID]string      // Memory status of each pod in the previous cycle, nil before the first one
+	podSnapshots map[podID]podSnapshot // Status and usage bucket of each pod in the previous cycle, nil before the first one
+	cycle        int                   // Collections started so far, failed ones included
+	oomKills     *OOMCounter           // OOMKilled restarts between cycles, served on /metrics
+	apiCalls     *APICallTotals        // API requests of all cycles, served on /metrics
+	clock        k8s.Clock             // Time source for timestamps, shared with the Kubernetes client
+	outputFile   *RotatingWriter       // Report file used instead of stdout, nil when disabled
 }
 
 // New creates a new memory monitor
 func New(cfg *config.Config) (*MemoryMonitor, error) {
-	// Create Kubernetes client
-	client, err := k8s.NewClient(cfg.KubeConfig, cfg.InCluster)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	// Create one Kubernetes client per context, or a single one for the default context
+	var clusters []*k8s.Client
+	for _, contextName := range cfg.Contexts {
+		client, err := k8s.NewClientForContext(cfg.KubeConfig, contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+		}
+		clusters = append(clusters, configureClient(client, cfg))
+	}
+	if len(clusters) == 0 {
+		client, err := k8s.NewClient(cfg.KubeConfig, cfg.InCluster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+		}
+		clusters = append(clusters, configureClient(client, cfg))
 	}
 
-	return &MemoryMonitor{
-		k8sClient: client,
-		config:    cfg,
-	}, nil
+	monitor := &MemoryMonitor{
+		k8sClient:    clusters[0],
+		clusters:     clusters,
+		config:       cfg,
+		notifiedPods: map[string]bool{},
+		session:      newSessionStats(time.Now()),
+		oomKills:     NewOOMCounter(),
+		apiCalls:     NewAPICallTotals(),
+		clock:        k8s.RealClock{},
+	}
+	if cfg.SlackWebhookURL != "" {
+		monitor.slack = NewSlackNotifier(cfg.SlackWebhookURL, cfg.RequestTimeout)
+	}
+	if cfg.WebhookURL != "" {
+		monitor.webhook = NewWebhookSink(cfg.WebhookURL, cfg.WebhookHeaders, cfg.RequestTimeout)
+	}
+	if cfg.OutputFile != "" {
+		var err error
+		monitor.outputFile, err = NewRotatingWriter(cfg.OutputFile, cfg.RotateSizeBytes(), cfg.RotateInterval, monitor.Now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return monitor, nil
+}
+
+// configureClient applies the collection settings of cfg to a Kubernetes client
+func configureClient(client *k8s.Client, cfg *config.Config) *k8s.Client {
+	client.SetRetryPolicy(k8s.RetryPolicy{MaxRetries: cfg.MaxRetries, Backoff: cfg.RetryBackoff})
+	client.SetListPageSize(cfg.ListPageSize)
+	client.SetNodeName(cfg.NodeName)
+	client.SetMaxNamespaces(cfg.MaxNamespaces)
+	client.SetMetricsAPIGroup(cfg.MetricsAPIGroup)
+	if cfg.NoMetrics {
+		client.DisableMetrics()
+	}
+	client.SetEnvVars(cfg.EnvVars)
+	client.SetContainerFilter(k8s.ContainerFilter{
+		Include:       cfg.Containers,
+		Exclude:       cfg.ExcludeContainers,
+		System:        cfg.SystemContainers,
+		IncludeSystem: cfg.IncludeSystem,
+	})
+	client.SetStrict(cfg.Strict)
+	client.SetLabelSelector(cfg.LabelSelector)
+	client.SetAnnotationsAsLabels(cfg.AnnotationsAsLabels)
+	client.SetSampling(cfg.Samples, cfg.SampleInterval)
+	if cfg.RefreshMetricsOnly {
+		client.SetPodCacheTTL(cfg.PodCacheTTL)
+	}
+	return client
+}
+
+// clients returns the clients collected from each cycle
+// Monitors built without cluster clients use the single k8sClient
+func (m *MemoryMonitor) clients() []*k8s.Client {
+	if len(m.clusters) == 0 {
+		return []*k8s.Client{m.k8sClient}
+	}
+	return m.clusters
 }
 
 // HealthCheck verifies the monitor can connect to Kubernetes
 func (m *MemoryMonitor) HealthCheck(ctx context.Context) error {
-	if m.config.Output != config.OutputFormatCSV {
+	if m.config.IsTableOutput() {
 		slog.Info("Performing health check...")
 	}
 
-	err := m.k8sClient.HealthCheck(ctx)
-	if err != nil {
-		return fmt.Errorf("kubernetes health check failed: %w", err)
+	for _, client := range m.clients() {
+		if err := client.HealthCheck(ctx); err != nil {
+			if client.Cluster() != "" {
+				return fmt.Errorf("kubernetes health check failed for cluster %s: %w", client.Cluster(), err)
+			}
+			return fmt.Errorf("kubernetes health check failed: %w", err)
+		}
 	}
 
-	if m.config.Output != config.OutputFormatCSV {
+	if m.config.IsTableOutput() {
 		slog.Info("Health check passed - Kubernetes cluster is accessible")
 	}
 	return nil
 }
 
+// Diagnose runs the connectivity and permission checklist against the configured namespace
+func (m *MemoryMonitor) Diagnose(ctx context.Context) []k8s.DiagnosticResult {
+	return m.k8sClient.Diagnose(ctx, m.config.Namespace)
+}
+
 // CollectMemoryInfo collects memory information from pods based on configuration
 func (m *MemoryMonitor) CollectMemoryInfo(ctx context.Context) (*MemoryReport, error) {
-	if m.config.Output != config.OutputFormatCSV {
+	if m.config.IsTableOutput() {
 		slog.Info("Starting memory information collection...",
 			"target_namespace", m.config.Namespace,
 			"all_namespaces", m.config.AllNamespaces)
 	}
 
-	var pods []k8s.PodMemoryInfo
-	var summary *k8s.MemorySummary
-	var err error
-
-	switch {
-	case m.config.Namespace != "":
-		// Monitor specific namespace
-		pods, summary, err = m.k8sClient.GetPodsMemoryInfo(ctx, m.config.Namespace, false)
-	case m.config.AllNamespaces:
-		// Monitor all namespaces
-		pods, summary, err = m.k8sClient.GetPodsMemoryInfo(ctx, "", true)
-	default:
-		// Default to all namespaces
-		pods, summary, err = m.k8sClient.GetAllPodsMemoryInfo(ctx)
-	}
-
+	m.cycle++
+	start := time.Now()
+	pods, summary, err := m.collectClusters(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect memory info: %w", err)
 	}
+	summary.Timings.Total = time.Since(start)
+	// Diffed before the phase filter so pods changing phase are not reported as removed
+	// A collection truncated by --max-namespaces still covers the same namespaces every cycle
+	m.trackPodChanges(pods, summary.Cancelled())
+	m.oomKills.observe(pods, summary.Partial)
+
+	// The summary keeps describing every collected pod; only the listed pods are filtered
+	// An explicitly requested pod is always shown, whatever its phase
+	var phaseFilter []string
+	if m.config.PodName == "" && len(m.config.IncludePhases) > 0 {
+		phaseFilter = m.config.IncludePhases
+		pods = filterPodsByPhase(pods, phaseFilter)
+	}
+	var excluded int
+	if m.config.PodName == "" && len(m.config.ExcludeLabels) > 0 {
+		shown := len(pods)
+		pods = filterPodsByExcludedLabels(pods, m.config.ExcludeLabels)
+		excluded = shown - len(pods)
+	}
 
-	// Sort pods by namespace and name for consistent output
-	sort.Slice(pods, func(i, j int) bool {
-		if pods[i].Namespace != pods[j].Namespace {
-			return pods[i].Namespace < pods[j].Namespace
+	for i := range pods {
+		pods[i].CalculateUsagePercent()
+		setContainerUsageShares(&pods[i])
+		if m.config.SortContainers {
+			// Sorted once here so every output format, CSV included, lists containers the same way
+			sortContainersByUsage(pods[i].Containers)
 		}
-		return pods[i].PodName < pods[j].PodName
-	})
+	}
+	sortPods(pods, m.config.SortBy, m.config.SortOrder == config.SortOrderDesc)
 
 	report := &MemoryReport{
-		Summary: *summary,
-		Pods:    pods,
+		Summary:      *summary,
+		Pods:         pods,
+		PhaseFilter:  phaseFilter,
+		ExcludedPods: excluded,
+		Clusters:     m.clusterNames(),
+		Cycle:        m.cycle,
+	}
+	if m.config.GroupBy == config.GroupByNode {
+		report.NodeAllocatable = m.collectNodeAllocatable(ctx)
 	}
+	m.logAPICalls()
 
-	if m.config.Output != config.OutputFormatCSV {
+	if m.config.IsTableOutput() {
 		slog.Info("Memory collection completed successfully",
 			"total_pods", summary.TotalPods,
 			"running_pods", summary.RunningPods,
 			"namespaces", summary.NamespaceCount,
+			"collection_duration_ms", summary.Timings.Total.Milliseconds(),
+			"pod_list_duration_ms", summary.Timings.PodList.Milliseconds(),
+			"metrics_duration_ms", summary.Timings.Metrics.Milliseconds(),
 			"target_namespace", m.config.Namespace)
 	}
 
 	return report, nil
 }
 
+// logAPICalls logs the API requests of the cycle across all clusters and resets the counts
+// They are logged at debug level with machine-readable output, like the other progress logs,
+// and added to the totals served on /metrics
+func (m *MemoryMonitor) logAPICalls() {
+	var calls k8s.APICallCounts
+	for _, client := range m.clients() {
+		calls.Add(client.TakeAPICallCounts())
+	}
+	m.apiCalls.add(calls)
+	log := slog.Debug
+	if m.config.IsTableOutput() {
+		log = slog.Info
+	}
+	log("API calls for cycle",
+		"namespace_lists", calls.NamespaceLists,
+		"pod_requests", calls.PodRequests,
+		"pods_listed", calls.PodsListed,
+		"metrics_requests", calls.MetricsRequests,
+		"node_lists", calls.NodeLists,
+		"namespaces_processed", calls.NamespacesProcessed)
+}
+
 // AnalyzeMemoryUsage performs analysis on memory usage and identifies potential issues
 func (m *MemoryMonitor) AnalyzeMemoryUsage(ctx context.Context) (*AnalysisResult, error) {
 	report, err := m.CollectMemoryInfo(ctx)
 	if err != nil {
+		m.session.FailedCycles++
 		return nil, fmt.Errorf("failed to collect memory info for analysis: %w", err)
 	}
+	return m.AnalyzeMemoryReport(report), nil
+}
+
+// AnalyzeMemoryReport analyzes a report as one cycle of the monitor, skipping collection
+// Unlike AnalyzeReport it updates the state kept across cycles (threshold crossings, changed pods
+// and session totals), so a sequence of handcrafted reports exercises the watch analysis without a cluster
+func (m *MemoryMonitor) AnalyzeMemoryReport(report *MemoryReport) *AnalysisResult {
+	analysis := AnalyzeReport(report, m.config)
+	m.trackThresholdCrossings(analysis)
+	m.trackChangedPods(analysis)
+	m.session.record(analysis)
+
+	if m.config.IsTableOutput() {
+		slog.Info("Memory analysis completed",
+			"warning_pods", len(analysis.WarningPods),
+			"high_usage_pods", len(analysis.HighUsagePods),
+			"critical_pods", analysis.Report.Summary.CriticalPods,
+			"pods_over_limit", analysis.Report.Summary.PodsOverLimit,
+			"problems_found", analysis.ProblemCount())
+	}
+
+	return analysis
+}
 
+// analyzeScheduling records pending pods the scheduler could not place
+// Only those requesting memory are reported as problems, since they signal missing capacity
+func (a *AnalysisResult) analyzeScheduling(pod *k8s.PodMemoryInfo) {
+	condition := pod.UnschedulableCondition()
+	if condition == nil {
+		return
+	}
+	a.Unschedulable = append(a.Unschedulable, *pod)
+	if pod.MemoryRequest == nil {
+		return
+	}
+	message := condition.Message
+	if message == "" {
+		message = condition.Reason
+	}
+	a.addPodProblem(pod, newPodProblem(SeverityWarning, ProblemKindUnschedulable, pod.Namespace, pod.PodName,
+		"is unschedulable while requesting %s of memory: %s", k8s.FormatMemory(pod.MemoryRequest), message))
+}
+
+// AnalyzeReport identifies the problems of an already collected report, e.g. one loaded from disk
+// It keeps no state between calls, so threshold crossings and session totals are left to the monitor
+func AnalyzeReport(report *MemoryReport, cfg *config.Config) *AnalysisResult {
 	analysis := &AnalysisResult{
 		Report:        *report,
 		HighUsagePods: []k8s.PodMemoryInfo{},
 		WarningPods:   []k8s.PodMemoryInfo{},
 		ProblemsFound: []string{},
+		Problems:      []Problem{},
+		maxProblems:   cfg.MaxProblems,
+		trimEarly:     cfg.MaxMetricsAge <= 0 && !cfg.DedupeProblems,
 	}
 
+	threshold := cfg.MemoryThresholdQuantity
+
 	// Analyze each pod
 	for i := range report.Pods {
 		pod := &report.Pods[i]
+		analysis.analyzeScheduling(pod)
+
 		// Skip pods without current usage data
 		if pod.CurrentUsage == nil {
 			continue
@@ -124,105 +311,304 @@ func (m *MemoryMonitor) AnalyzeMemoryUsage(ctx context.Context) (*AnalysisResult
 		// Calculate percentages
 		pod.CalculateUsagePercent()
 
-		// Check for high usage against requests
-		if pod.UsagePercent != nil && *pod.UsagePercent >= m.config.MemoryWarningPercent {
+		// Check for usage over the warning threshold, against requests unless limits are the primary metric
+		if isWarning(pod, cfg) {
 			analysis.WarningPods = append(analysis.WarningPods, *pod)
+		}
 
-			if *pod.UsagePercent >= 95.0 {
-				analysis.HighUsagePods = append(analysis.HighUsagePods, *pod)
-				analysis.ProblemsFound = append(analysis.ProblemsFound,
-					fmt.Sprintf("Pod %s/%s is using %.1f%% of its memory request",
-						pod.Namespace, pod.PodName, *pod.UsagePercent))
-			}
+		// Check for high usage against requests
+		if overWarningThreshold(pod.UsagePercent, cfg) && *pod.UsagePercent >= criticalRequestPercent {
+			analysis.HighUsagePods = append(analysis.HighUsagePods, *pod)
+			analysis.addPodProblem(pod, newPodProblem(SeverityCritical, ProblemKindRequestUsage, pod.Namespace, pod.PodName,
+				"is using %.1f%% of its memory request", *pod.UsagePercent))
 		}
 
 		// Check for high usage against limits
-		if pod.LimitUsagePercent != nil && *pod.LimitUsagePercent >= 90.0 {
+		if pod.LimitUsagePercent != nil && *pod.LimitUsagePercent >= criticalLimitPercent {
 			analysis.HighUsagePods = append(analysis.HighUsagePods, *pod)
-			analysis.ProblemsFound = append(analysis.ProblemsFound,
-				fmt.Sprintf("Pod %s/%s is using %.1f%% of its memory limit",
-					pod.Namespace, pod.PodName, *pod.LimitUsagePercent))
+			analysis.addPodProblem(pod, newPodLimitProblem(pod.Namespace, pod.PodName, *pod.LimitUsagePercent))
+		}
+
+		// Check for usage above the absolute threshold, whatever the pod's requests and limits
+		if threshold != nil && pod.CurrentUsage.Cmp(*threshold) > 0 {
+			analysis.addPodProblem(pod, newPodProblem(SeverityWarning, ProblemKindAbsoluteThreshold, pod.Namespace, pod.PodName,
+				"is using %s, above the %s memory threshold", k8s.FormatMemory(pod.CurrentUsage), k8s.FormatMemory(threshold)))
 		}
 
 		// Check for pods without memory limits
 		if pod.MemoryLimit == nil {
-			analysis.ProblemsFound = append(analysis.ProblemsFound,
-				fmt.Sprintf("Pod %s/%s has no memory limit defined",
-					pod.Namespace, pod.PodName))
+			analysis.addPodProblem(pod, newPodProblem(SeverityWarning, ProblemKindNoLimit, pod.Namespace, pod.PodName,
+				"has no memory limit defined"))
 		}
 
 		// Check for pods without memory requests
 		if pod.MemoryRequest == nil {
-			analysis.ProblemsFound = append(analysis.ProblemsFound,
-				fmt.Sprintf("Pod %s/%s has no memory request defined",
-					pod.Namespace, pod.PodName))
+			analysis.addPodProblem(pod, newPodProblem(SeverityWarning, ProblemKindNoRequest, pod.Namespace, pod.PodName,
+				"has no memory request defined"))
 		}
 	}
 
+	analysis.analyzeNodeOvercommit()
+
+	if cfg.SuggestRequests {
+		applySuggestedRequests(analysis.Report.Pods, cfg.SuggestFactor, cfg.SuggestRoundToBytes())
+	}
+
 	// Include container-level findings
-	containerAnalysis := analyzeReport(&analysis.Report, m.config)
-	analysis.ProblemsFound = append(analysis.ProblemsFound, containerAnalysis.ProblemsFound...)
+	analysis.analyzeContainers(cfg)
+	analysis.deprioritizeStaleMetrics(cfg.MaxMetricsAge)
+	if cfg.DedupeProblems {
+		analysis.dedupeProblems()
+	}
+	analysis.sortProblems()
+	analysis.capProblems()
+	analysis.updateRiskCounts()
 
-	if m.config.Output != config.OutputFormatCSV {
-		slog.Info("Memory analysis completed",
-			"warning_pods", len(analysis.WarningPods),
-			"high_usage_pods", len(analysis.HighUsagePods),
-			"problems_found", len(analysis.ProblemsFound))
+	return analysis
+}
+
+// NotifyCritical posts critical problems for pods that were not critical in the previous cycle
+// Pods that recover are forgotten so they are reported again if they turn critical later
+func (m *MemoryMonitor) NotifyCritical(ctx context.Context, analysis *AnalysisResult) {
+	if m.slack == nil {
+		return
 	}
 
-	return analysis, nil
+	current := map[string]bool{}
+	newPods := map[string]bool{}
+	var newProblems []Problem
+	for _, p := range analysis.Problems {
+		if p.Severity != SeverityCritical {
+			continue
+		}
+		key := criticalPodKey(p)
+		current[key] = true
+		if !m.notifiedPods[key] {
+			newPods[key] = true
+			newProblems = append(newProblems, p)
+		}
+	}
+
+	if len(newProblems) > 0 {
+		if err := m.slack.Notify(ctx, newProblems); err != nil {
+			slog.Warn("Failed to send Slack notification", "error", err)
+			// Leave the pods unmarked so the next cycle retries them
+			for key := range newPods {
+				delete(current, key)
+			}
+		}
+	}
+	m.notifiedPods = current
 }
 
-func analyzeReport(report *MemoryReport, cfg *config.Config) *AnalysisResult {
-	analysis := &AnalysisResult{
-		Report:        *report,
-		HighUsagePods: []k8s.PodMemoryInfo{},
-		WarningPods:   []k8s.PodMemoryInfo{},
-		ProblemsFound: []string{},
+// WriteOutputFile writes the analysis to the report file in the configured CSV or JSON format
+// The file is rotated first when due; a CSV header starts every new file
+func (m *MemoryMonitor) WriteOutputFile(analysis *AnalysisResult) error {
+	if m.outputFile == nil {
+		return nil
+	}
+	if _, err := m.outputFile.RotateIfDue(); err != nil {
+		return err
 	}
 
-	for i := range report.Pods {
-		pod := &report.Pods[i]
+	if m.config.Output == config.OutputFormatCSV {
+		formatter := &CSVFormatter{writer: csv.NewWriter(m.outputFile)}
+		formatter.FormatReport(&analysis.Report, m.config, m.outputFile.Size() == 0)
+		if err := formatter.writer.Error(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return nil
+	}
+	newJSONFormatterTo(m.outputFile).FormatAnalysis(analysis, m.config)
+	return nil
+}
+
+// Close releases the report file, if any
+func (m *MemoryMonitor) Close() error {
+	if m.outputFile == nil {
+		return nil
+	}
+	return m.outputFile.Close()
+}
+
+// PublishWebhook posts the analysis to the configured webhook, logging any failure
+func (m *MemoryMonitor) PublishWebhook(ctx context.Context, analysis *AnalysisResult) {
+	if m.webhook == nil {
+		return
+	}
+	if err := m.webhook.Send(ctx, analysis, m.config); err != nil {
+		slog.Warn("Failed to publish report to webhook", "error", err)
+	}
+}
+
+// Session returns the totals accumulated across the cycles run so far
+func (m *MemoryMonitor) Session() *SessionStats {
+	return m.session
+}
+
+// SetClock replaces the time source of the monitor and its Kubernetes client
+// The session restarts at the clock's current time so durations stay consistent
+func (m *MemoryMonitor) SetClock(clock k8s.Clock) {
+	m.clock = clock
+	for _, client := range m.clients() {
+		client.SetClock(clock)
+	}
+	m.session = newSessionStats(clock.Now())
+}
+
+// Timestamp returns the current time from the monitor's clock in the configured timestamp format
+func (m *MemoryMonitor) Timestamp() string {
+	return formatTimestamp(m.Now(), m.config)
+}
+
+// Now returns the current time from the monitor's clock
+func (m *MemoryMonitor) Now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock.Now()
+}
+
+// filterPodsByPhase keeps only pods whose phase is in phases
+func filterPodsByPhase(pods []k8s.PodMemoryInfo, phases []string) []k8s.PodMemoryInfo {
+	included := make(map[string]bool, len(phases))
+	for _, phase := range phases {
+		included[phase] = true
+	}
+	filtered := pods[:0]
+	for i := range pods {
+		if included[pods[i].Phase] {
+			filtered = append(filtered, pods[i])
+		}
+	}
+	return filtered
+}
+
+// filterPodsByExcludedLabels drops pods carrying any of the "key=value" labels
+func filterPodsByExcludedLabels(pods []k8s.PodMemoryInfo, labels []string) []k8s.PodMemoryInfo {
+	filtered := pods[:0]
+	for i := range pods {
+		if !hasAnyLabel(pods[i].Labels, labels) {
+			filtered = append(filtered, pods[i])
+		}
+	}
+	return filtered
+}
+
+// hasAnyLabel reports whether podLabels contains any of the "key=value" labels
+func hasAnyLabel(podLabels map[string]string, labels []string) bool {
+	for _, label := range labels {
+		key, value, _ := strings.Cut(label, "=")
+		if v, ok := podLabels[strings.TrimSpace(key)]; ok && v == strings.TrimSpace(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// setContainerUsageShares records each container's percent of the pod's total usage
+// Shares are only meaningful with several containers and are left unset when the pod total is unknown or zero
+func setContainerUsageShares(pod *k8s.PodMemoryInfo) {
+	if len(pod.Containers) < 2 || pod.CurrentUsage == nil || pod.CurrentUsage.Value() <= 0 {
+		return
+	}
+	total := float64(pod.CurrentUsage.Value())
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		if c.CurrentUsage == nil {
+			continue
+		}
+		share := float64(c.CurrentUsage.Value()) / total * 100
+		c.PodUsageShare = &share
+	}
+}
+
+// sortPods orders pods by namespace and name, or by headroom when requested, ascending unless descending is set
+// Pods without a known headroom are placed last
+func sortPods(pods []k8s.PodMemoryInfo, sortBy string, descending bool) {
+	byName := func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		if pods[i].PodName != pods[j].PodName {
+			return pods[i].PodName < pods[j].PodName
+		}
+		return pods[i].Cluster < pods[j].Cluster
+	}
+
+	if sortBy != config.SortByHeadroom {
+		sort.Slice(pods, func(i, j int) bool {
+			if descending {
+				return byName(j, i)
+			}
+			return byName(i, j)
+		})
+		return
+	}
+
+	// Pods without headroom stay last in both directions; ties keep name order
+	sort.SliceStable(pods, func(i, j int) bool {
+		hi, hj := pods[i].Headroom, pods[j].Headroom
+		switch {
+		case hi == nil && hj == nil:
+			return byName(i, j)
+		case hi == nil || hj == nil:
+			return hj == nil
+		case hi.Cmp(*hj) != 0:
+			return (hi.Cmp(*hj) < 0) != descending
+		default:
+			return byName(i, j)
+		}
+	})
+}
+
+// sortContainersByUsage orders containers by current usage, largest first
+// Containers without usage go last, keeping their relative order
+func sortContainersByUsage(containers []k8s.ContainerMemoryInfo) {
+	sort.SliceStable(containers, func(i, j int) bool {
+		ui, uj := containers[i].CurrentUsage, containers[j].CurrentUsage
+		if ui == nil || uj == nil {
+			return ui != nil && uj == nil
+		}
+		return ui.Cmp(*uj) > 0
+	})
+}
+
+// analyzeContainers adds the problems found in each pod's containers
+func (a *AnalysisResult) analyzeContainers(cfg *config.Config) {
+	for i := range a.Report.Pods {
+		pod := &a.Report.Pods[i]
 
 		// Analyze per-container first
 		for _, c := range pod.Containers {
 			c.CalculateUsagePercent()
 
-			if c.LimitUsagePercent != nil && *c.LimitUsagePercent >= 90.0 {
-				analysis.ProblemsFound = append(analysis.ProblemsFound,
-					fmt.Sprintf(
-						"Pod %s/%s container %s is using %.1f%% of its memory limit",
-						pod.Namespace,
-						pod.PodName,
-						c.ContainerName,
-						*c.LimitUsagePercent,
-					),
-				)
+			if c.LimitUsagePercent != nil && *c.LimitUsagePercent >= criticalLimitPercent {
+				a.addPodProblem(pod, newContainerLimitProblem(pod.Namespace, pod.PodName, c.ContainerName, *c.LimitUsagePercent))
 			}
 
-			if c.UsagePercent != nil && *c.UsagePercent >= cfg.MemoryWarningPercent {
-				analysis.ProblemsFound = append(analysis.ProblemsFound,
-					fmt.Sprintf(
-						"Pod %s/%s container %s is using %.1f%% of its memory request",
-						pod.Namespace,
-						pod.PodName,
-						c.ContainerName,
-						*c.UsagePercent,
-					),
-				)
+			switch {
+			case !cfg.LimitIsPrimary() && overWarningThreshold(c.UsagePercent, cfg):
+				a.addPodProblem(pod, newContainerProblem(SeverityWarning, ProblemKindRequestUsage,
+					pod.Namespace, pod.PodName, c.ContainerName,
+					"is using %.1f%% of its memory request", *c.UsagePercent))
+			case cfg.LimitIsPrimary() && overWarningThreshold(c.LimitUsagePercent, cfg) &&
+				*c.LimitUsagePercent < criticalLimitPercent:
+				// Usage at the critical limit percentage is already reported above
+				a.addPodProblem(pod, newContainerProblem(SeverityWarning, ProblemKindLimitUsage,
+					pod.Namespace, pod.PodName, c.ContainerName,
+					"is using %.1f%% of its memory limit", *c.LimitUsagePercent))
 			}
 
 			if c.MemoryLimit == nil {
-				analysis.ProblemsFound = append(analysis.ProblemsFound,
-					fmt.Sprintf("Pod %s/%s container %s has no memory limit defined", pod.Namespace, pod.PodName, c.ContainerName))
+				a.addPodProblem(pod, newContainerProblem(SeverityWarning, ProblemKindNoLimit,
+					pod.Namespace, pod.PodName, c.ContainerName, "has no memory limit defined"))
 			}
 
 			if c.MemoryRequest == nil {
-				analysis.ProblemsFound = append(analysis.ProblemsFound,
-					fmt.Sprintf("Pod %s/%s container %s has no memory request defined", pod.Namespace, pod.PodName, c.ContainerName))
+				a.addPodProblem(pod, newContainerProblem(SeverityWarning, ProblemKindNoRequest,
+					pod.Namespace, pod.PodName, c.ContainerName, "has no memory request defined"))
 			}
 		}
 	}
-
-	return analysis
 }
@@ -1,14 +1,153 @@
 package monitor
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/eduardoferro/k8s-memory-watch/internal/config"
 	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 )
 
+// testPod describes a single-container pod and its current usage for newTestMonitor
+type testPod struct {
+	namespace, name       string
+	usage, request, limit string
+	phase                 corev1.PodPhase // defaults to Running
+	labels                map[string]string
+}
+
+// newTestMonitor builds a monitor backed by fake clientsets serving the given pods and metrics
+func newTestMonitor(cfg *config.Config, pods ...testPod) *MemoryMonitor {
+	return &MemoryMonitor{
+		k8sClient:    newTestClient(pods...),
+		config:       cfg,
+		notifiedPods: map[string]bool{},
+		session:      newSessionStats(time.Now()),
+	}
+}
+
+// newTestClient builds a client backed by fake clientsets serving the given pods and metrics
+func newTestClient(pods ...testPod) *k8s.Client {
+	var objects []runtime.Object
+	metrics := &metricsv1beta1.PodMetricsList{}
+	for _, p := range pods {
+		resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+		if p.request != "" {
+			resources.Requests[corev1.ResourceMemory] = resource.MustParse(p.request)
+		}
+		if p.limit != "" {
+			resources.Limits[corev1.ResourceMemory] = resource.MustParse(p.limit)
+		}
+		phase := p.phase
+		if phase == "" {
+			phase = corev1.PodRunning
+		}
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace, Labels: p.labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: resources}}},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		})
+		if p.usage != "" {
+			metrics.Items = append(metrics.Items, metricsv1beta1.PodMetrics{
+				ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace},
+				Containers: []metricsv1beta1.ContainerMetrics{
+					{Name: "app", Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(p.usage)}},
+				},
+			})
+		}
+	}
+
+	// The fake metrics tracker registers PodMetrics under a resource name that List does not query
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, metrics, nil
+	})
+
+	return k8s.NewClientWithInterfaces(fake.NewSimpleClientset(objects...), metricsClient)
+}
+
+// testMonitorConfig returns a config monitoring the "prod" namespace, suitable for newTestMonitor
+func testMonitorConfig() *config.Config {
+	return &config.Config{
+		Namespace:            "prod",
+		MemoryWarningPercent: 80.0,
+		Output:               config.OutputFormatJSON,
+		SortBy:               config.SortByName,
+	}
+}
+
+func TestAnalyzeMemoryUsage_UsageAboveLimit(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(),
+		testPod{namespace: "prod", name: "over", usage: "530Mi", request: "512Mi", limit: "512Mi"},
+		testPod{namespace: "prod", name: "close", usage: "480Mi", request: "1Gi", limit: "512Mi"},
+	)
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+
+	kinds := map[string]string{}
+	for _, p := range analysis.Problems {
+		if p.Container == "" && p.Severity == SeverityCritical && p.Kind != ProblemKindRequestUsage {
+			kinds[p.PodName] = p.Kind
+		}
+	}
+	if kinds["over"] != ProblemKindOverLimit {
+		t.Errorf("expected %s for pod above its limit, got %q", ProblemKindOverLimit, kinds["over"])
+	}
+	if kinds["close"] != ProblemKindLimitUsage {
+		t.Errorf("expected %s for pod near its limit, got %q", ProblemKindLimitUsage, kinds["close"])
+	}
+
+	joined := strings.Join(analysis.ProblemsFound, "\n")
+	if !strings.Contains(joined, "Pod prod/over is using 103.5% of its memory limit and has exceeded it") {
+		t.Errorf("expected distinct over-limit message, got:\n%s", joined)
+	}
+}
+
+func TestAnalyzeMemoryUsage_AbsoluteThreshold(t *testing.T) {
+	cfg := testMonitorConfig()
+	threshold := resource.MustParse("1Gi")
+	cfg.MemoryThreshold = "1Gi"
+	cfg.MemoryThresholdQuantity = &threshold
+	m := newTestMonitor(cfg,
+		testPod{namespace: "prod", name: "big", usage: "1500Mi", request: "4Gi", limit: "8Gi"},
+		testPod{namespace: "prod", name: "small", usage: "200Mi", request: "4Gi", limit: "8Gi"},
+	)
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+
+	var flagged []string
+	for _, p := range analysis.Problems {
+		if p.Kind == ProblemKindAbsoluteThreshold {
+			flagged = append(flagged, p.PodName)
+			if p.Severity != SeverityWarning {
+				t.Errorf("expected a warning, got %s", p.Severity)
+			}
+		}
+	}
+	if len(flagged) != 1 || flagged[0] != "big" {
+		t.Errorf("expected only the pod above 1Gi to be flagged despite its limit, got %v", flagged)
+	}
+}
+
 func TestAnalyzeReport_PerContainerMessages(t *testing.T) {
 	cfg := &config.Config{MemoryWarningPercent: 80.0}
 
@@ -36,7 +175,7 @@ func TestAnalyzeReport_PerContainerMessages(t *testing.T) {
 		},
 	}
 
-	analysis := analyzeReport(report, cfg)
+	analysis := AnalyzeReport(report, cfg)
 	joined := strings.Join(analysis.ProblemsFound, "\n")
 	if !strings.Contains(joined, "Pod ns/p container a is using") {
 		t.Fatalf("expected over-limit message for container a, got: %s", joined)
@@ -45,3 +184,321 @@ func TestAnalyzeReport_PerContainerMessages(t *testing.T) {
 		t.Fatalf("expected missing limit message for container b, got: %s", joined)
 	}
 }
+
+func TestAnalyzeReport_LimitAsPrimaryMetric(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80.0, PrimaryMetric: config.PrimaryMetricLimit}
+	report := &MemoryReport{
+		Pods: []k8s.PodMemoryInfo{
+			{
+				Namespace: "ns",
+				PodName:   "p",
+				Containers: []k8s.ContainerMemoryInfo{
+					{
+						ContainerName: "a",
+						CurrentUsage:  resource.NewQuantity(1024*1024*430, resource.BinarySI), // ~84% of limit
+						MemoryRequest: resource.NewQuantity(1024*1024*512, resource.BinarySI),
+						MemoryLimit:   resource.NewQuantity(1024*1024*512, resource.BinarySI),
+					},
+					{
+						ContainerName: "b",
+						CurrentUsage:  resource.NewQuantity(1024*1024*300, resource.BinarySI), // 150% of request
+						MemoryRequest: resource.NewQuantity(1024*1024*200, resource.BinarySI),
+						MemoryLimit:   resource.NewQuantity(1024*1024*1024, resource.BinarySI),
+					},
+				},
+			},
+		},
+	}
+
+	analysis := AnalyzeReport(report, cfg)
+	joined := strings.Join(analysis.ProblemsFound, "\n")
+	if !strings.Contains(joined, "container a is using 84.0% of its memory limit") {
+		t.Errorf("expected a limit warning for container a, got: %s", joined)
+	}
+	if strings.Contains(joined, "container b is using") {
+		t.Errorf("usage vs request should not warn with the limit as primary metric, got: %s", joined)
+	}
+}
+
+func TestSortContainersByUsage_LargestFirstNilLast(t *testing.T) {
+	containers := []k8s.ContainerMemoryInfo{
+		{ContainerName: "init-less"},
+		{ContainerName: "sidecar", CurrentUsage: qty(20)},
+		{ContainerName: "app", CurrentUsage: qty(300)},
+		{ContainerName: "unknown"},
+		{ContainerName: "proxy", CurrentUsage: qty(50)},
+	}
+
+	sortContainersByUsage(containers)
+
+	expected := []string{"app", "proxy", "sidecar", "init-less", "unknown"}
+	for i, name := range expected {
+		if containers[i].ContainerName != name {
+			t.Fatalf("position %d: expected %s, got %s", i, name, containers[i].ContainerName)
+		}
+	}
+}
+
+func TestSortPods_ByHeadroomAscending(t *testing.T) {
+	pods := []k8s.PodMemoryInfo{
+		{Namespace: "a", PodName: "roomy", Headroom: resource.NewQuantity(500, resource.BinarySI)},
+		{Namespace: "a", PodName: "unknown"},
+		{Namespace: "b", PodName: "over", Headroom: resource.NewQuantity(-10, resource.BinarySI)},
+		{Namespace: "c", PodName: "tight", Headroom: resource.NewQuantity(20, resource.BinarySI)},
+	}
+
+	sortPods(pods, config.SortByHeadroom, false)
+
+	expected := []string{"over", "tight", "roomy", "unknown"}
+	for i, name := range expected {
+		if pods[i].PodName != name {
+			t.Fatalf("position %d: expected %s, got %s", i, name, pods[i].PodName)
+		}
+	}
+}
+
+func TestSortPods_Descending(t *testing.T) {
+	tests := []struct {
+		sortBy   string
+		expected []string
+	}{
+		{config.SortByHeadroom, []string{"roomy", "tight", "over", "none", "unknown"}},
+		{config.SortByName, []string{"tight", "over", "unknown", "roomy", "none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			pods := []k8s.PodMemoryInfo{
+				{Namespace: "a", PodName: "roomy", Headroom: resource.NewQuantity(500, resource.BinarySI)},
+				{Namespace: "a", PodName: "unknown"},
+				{Namespace: "a", PodName: "none"},
+				{Namespace: "b", PodName: "over", Headroom: resource.NewQuantity(-10, resource.BinarySI)},
+				{Namespace: "c", PodName: "tight", Headroom: resource.NewQuantity(20, resource.BinarySI)},
+			}
+
+			sortPods(pods, tt.sortBy, true)
+
+			for i, name := range tt.expected {
+				if pods[i].PodName != name {
+					t.Fatalf("position %d: expected %s, got %s", i, name, pods[i].PodName)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeReport_ContainerAboveLimit(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80.0}
+	report := &MemoryReport{
+		Pods: []k8s.PodMemoryInfo{
+			{
+				Namespace: "ns",
+				PodName:   "p",
+				Containers: []k8s.ContainerMemoryInfo{
+					{
+						ContainerName: "app",
+						CurrentUsage:  resource.NewQuantity(1024*1024*600, resource.BinarySI),
+						MemoryRequest: resource.NewQuantity(1024*1024*600, resource.BinarySI),
+						MemoryLimit:   resource.NewQuantity(1024*1024*512, resource.BinarySI),
+					},
+				},
+			},
+		},
+	}
+
+	analysis := AnalyzeReport(report, cfg)
+	for _, p := range analysis.Problems {
+		if p.Kind == ProblemKindLimitUsage {
+			t.Fatalf("expected the over-limit problem to replace the 90%% one, got %+v", p)
+		}
+	}
+	joined := strings.Join(analysis.ProblemsFound, "\n")
+	if !strings.Contains(joined, "Pod ns/p container app is using 117.2% of its memory limit and has exceeded it") {
+		t.Fatalf("expected over-limit container message, got: %s", joined)
+	}
+}
+
+func TestAnalyzeMemoryUsage_PopulatesRiskCounts(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(),
+		testPod{namespace: "prod", name: "over", usage: "530Mi", request: "512Mi", limit: "512Mi"},
+		testPod{namespace: "prod", name: "warm", usage: "850Mi", request: "1Gi", limit: "2Gi"},
+		testPod{namespace: "prod", name: "idle", usage: "10Mi", request: "1Gi", limit: "2Gi"},
+	)
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+	}
+
+	summary := analysis.Report.Summary
+	if summary.CriticalPods != 1 || summary.WarningPodsCount != 2 || summary.PodsOverLimit != 1 {
+		t.Errorf("expected 1 critical, 2 warning and 1 over limit, got %d, %d and %d",
+			summary.CriticalPods, summary.WarningPodsCount, summary.PodsOverLimit)
+	}
+}
+
+func TestCollectMemoryInfo_FiltersPhasesButKeepsSummary(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.IncludePhases = []string{config.PhaseRunning, config.PhasePending}
+	m := newTestMonitor(cfg,
+		testPod{namespace: "prod", name: "api", usage: "100Mi"},
+		testPod{namespace: "prod", name: "job-done", phase: corev1.PodSucceeded},
+		testPod{namespace: "prod", name: "job-oom", phase: corev1.PodFailed},
+	)
+
+	report, err := m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	if len(report.Pods) != 1 || report.Pods[0].PodName != "api" {
+		t.Fatalf("expected only the running pod to be listed, got %+v", report.Pods)
+	}
+	if report.Summary.TotalPods != 3 {
+		t.Errorf("expected summary to still count 3 pods, got %d", report.Summary.TotalPods)
+	}
+
+	output := captureStdout(t, func() { report.PrintSummary(cfg) })
+	if !strings.Contains(output, "Pods Shown: 1 (phases: Running, Pending)") {
+		t.Errorf("expected shown-pods note in summary, got:\n%s", output)
+	}
+
+	cfg.IncludePhases = []string{config.PhaseFailed}
+	report, err = m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	if len(report.Pods) != 1 || report.Pods[0].PodName != "job-oom" {
+		t.Fatalf("expected only the failed pod to be listed, got %+v", report.Pods)
+	}
+}
+
+func TestCollectMemoryInfo_ExcludesLabelsButKeepsSummary(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.ExcludeLabels = []string{"monitoring.io/ignore=true", "tier=batch"}
+	m := newTestMonitor(cfg,
+		testPod{namespace: "prod", name: "api", usage: "100Mi", labels: map[string]string{"tier": "web"}},
+		testPod{namespace: "prod", name: "ignored", usage: "50Mi", labels: map[string]string{"monitoring.io/ignore": "true"}},
+		testPod{namespace: "prod", name: "not-ignored", usage: "50Mi", labels: map[string]string{"monitoring.io/ignore": "false"}},
+		testPod{namespace: "prod", name: "cron", usage: "20Mi", labels: map[string]string{"tier": "batch"}},
+	)
+
+	report, err := m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	if len(report.Pods) != 2 || report.Pods[0].PodName != "api" || report.Pods[1].PodName != "not-ignored" {
+		t.Fatalf("expected api and not-ignored to be listed, got %+v", report.Pods)
+	}
+	if report.ExcludedPods != 2 {
+		t.Errorf("expected 2 excluded pods, got %d", report.ExcludedPods)
+	}
+	if report.Summary.TotalPods != 4 || report.Summary.TotalMemoryUsage.Value() != 220*mi {
+		t.Errorf("expected summary to cover all pods, got %d pods using %s",
+			report.Summary.TotalPods, report.Summary.TotalMemoryUsage.String())
+	}
+
+	output := captureStdout(t, func() { report.PrintSummary(cfg) })
+	if !strings.Contains(output, "Pods Excluded by Label: 2") {
+		t.Errorf("expected excluded-pods note in summary, got:\n%s", output)
+	}
+}
+
+func TestHasAnyLabel(t *testing.T) {
+	labels := []string{"monitoring.io/ignore=true", "tier=batch"}
+	tests := []struct {
+		name      string
+		podLabels map[string]string
+		want      bool
+	}{
+		{"first label matches", map[string]string{"monitoring.io/ignore": "true"}, true},
+		{"second label matches", map[string]string{"tier": "batch", "app": "cron"}, true},
+		{"value differs", map[string]string{"monitoring.io/ignore": "false"}, false},
+		{"key missing", map[string]string{"app": "web"}, false},
+		{"no labels", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasAnyLabel(tt.podLabels, labels); got != tt.want {
+				t.Errorf("hasAnyLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectMemoryInfo_RecordsCollectionDuration(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(), testPod{namespace: "prod", name: "api", usage: "100Mi"})
+
+	report, err := m.CollectMemoryInfo(context.Background())
+	if err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	timings := report.Summary.Timings
+	if timings.Total <= 0 || timings.Total < timings.PodList {
+		t.Errorf("expected total duration covering the pod list, got %+v", timings)
+	}
+
+	output := captureStdout(t, func() { report.PrintSummary(m.config) })
+	if !strings.Contains(output, "Collection Time:") {
+		t.Errorf("expected collection time in summary, got:\n%s", output)
+	}
+}
+
+func TestSetContainerUsageShares(t *testing.T) {
+	twoContainers := func(total *resource.Quantity) k8s.PodMemoryInfo {
+		return k8s.PodMemoryInfo{CurrentUsage: total, Containers: []k8s.ContainerMemoryInfo{
+			{ContainerName: "app", CurrentUsage: qty(60 * mi)},
+			{ContainerName: "sidecar"},
+		}}
+	}
+
+	pod := twoContainers(qty(150 * mi))
+	setContainerUsageShares(&pod)
+	if share := pod.Containers[0].PodUsageShare; share == nil || *share != 40 {
+		t.Errorf("expected app to use 40%% of the pod, got %v", share)
+	}
+	if pod.Containers[1].PodUsageShare != nil {
+		t.Errorf("expected no share for a container without usage")
+	}
+	if out := formatContainerSection(pod.Containers, &config.Config{}); !strings.Contains(out, "Usage: 60.0 MB (40% of pod)") {
+		t.Errorf("expected the share in the container section, got: %s", out)
+	}
+
+	for name, total := range map[string]*resource.Quantity{"nil total": nil, "zero total": qty(0)} {
+		pod := twoContainers(total)
+		setContainerUsageShares(&pod)
+		if pod.Containers[0].PodUsageShare != nil {
+			t.Errorf("%s: expected no share, got %v", name, *pod.Containers[0].PodUsageShare)
+		}
+	}
+
+	single := k8s.PodMemoryInfo{CurrentUsage: qty(60 * mi), Containers: []k8s.ContainerMemoryInfo{{CurrentUsage: qty(60 * mi)}}}
+	setContainerUsageShares(&single)
+	if single.Containers[0].PodUsageShare != nil {
+		t.Errorf("expected no share for single-container pods")
+	}
+}
+
+// fixedClock always reports the same time
+type fixedClock struct {
+	t time.Time
+}
+
+func (f fixedClock) Now() time.Time {
+	return f.t
+}
+
+func TestMemoryMonitor_SetClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := newTestMonitor(testMonitorConfig(), testPod{namespace: "prod", name: "app", usage: "50Mi", request: "100Mi", limit: "200Mi"})
+	m.SetClock(fixedClock{now})
+
+	analysis, err := m.AnalyzeMemoryUsage(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !analysis.Report.Summary.Timestamp.Equal(now) {
+		t.Errorf("expected report timestamp %v, got %v", now, analysis.Report.Summary.Timestamp)
+	}
+	if !m.Session().Started.Equal(now) || !m.Now().Equal(now) {
+		t.Errorf("expected the session to start at %v, got %v", now, m.Session().Started)
+	}
+}
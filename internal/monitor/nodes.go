@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// unscheduledNode is the bucket for pods not yet assigned to a node
+const unscheduledNode = "(unscheduled)"
+
+// maxOvercommittedNodes caps how many request-overcommitted nodes the node report lists
+const maxOvercommittedNodes = 5
+
+// NodeUsage aggregates the memory of the listed pods running on one node
+type NodeUsage struct {
+	Cluster        string             `json:"cluster,omitempty"` // Context of the node, multi-cluster runs only
+	Node           string             `json:"node"`
+	Pods           int                `json:"pods"`
+	TotalUsage     resource.Quantity  `json:"total_usage"`
+	TotalRequest   resource.Quantity  `json:"total_request"`
+	TotalLimit     resource.Quantity  `json:"total_limit"`
+	Allocatable    *resource.Quantity `json:"allocatable,omitempty"`     // nil when unknown or unscheduled
+	UsagePercent   *float64           `json:"usage_percent,omitempty"`   // Usage vs allocatable
+	RequestPercent *float64           `json:"request_percent,omitempty"` // Requests vs allocatable
+	LimitPercent   *float64           `json:"limit_percent,omitempty"`   // Limits vs allocatable
+}
+
+// nodeKey identifies a node across clusters, as cluster/node when the cluster is known
+// It keys NodeAllocatable, so same-named nodes of different clusters are kept apart
+func nodeKey(cluster, node string) string {
+	if cluster == "" {
+		return node
+	}
+	return cluster + "/" + node
+}
+
+// Name returns the node name, prefixed with its cluster when it has one
+func (n *NodeUsage) Name() string {
+	return nodeKey(n.Cluster, n.Node)
+}
+
+// IsOvercommitted reports whether the requests or limits of the node's pods exceed its allocatable memory
+func (n *NodeUsage) IsOvercommitted() bool {
+	return (n.RequestPercent != nil && *n.RequestPercent > 100) || (n.LimitPercent != nil && *n.LimitPercent > 100)
+}
+
+// RequestsOvercommitted reports whether the requests of the node's pods exceed its allocatable memory
+func (n *NodeUsage) RequestsOvercommitted() bool {
+	return n.RequestPercent != nil && *n.RequestPercent > 100
+}
+
+// RequestOvercommittedNodes returns the nodes whose pod requests exceed allocatable, most overcommitted first
+// It is empty unless node allocatable memory was collected
+func (r *MemoryReport) RequestOvercommittedNodes() []NodeUsage {
+	var overcommitted []NodeUsage
+	for _, n := range r.NodeUsages() {
+		if n.RequestsOvercommitted() {
+			overcommitted = append(overcommitted, n)
+		}
+	}
+	sort.SliceStable(overcommitted, func(i, j int) bool {
+		return *overcommitted[i].RequestPercent > *overcommitted[j].RequestPercent
+	})
+	return overcommitted
+}
+
+// analyzeNodeOvercommit reports nodes whose pods request more memory than the node can allocate
+func (a *AnalysisResult) analyzeNodeOvercommit() {
+	for _, n := range a.Report.RequestOvercommittedNodes() {
+		p := newNodeProblem(SeverityWarning, ProblemKindNodeOvercommitted, n.Name(),
+			"has %.1f%% of its allocatable memory requested (%s of %s across %d pods)",
+			*n.RequestPercent, k8s.FormatMemory(&n.TotalRequest), k8s.FormatMemory(n.Allocatable), n.Pods)
+		p.Cluster, p.Node = n.Cluster, n.Node
+		a.addProblem(p)
+	}
+}
+
+// NodeUsages sums usage, requests and limits of the listed pods per node of each cluster
+// Nodes are ordered by cluster and name, with unscheduled pods last
+func (r *MemoryReport) NodeUsages() []NodeUsage {
+	byNode := make(map[string]*NodeUsage)
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		node := pod.NodeName
+		if node == "" {
+			node = unscheduledNode
+		}
+		key := nodeKey(pod.Cluster, node)
+		usage, ok := byNode[key]
+		if !ok {
+			usage = &NodeUsage{Cluster: pod.Cluster, Node: node}
+			byNode[key] = usage
+		}
+		usage.Pods++
+		if pod.CurrentUsage != nil {
+			usage.TotalUsage.Add(*pod.CurrentUsage)
+		}
+		if pod.MemoryRequest != nil {
+			usage.TotalRequest.Add(*pod.MemoryRequest)
+		}
+		if pod.MemoryLimit != nil {
+			usage.TotalLimit.Add(*pod.MemoryLimit)
+		}
+	}
+
+	result := make([]NodeUsage, 0, len(byNode))
+	for key, usage := range byNode {
+		if allocatable, ok := r.NodeAllocatable[key]; ok && allocatable.Value() > 0 {
+			usage.Allocatable = &allocatable
+			usage.UsagePercent = percentOf(&usage.TotalUsage, &allocatable)
+			usage.RequestPercent = percentOf(&usage.TotalRequest, &allocatable)
+			usage.LimitPercent = percentOf(&usage.TotalLimit, &allocatable)
+		}
+		result = append(result, *usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cluster != result[j].Cluster {
+			return result[i].Cluster < result[j].Cluster
+		}
+		if (result[i].Node == unscheduledNode) != (result[j].Node == unscheduledNode) {
+			return result[j].Node == unscheduledNode
+		}
+		return result[i].Node < result[j].Node
+	})
+	return result
+}
+
+// percentOf returns value as a percentage of total
+func percentOf(value, total *resource.Quantity) *float64 {
+	percent := float64(value.Value()) / float64(total.Value()) * 100
+	return &percent
+}
+
+// PrintNodeReport prints per-node memory totals against allocatable, highlighting overcommitted nodes
+func (r *MemoryReport) PrintNodeReport() {
+	nodes := r.NodeUsages()
+	if len(nodes) == 0 {
+		return
+	}
+
+	fmt.Printf("=== Node Memory Report (percent of allocatable) ===\n")
+	table := newAlignedTable(false)
+	overcommitted := 0
+	for i := range nodes {
+		n := &nodes[i]
+		symbol := ""
+		if n.IsOvercommitted() {
+			symbol = "🔥"
+			overcommitted++
+		}
+		table.addRow(symbol, "",
+			n.Name(),
+			fmt.Sprintf("Pods: %d", n.Pods),
+			fmt.Sprintf("Usage: %s (%s)", k8s.FormatMemory(&n.TotalUsage), k8s.FormatPercent(n.UsagePercent)),
+			fmt.Sprintf("Requests: %s (%s)", k8s.FormatMemory(&n.TotalRequest), k8s.FormatPercent(n.RequestPercent)),
+			fmt.Sprintf("Limits: %s (%s)", k8s.FormatMemory(&n.TotalLimit), k8s.FormatPercent(n.LimitPercent)),
+			"Allocatable: "+k8s.FormatMemory(n.Allocatable),
+		)
+	}
+	fmt.Print(table.String())
+	if overcommitted > 0 {
+		fmt.Printf("\n%d nodes have requests or limits above their allocatable memory\n", overcommitted)
+	}
+	if top := r.RequestOvercommittedNodes(); len(top) > 0 {
+		fmt.Printf("\nMost overcommitted nodes by requests:\n")
+		if len(top) > maxOvercommittedNodes {
+			top = top[:maxOvercommittedNodes]
+		}
+		for i := range top {
+			n := &top[i]
+			fmt.Printf("  - %s: %s requested of %s allocatable (%s)\n",
+				n.Name(), k8s.FormatMemory(&n.TotalRequest), k8s.FormatMemory(n.Allocatable), k8s.FormatPercent(n.RequestPercent))
+		}
+	}
+	fmt.Printf("\n")
+}
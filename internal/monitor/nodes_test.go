@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func nodeTestReport() *MemoryReport {
+	return &MemoryReport{
+		Pods: []k8s.PodMemoryInfo{
+			{PodName: "a", NodeName: "node-2", CurrentUsage: qty(100 * mi), MemoryRequest: qty(300 * mi), MemoryLimit: qty(600 * mi)},
+			{PodName: "b", NodeName: "node-2", CurrentUsage: qty(100 * mi), MemoryRequest: qty(300 * mi), MemoryLimit: qty(600 * mi)},
+			{PodName: "c", NodeName: "node-1", CurrentUsage: qty(50 * mi)},
+			{PodName: "d", MemoryRequest: qty(64 * mi)},
+		},
+		NodeAllocatable: map[string]resource.Quantity{"node-2": *qty(1000 * mi)},
+	}
+}
+
+func TestNodeUsages_SumsPerNode(t *testing.T) {
+	nodes := nodeTestReport().NodeUsages()
+	if len(nodes) != 3 || nodes[0].Node != "node-1" || nodes[1].Node != "node-2" || nodes[2].Node != unscheduledNode {
+		t.Fatalf("expected node-1, node-2 and the unscheduled bucket last, got %+v", nodes)
+	}
+
+	busy := nodes[1]
+	if busy.Pods != 2 || busy.TotalUsage.Value() != 200*mi || busy.TotalLimit.Value() != 1200*mi {
+		t.Errorf("unexpected node-2 totals: %+v", busy)
+	}
+	if busy.RequestPercent == nil || *busy.RequestPercent != 60 || *busy.LimitPercent != 120 {
+		t.Errorf("expected 60%% requests and 120%% limits of allocatable, got %v and %v", busy.RequestPercent, busy.LimitPercent)
+	}
+	if !busy.IsOvercommitted() {
+		t.Errorf("expected limits above allocatable to be overcommitted")
+	}
+
+	if nodes[0].Allocatable != nil || nodes[0].IsOvercommitted() {
+		t.Errorf("expected no allocatable figures for an unknown node, got %+v", nodes[0])
+	}
+	if nodes[2].Pods != 1 || nodes[2].TotalRequest.Value() != 64*mi {
+		t.Errorf("unexpected unscheduled totals: %+v", nodes[2])
+	}
+}
+
+func TestPrintNodeReport(t *testing.T) {
+	out := captureStdout(t, func() { nodeTestReport().PrintNodeReport() })
+	for _, want := range []string{
+		"🔥 node-2",
+		"Limits: 1.17 GB (120.0%)",
+		"Allocatable: 1000.0 MB",
+		"(unscheduled)",
+		"1 nodes have requests or limits above their allocatable memory",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func requestOvercommitReport() *MemoryReport {
+	return &MemoryReport{
+		Pods: []k8s.PodMemoryInfo{
+			{Namespace: "prod", PodName: "a", NodeName: "node-1", MemoryRequest: qty(600 * mi)},
+			{Namespace: "prod", PodName: "b", NodeName: "node-1", MemoryRequest: qty(600 * mi)},
+			{Namespace: "prod", PodName: "c", NodeName: "node-2", MemoryRequest: qty(1500 * mi)},
+			{Namespace: "prod", PodName: "d", NodeName: "node-3", MemoryRequest: qty(500 * mi)},
+		},
+		NodeAllocatable: map[string]resource.Quantity{
+			"node-1": *qty(1000 * mi),
+			"node-2": *qty(1000 * mi),
+			"node-3": *qty(1000 * mi),
+		},
+	}
+}
+
+func TestRequestOvercommittedNodes_OrdersByExcess(t *testing.T) {
+	nodes := requestOvercommitReport().RequestOvercommittedNodes()
+	if len(nodes) != 2 || nodes[0].Node != "node-2" || nodes[1].Node != "node-1" {
+		t.Fatalf("expected node-2 then node-1, got %+v", nodes)
+	}
+
+	// Limits above allocatable do not count as request overcommit
+	if got := nodeTestReport().RequestOvercommittedNodes(); len(got) != 0 {
+		t.Errorf("expected no request-overcommitted nodes, got %+v", got)
+	}
+}
+
+func TestAnalyzeReport_NodeOvercommitProblems(t *testing.T) {
+	analysis := AnalyzeReport(requestOvercommitReport(), &config.Config{})
+
+	var found []Problem
+	for _, p := range analysis.Problems {
+		if p.Kind == ProblemKindNodeOvercommitted {
+			found = append(found, p)
+		}
+	}
+	if len(found) != 2 || found[0].Node != "node-2" || found[0].Namespace != "" || found[0].PodName != "" {
+		t.Fatalf("expected node-level problems for node-2 then node-1, got %+v", found)
+	}
+	want := "Node node-2 has 150.0% of its allocatable memory requested (1.46 GB of 1000.0 MB across 1 pods)"
+	if found[0].Message != want {
+		t.Errorf("message = %q, want %q", found[0].Message, want)
+	}
+
+	if got := AnalyzeReport(&MemoryReport{Pods: requestOvercommitReport().Pods}, &config.Config{}); len(got.Problems) != 0 {
+		t.Errorf("expected no node problems without allocatable data, got %+v", got.Problems)
+	}
+}
+
+func TestPrintNodeReport_ListsMostOvercommittedNodes(t *testing.T) {
+	out := captureStdout(t, func() { requestOvercommitReport().PrintNodeReport() })
+	want := "Most overcommitted nodes by requests:\n" +
+		"  - node-2: 1.46 GB requested of 1000.0 MB allocatable (150.0%)\n" +
+		"  - node-1: 1.17 GB requested of 1000.0 MB allocatable (120.0%)\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output:\n%s", want, out)
+	}
+}
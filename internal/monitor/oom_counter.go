@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// oomKilledReason is the termination reason the kubelet records for containers killed at their memory limit
+const oomKilledReason = "OOMKilled"
+
+// containerID identifies a container across cycles
+type containerID struct {
+	Cluster   string
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// OOMCounter counts OOMKilled restarts seen between cycles and serves them as a Prometheus counter
+// It is shared between the monitor, which observes each collection, and the /metrics handler
+type OOMCounter struct {
+	mu       sync.Mutex
+	restarts map[containerID]int32 // Restart count of each container in the previous cycle
+	kills    map[containerID]int   // OOM kills counted so far, for containers with an OOMKilled exit
+}
+
+// NewOOMCounter creates an empty counter
+func NewOOMCounter() *OOMCounter {
+	return &OOMCounter{restarts: make(map[containerID]int32), kills: make(map[containerID]int)}
+}
+
+// observe counts the containers that restarted since the previous cycle after being OOMKilled
+// Only the reason of the last exit is known, so several restarts within one cycle count once.
+// Containers first seen with an OOMKilled exit start at zero, and partial collections only add
+// observations so containers in namespaces that were not reached keep their history. A nil counter ignores them
+func (c *OOMCounter) observe(pods []k8s.PodMemoryInfo, partial bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[containerID]bool)
+	for i := range pods {
+		pod := &pods[i]
+		for j := range pod.Containers {
+			container := &pod.Containers[j]
+			id := containerID{Cluster: pod.Cluster, Namespace: pod.Namespace, Pod: pod.PodName, Container: container.ContainerName}
+			seen[id] = true
+
+			previous, known := c.restarts[id]
+			c.restarts[id] = container.RestartCount
+			if container.LastTermination != oomKilledReason {
+				continue
+			}
+			if _, tracked := c.kills[id]; !tracked {
+				c.kills[id] = 0
+			}
+			if known && container.RestartCount > previous {
+				c.kills[id]++
+			}
+		}
+	}
+
+	if partial {
+		return
+	}
+	// Deleted pods are forgotten so the series of short-lived pods do not pile up
+	for id := range c.restarts {
+		if !seen[id] {
+			delete(c.restarts, id)
+			delete(c.kills, id)
+		}
+	}
+}
+
+// WriteMetrics writes the counter in the Prometheus text exposition format
+func (c *OOMCounter) WriteMetrics(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids := make([]containerID, 0, len(c.kills))
+	for id := range c.kills {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Pod != b.Pod {
+			return a.Pod < b.Pod
+		}
+		return a.Container < b.Container
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP k8s_memory_oom_total OOMKilled container restarts observed between collection cycles.\n")
+	b.WriteString("# TYPE k8s_memory_oom_total counter\n")
+	for _, id := range ids {
+		b.WriteString("k8s_memory_oom_total{")
+		if id.Cluster != "" {
+			fmt.Fprintf(&b, `cluster="%s",`, labelValueEscaper.Replace(id.Cluster))
+		}
+		fmt.Fprintf(&b, `namespace="%s",pod="%s",container="%s"} %d`+"\n",
+			labelValueEscaper.Replace(id.Namespace), labelValueEscaper.Replace(id.Pod),
+			labelValueEscaper.Replace(id.Container), c.kills[id])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves /metrics
+func (c *OOMCounter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = c.WriteMetrics(w)
+}
+
+// labelValueEscaper escapes backslashes, quotes and newlines in label values as the exposition format requires
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,109 @@
+package monitor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func oomPod(namespace, name string, restarts int32, reason string) k8s.PodMemoryInfo {
+	return k8s.PodMemoryInfo{
+		Namespace: namespace,
+		PodName:   name,
+		Containers: []k8s.ContainerMemoryInfo{
+			{ContainerName: "app", RestartCount: restarts, LastTermination: reason},
+		},
+	}
+}
+
+func TestOOMCounter_CountsOOMKilledRestarts(t *testing.T) {
+	tests := []struct {
+		name     string
+		cycles   [][]k8s.PodMemoryInfo
+		partial  bool
+		expected string
+	}{
+		{
+			name:     "first cycle only sets the baseline",
+			cycles:   [][]k8s.PodMemoryInfo{{oomPod("ns", "web", 3, "OOMKilled")}},
+			expected: `k8s_memory_oom_total{namespace="ns",pod="web",container="app"} 0`,
+		},
+		{
+			name: "restart after an OOM kill increments",
+			cycles: [][]k8s.PodMemoryInfo{
+				{oomPod("ns", "web", 0, "")},
+				{oomPod("ns", "web", 1, "OOMKilled")},
+				{oomPod("ns", "web", 1, "OOMKilled")},
+				{oomPod("ns", "web", 3, "OOMKilled")},
+			},
+			expected: `k8s_memory_oom_total{namespace="ns",pod="web",container="app"} 2`,
+		},
+		{
+			name: "restarts for other reasons are not counted",
+			cycles: [][]k8s.PodMemoryInfo{
+				{oomPod("ns", "web", 0, "")},
+				{oomPod("ns", "web", 1, "Error")},
+			},
+			expected: "",
+		},
+		{
+			name: "deleted pods are forgotten",
+			cycles: [][]k8s.PodMemoryInfo{
+				{oomPod("ns", "web", 1, "OOMKilled")},
+				{},
+			},
+			expected: "",
+		},
+		{
+			name: "partial cycles keep unreached pods",
+			cycles: [][]k8s.PodMemoryInfo{
+				{oomPod("ns", "web", 1, "OOMKilled")},
+				{},
+			},
+			partial:  true,
+			expected: `k8s_memory_oom_total{namespace="ns",pod="web",container="app"} 0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := NewOOMCounter()
+			for _, pods := range tt.cycles {
+				counter.observe(pods, tt.partial)
+			}
+
+			var out strings.Builder
+			if err := counter.WriteMetrics(&out); err != nil {
+				t.Fatalf("WriteMetrics() failed: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if lines[1] != "# TYPE k8s_memory_oom_total counter" {
+				t.Errorf("expected a counter TYPE line, got %q", lines[1])
+			}
+			got := strings.Join(lines[2:], "\n")
+			if got != tt.expected {
+				t.Errorf("expected samples %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestOOMCounter_ServeHTTP(t *testing.T) {
+	counter := NewOOMCounter()
+	pod := oomPod("ns", `we"b`, 1, "OOMKilled")
+	pod.Cluster = "prod"
+	counter.observe([]k8s.PodMemoryInfo{pod}, false)
+
+	rec := httptest.NewRecorder()
+	counter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain content type, got %q", ct)
+	}
+	expected := `k8s_memory_oom_total{cluster="prod",namespace="ns",pod="we\"b",container="app"} 0`
+	if !strings.Contains(rec.Body.String(), expected) {
+		t.Errorf("expected %s in:\n%s", expected, rec.Body.String())
+	}
+}
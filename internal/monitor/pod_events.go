@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// Pod set change events logged with --watch-namespace-events
+const (
+	PodEventAdded   = "pod_added"
+	PodEventRemoved = "pod_removed"
+)
+
+// podID identifies a pod across cycles
+// The cluster tells apart pods with the same namespace and name in different contexts
+type podID struct {
+	Cluster   string
+	Namespace string
+	Name      string
+}
+
+// podIDOf returns the identity of a collected pod
+func podIDOf(pod *k8s.PodMemoryInfo) podID {
+	return podID{Cluster: pod.Cluster, Namespace: pod.Namespace, Name: pod.PodName}
+}
+
+// String renders the pod as namespace/name, prefixed with its cluster when it has one
+func (id podID) String() string {
+	if id.Cluster == "" {
+		return id.Namespace + "/" + id.Name
+	}
+	return id.Cluster + "/" + id.Namespace + "/" + id.Name
+}
+
+// logAttrs returns the slog attributes identifying the pod, with the cluster only when it has one
+func (id podID) logAttrs() []any {
+	attrs := []any{"namespace", id.Namespace, "pod", id.Name}
+	if id.Cluster != "" {
+		attrs = append([]any{"cluster", id.Cluster}, attrs...)
+	}
+	return attrs
+}
+
+// diffPodSets returns the pods present only in current (added) and only in previous (removed), sorted by cluster, namespace and name
+func diffPodSets(previous, current map[podID]bool) (added, removed []podID) {
+	for id := range current {
+		if !previous[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range previous {
+		if !current[id] {
+			removed = append(removed, id)
+		}
+	}
+	sortPodIDs(added)
+	sortPodIDs(removed)
+	return added, removed
+}
+
+func sortPodIDs(ids []podID) {
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Cluster != ids[j].Cluster {
+			return ids[i].Cluster < ids[j].Cluster
+		}
+		if ids[i].Namespace != ids[j].Namespace {
+			return ids[i].Namespace < ids[j].Namespace
+		}
+		return ids[i].Name < ids[j].Name
+	})
+}
+
+// trackPodChanges logs pods that appeared or disappeared since the previous cycle
+// The first cycle only records the baseline, and cancelled collections are skipped so
+// namespaces that were not reached do not show up as removed pods
+func (m *MemoryMonitor) trackPodChanges(pods []k8s.PodMemoryInfo, cancelled bool) {
+	if !m.config.WatchNamespaceEvents || cancelled {
+		return
+	}
+
+	current := make(map[podID]bool, len(pods))
+	for i := range pods {
+		current[podIDOf(&pods[i])] = true
+	}
+	if m.knownPods != nil {
+		added, removed := diffPodSets(m.knownPods, current)
+		for _, id := range added {
+			slog.Info("Pod added", append([]any{"event", PodEventAdded}, id.logAttrs()...)...)
+		}
+		for _, id := range removed {
+			slog.Info("Pod removed", append([]any{"event", PodEventRemoved}, id.logAttrs()...)...)
+		}
+	}
+	m.knownPods = current
+}
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiffPodSets(t *testing.T) {
+	previous := map[podID]bool{
+		{Namespace: "a", Name: "kept"}:    true,
+		{Namespace: "a", Name: "deleted"}: true,
+	}
+	current := map[podID]bool{
+		{Namespace: "a", Name: "kept"}:  true,
+		{Namespace: "b", Name: "web-2"}: true,
+		{Namespace: "a", Name: "web-1"}: true,
+		{Namespace: "b", Name: "kept"}:  true,
+	}
+
+	added, removed := diffPodSets(previous, current)
+
+	expectedAdded := []podID{{Namespace: "a", Name: "web-1"}, {Namespace: "b", Name: "kept"}, {Namespace: "b", Name: "web-2"}}
+	if len(added) != len(expectedAdded) {
+		t.Fatalf("expected added %v, got %v", expectedAdded, added)
+	}
+	for i := range expectedAdded {
+		if added[i] != expectedAdded[i] {
+			t.Errorf("added[%d]: expected %v, got %v", i, expectedAdded[i], added[i])
+		}
+	}
+	if len(removed) != 1 || removed[0] != (podID{Namespace: "a", Name: "deleted"}) {
+		t.Errorf("expected only a/deleted removed, got %v", removed)
+	}
+}
+
+func TestTrackPodChanges_KeepsStateAcrossCycles(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.WatchNamespaceEvents = true
+	m := newTestMonitor(cfg, testPod{namespace: "prod", name: "web", usage: "100Mi", request: "200Mi", limit: "400Mi"})
+
+	if _, err := m.CollectMemoryInfo(context.Background()); err != nil {
+		t.Fatalf("CollectMemoryInfo() failed: %v", err)
+	}
+	if !m.knownPods[podID{Namespace: "prod", Name: "web"}] || len(m.knownPods) != 1 {
+		t.Fatalf("expected the first cycle to record prod/web, got %v", m.knownPods)
+	}
+
+	// A partial cycle must not replace the baseline
+	m.trackPodChanges(nil, true)
+	if len(m.knownPods) != 1 {
+		t.Errorf("expected partial collections to be ignored, got %v", m.knownPods)
+	}
+
+	m.trackPodChanges(nil, false)
+	if len(m.knownPods) != 0 {
+		t.Errorf("expected the baseline to follow the latest cycle, got %v", m.knownPods)
+	}
+}
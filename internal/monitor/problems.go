@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// Problem severities
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+)
+
+// Problem kinds
+const (
+	ProblemKindRequestUsage = "request_usage"
+	ProblemKindLimitUsage   = "limit_usage"
+	ProblemKindOverLimit    = "over_limit_exceeded"
+	ProblemKindNoLimit      = "no_limit"
+	ProblemKindNoRequest    = "no_request"
+
+	ProblemKindAbsoluteThreshold = "over_absolute_threshold"
+	ProblemKindUnschedulable     = "unschedulable"
+	ProblemKindNodeOvercommitted = "node_overcommitted"
+)
+
+// Problem is a structured memory issue detected during analysis
+type Problem struct {
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Cluster   string `json:"cluster,omitempty"` // Context of the pod or node, multi-cluster runs only
+	Namespace string `json:"namespace"`
+	PodName   string `json:"pod_name"`
+	Container string `json:"container,omitempty"`
+	Node      string `json:"node,omitempty"` // Set for node-level problems, which have no namespace or pod
+	Message   string `json:"message"`
+
+	// Set when --dedupe-problems collapsed the problem of several replicas; PodName is then the first of them
+	Workload string `json:"workload,omitempty"`
+	Count    int    `json:"count,omitempty"`
+}
+
+// addProblem records a problem both as structured data and as its human-readable message
+// With early trimming, the list is cut back to the limit once it holds twice as many, so memory stays bounded
+func (a *AnalysisResult) addProblem(p Problem) {
+	a.Problems = append(a.Problems, p)
+	a.ProblemsFound = append(a.ProblemsFound, p.Message)
+	if a.trimEarly && a.maxProblems > 0 && len(a.Problems) >= 2*a.maxProblems {
+		a.sortProblems()
+		a.capProblems()
+	}
+}
+
+// problemRank orders problems by urgency: pods already over their limit, then other critical problems,
+// then warnings
+func problemRank(p *Problem) int {
+	switch {
+	case p.Severity == SeverityCritical && p.Kind == ProblemKindOverLimit:
+		return 0
+	case p.Severity == SeverityCritical:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// sortProblems orders the problems by urgency, keeping the analysis order within each rank
+func (a *AnalysisResult) sortProblems() {
+	sort.SliceStable(a.Problems, func(i, j int) bool {
+		return problemRank(&a.Problems[i]) < problemRank(&a.Problems[j])
+	})
+	for i := range a.Problems {
+		a.ProblemsFound[i] = a.Problems[i].Message
+	}
+}
+
+// capProblems keeps the first maxProblems problems and counts the rest as dropped
+// The problems must already be ordered by sortProblems, so the most urgent ones are kept
+func (a *AnalysisResult) capProblems() {
+	if a.maxProblems <= 0 || len(a.Problems) <= a.maxProblems {
+		return
+	}
+	for _, p := range a.Problems[a.maxProblems:] {
+		if p.Severity == SeverityCritical {
+			a.droppedCritical++
+		}
+	}
+	a.DroppedProblems += len(a.Problems) - a.maxProblems
+
+	// Copied so the backing array of the dropped problems can be released
+	a.Problems = append([]Problem(nil), a.Problems[:a.maxProblems]...)
+	a.ProblemsFound = make([]string, len(a.Problems))
+	for i := range a.Problems {
+		a.ProblemsFound[i] = a.Problems[i].Message
+	}
+}
+
+// ProblemCount returns the number of problems found, including those dropped by the problem limit
+func (a *AnalysisResult) ProblemCount() int {
+	return len(a.ProblemsFound) + a.DroppedProblems
+}
+
+// SeverityCounts returns the number of critical and warning problems found, including those dropped
+// by the problem limit
+func (a *AnalysisResult) SeverityCounts() (critical, warning int) {
+	for _, p := range a.Problems {
+		switch p.Severity {
+		case SeverityCritical:
+			critical++
+		case SeverityWarning:
+			warning++
+		}
+	}
+	return critical + a.droppedCritical, warning + a.DroppedProblems - a.droppedCritical
+}
+
+// criticalLimitPercent is the share of the limit at which usage becomes critical
+const criticalLimitPercent = 90.0
+
+// criticalRequestPercent is the share of the request at which usage becomes critical
+const criticalRequestPercent = 95.0
+
+// addPodProblem records a problem found on the given pod, tagged with the pod's cluster
+func (a *AnalysisResult) addPodProblem(pod *k8s.PodMemoryInfo, p Problem) {
+	p.Cluster = pod.Cluster
+	a.addProblem(p)
+}
+
+// podID returns the identity of the pod the problem was found on
+func (p *Problem) podID() podID {
+	return podID{Cluster: p.Cluster, Namespace: p.Namespace, Name: p.PodName}
+}
+
+// newPodLimitProblem reports high usage against a pod's limit
+// Usage above 100% means the limit is already exceeded and an OOM kill is imminent, which outranks the 90% threshold
+func newPodLimitProblem(namespace, podName string, limitUsagePercent float64) Problem {
+	if limitUsagePercent > 100 {
+		return newPodProblem(SeverityCritical, ProblemKindOverLimit, namespace, podName,
+			"is using %.1f%% of its memory limit and has exceeded it (OOM kill imminent)", limitUsagePercent)
+	}
+	return newPodProblem(SeverityCritical, ProblemKindLimitUsage, namespace, podName,
+		"is using %.1f%% of its memory limit", limitUsagePercent)
+}
+
+// newContainerLimitProblem reports high usage against a container's limit, like newPodLimitProblem
+func newContainerLimitProblem(namespace, podName, container string, limitUsagePercent float64) Problem {
+	if limitUsagePercent > 100 {
+		return newContainerProblem(SeverityCritical, ProblemKindOverLimit, namespace, podName, container,
+			"is using %.1f%% of its memory limit and has exceeded it (OOM kill imminent)", limitUsagePercent)
+	}
+	return newContainerProblem(SeverityCritical, ProblemKindLimitUsage, namespace, podName, container,
+		"is using %.1f%% of its memory limit", limitUsagePercent)
+}
+
+// newPodProblem builds a pod-level problem whose message is prefixed with the pod identity
+func newPodProblem(severity, kind, namespace, podName, format string, args ...any) Problem {
+	return Problem{
+		Severity:  severity,
+		Kind:      kind,
+		Namespace: namespace,
+		PodName:   podName,
+		Message:   fmt.Sprintf("Pod %s/%s ", namespace, podName) + fmt.Sprintf(format, args...),
+	}
+}
+
+// newContainerProblem builds a container-level problem whose message names the container
+func newContainerProblem(severity, kind, namespace, podName, container, format string, args ...any) Problem {
+	return Problem{
+		Severity:  severity,
+		Kind:      kind,
+		Namespace: namespace,
+		PodName:   podName,
+		Container: container,
+		Message:   fmt.Sprintf("Pod %s/%s container %s ", namespace, podName, container) + fmt.Sprintf(format, args...),
+	}
+}
+
+// newNodeProblem builds a node-level problem whose message is prefixed with the node name
+func newNodeProblem(severity, kind, node, format string, args ...any) Problem {
+	return Problem{
+		Severity: severity,
+		Kind:     kind,
+		Node:     node,
+		Message:  fmt.Sprintf("Node %s ", node) + fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestCapProblems_KeepsCriticalFirst(t *testing.T) {
+	analysis := &AnalysisResult{maxProblems: 3}
+	for i := 0; i < 4; i++ {
+		analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "prod", fmt.Sprintf("web-%d", i), "has no memory limit defined"))
+	}
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", "db-0", "is using %.1f%% of its memory limit", 97.0))
+	analysis.sortProblems()
+	analysis.capProblems()
+
+	expected := []string{
+		"Pod prod/db-0 is using 97.0% of its memory limit",
+		"Pod prod/web-0 has no memory limit defined",
+		"Pod prod/web-1 has no memory limit defined",
+	}
+	if len(analysis.Problems) != len(expected) || len(analysis.ProblemsFound) != len(expected) {
+		t.Fatalf("expected %d problems, got %q", len(expected), analysis.ProblemsFound)
+	}
+	for i := range expected {
+		if analysis.ProblemsFound[i] != expected[i] || analysis.Problems[i].Message != expected[i] {
+			t.Errorf("problem %d: expected %q, got %q", i, expected[i], analysis.ProblemsFound[i])
+		}
+	}
+	if analysis.DroppedProblems != 2 {
+		t.Errorf("expected 2 dropped problems, got %d", analysis.DroppedProblems)
+	}
+	if analysis.ProblemCount() != 5 {
+		t.Errorf("expected a problem count of 5, got %d", analysis.ProblemCount())
+	}
+}
+
+func TestAnalyzeReport_MaxProblems(t *testing.T) {
+	report := &MemoryReport{}
+	for i := 0; i < 50; i++ {
+		report.Pods = append(report.Pods, k8s.PodMemoryInfo{
+			Namespace:  "prod",
+			PodName:    fmt.Sprintf("web-%d", i),
+			Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app"}},
+		})
+	}
+
+	analysis := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0, MaxProblems: 10})
+	if len(analysis.Problems) != 10 || len(analysis.ProblemsFound) != 10 {
+		t.Fatalf("expected 10 problems kept, got %d and %d", len(analysis.Problems), len(analysis.ProblemsFound))
+	}
+	if analysis.DroppedProblems == 0 {
+		t.Fatal("expected dropped problems to be counted")
+	}
+
+	unlimited := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0})
+	if analysis.ProblemCount() != unlimited.ProblemCount() {
+		t.Errorf("expected the problem count %d to match the unlimited run, got %d", unlimited.ProblemCount(), analysis.ProblemCount())
+	}
+
+	output := captureStdout(t, func() { NewAnalysisReporter().printProblems(analysis) })
+	if !strings.Contains(output, fmt.Sprintf("Found %d potential issues", unlimited.ProblemCount())) {
+		t.Errorf("expected the total problem count, got: %s", output)
+	}
+	if !strings.Contains(output, fmt.Sprintf("... and %d more", analysis.DroppedProblems)) {
+		t.Errorf("expected a note about the dropped problems, got: %s", output)
+	}
+}
+
+func TestAnalyzeReport_MaxProblemsAppliesAfterDedupe(t *testing.T) {
+	report := &MemoryReport{}
+	for i := 0; i < 6; i++ {
+		report.Pods = append(report.Pods, k8s.PodMemoryInfo{
+			Namespace:  "prod",
+			PodName:    fmt.Sprintf("web-%d", i),
+			Workload:   "deploy/web",
+			Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app"}},
+		})
+	}
+
+	analysis := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0, MaxProblems: 2, DedupeProblems: true})
+	if analysis.DroppedProblems != 0 {
+		t.Errorf("expected the deduplicated problems to fit the limit, got %d dropped: %q", analysis.DroppedProblems, analysis.ProblemsFound)
+	}
+	for _, p := range analysis.Problems {
+		if p.Count != 6 {
+			t.Errorf("expected each problem to collapse the 6 replicas, got %+v", p)
+		}
+	}
+}
+
+func TestSeverityCounts_IncludeDroppedProblems(t *testing.T) {
+	analysis := &AnalysisResult{maxProblems: 2}
+	for i := 0; i < 3; i++ {
+		analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", fmt.Sprintf("db-%d", i), "is using %.1f%% of its memory limit", 97.0))
+	}
+	for i := 0; i < 4; i++ {
+		analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "prod", fmt.Sprintf("web-%d", i), "has no memory limit defined"))
+	}
+	analysis.sortProblems()
+	analysis.capProblems()
+
+	critical, warning := analysis.SeverityCounts()
+	if critical != 3 || warning != 4 {
+		t.Errorf("expected 3 critical and 4 warning problems, got %d and %d", critical, warning)
+	}
+	doc := newSummaryDocument(analysis)
+	if doc.CriticalProblems != 3 || doc.WarningProblems != 4 {
+		t.Errorf("expected the summary to count dropped problems, got %d critical and %d warning", doc.CriticalProblems, doc.WarningProblems)
+	}
+	stats := newSessionStats(time.Now())
+	stats.record(analysis)
+	if stats.CriticalEvents != 3 {
+		t.Errorf("expected 3 critical events, got %d", stats.CriticalEvents)
+	}
+}
+
+func TestAnalyzeReport_OrdersOverLimitFirst(t *testing.T) {
+	const mib = 1024 * 1024
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "unlimited", CurrentUsage: qty(100 * mib)},
+		{Namespace: "prod", PodName: "busy", CurrentUsage: qty(950 * mib), MemoryRequest: qty(4096 * mib), MemoryLimit: qty(1024 * mib)},
+		{Namespace: "prod", PodName: "over", CurrentUsage: qty(1060 * mib), MemoryRequest: qty(4096 * mib), MemoryLimit: qty(1024 * mib)},
+	}}
+
+	analysis := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0})
+	var kinds []string
+	for _, p := range analysis.Problems {
+		kinds = append(kinds, p.Kind)
+	}
+	expected := []string{ProblemKindOverLimit, ProblemKindLimitUsage, ProblemKindNoLimit, ProblemKindNoRequest}
+	if strings.Join(kinds, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected problems ordered %v, got %v", expected, kinds)
+	}
+	if analysis.ProblemsFound[0] != analysis.Problems[0].Message {
+		t.Errorf("expected messages to follow the problem order, got %q", analysis.ProblemsFound)
+	}
+
+	capped := AnalyzeReport(report, &config.Config{MemoryWarningPercent: 80.0, MaxProblems: 1})
+	if len(capped.Problems) != 1 || capped.Problems[0].Kind != ProblemKindOverLimit {
+		t.Errorf("expected the over-limit problem to be kept first, got %+v", capped.Problems)
+	}
+}
+
+func TestAddProblem_BoundsMemoryWhileAnalyzing(t *testing.T) {
+	analysis := &AnalysisResult{maxProblems: 10, trimEarly: true}
+	for i := 0; i < 10000; i++ {
+		analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "prod", fmt.Sprintf("web-%d", i), "has no memory limit defined"))
+		if cap(analysis.Problems) > 4*analysis.maxProblems || cap(analysis.ProblemsFound) > 4*analysis.maxProblems {
+			t.Fatalf("expected the problem slices to stay bounded, got capacities %d and %d after %d problems",
+				cap(analysis.Problems), cap(analysis.ProblemsFound), i+1)
+		}
+	}
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", "db-0", "is using %.1f%% of its memory limit", 97.0))
+	analysis.sortProblems()
+	analysis.capProblems()
+
+	if len(analysis.Problems) != 10 || analysis.Problems[0].PodName != "db-0" {
+		t.Errorf("expected the 10 most urgent problems, critical first, got %d starting with %+v", len(analysis.Problems), analysis.Problems[0])
+	}
+	critical, warning := analysis.SeverityCounts()
+	if critical != 1 || warning != 10000 || analysis.ProblemCount() != 10001 {
+		t.Errorf("expected every problem to be counted, got %d critical, %d warning and %d in total", critical, warning, analysis.ProblemCount())
+	}
+}
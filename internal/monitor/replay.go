@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// savedReport is the part of a --output=json document needed to replay its analysis
+type savedReport struct {
+	SchemaVersion string        `json:"schema_version"`
+	Report        *MemoryReport `json:"report"`
+}
+
+// LoadReports reads the reports of a file captured with --output=json, in the order they were written
+// A watch session writes one document per cycle, so a file may hold several reports
+func LoadReports(path string) ([]MemoryReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report file: %w", err)
+	}
+	defer f.Close()
+
+	reports, err := decodeReports(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reports from %s: %w", path, err)
+	}
+	return reports, nil
+}
+
+// decodeReports decodes a stream of JSON report documents
+func decodeReports(r io.Reader) ([]MemoryReport, error) {
+	var reports []MemoryReport
+	decoder := json.NewDecoder(r)
+	for {
+		var doc savedReport
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", len(reports)+1, err)
+		}
+		if doc.Report == nil {
+			// --problems-only and --summary-only documents carry no pods to analyze
+			return nil, fmt.Errorf("document %d has no report, capture it with --output=json only", len(reports)+1)
+		}
+		if doc.SchemaVersion != ReportSchemaVersion {
+			return nil, fmt.Errorf("document %d has schema version %q, expected %q",
+				len(reports)+1, doc.SchemaVersion, ReportSchemaVersion)
+		}
+		reports = append(reports, *doc.Report)
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no reports found")
+	}
+	return reports, nil
+}
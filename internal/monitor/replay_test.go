@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func replayTestReport(usage int64) *MemoryReport {
+	return &MemoryReport{
+		Summary: k8s.MemorySummary{TotalPods: 1},
+		Pods: []k8s.PodMemoryInfo{{
+			Namespace:     "prod",
+			PodName:       "web",
+			CurrentUsage:  resource.NewQuantity(usage*mi, resource.BinarySI),
+			MemoryRequest: resource.NewQuantity(100*mi, resource.BinarySI),
+			MemoryLimit:   resource.NewQuantity(200*mi, resource.BinarySI),
+		}},
+	}
+}
+
+func TestLoadReports_ReplaysSavedCycles(t *testing.T) {
+	saveCfg := &config.Config{MemoryWarningPercent: 80}
+	var buf bytes.Buffer
+	formatter := newJSONFormatterTo(&buf)
+	formatter.FormatAnalysis(AnalyzeReport(replayTestReport(50), saveCfg), saveCfg)
+	formatter.FormatAnalysis(AnalyzeReport(replayTestReport(70), saveCfg), saveCfg)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := LoadReports(path)
+	if err != nil {
+		t.Fatalf("LoadReports() failed: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+
+	// A lower threshold flags the second cycle, which was fine when captured
+	replayCfg := &config.Config{MemoryWarningPercent: 60}
+	if got := len(AnalyzeReport(&reports[0], replayCfg).WarningPods); got != 0 {
+		t.Errorf("expected no warning pods in the first cycle, got %d", got)
+	}
+	if got := len(AnalyzeReport(&reports[1], replayCfg).WarningPods); got != 1 {
+		t.Errorf("expected the second cycle to warn, got %d warning pods", got)
+	}
+}
+
+func TestDecodeReports_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty file", "", "no reports found"},
+		{"invalid json", "{", "document 1"},
+		{"problems only", `{"severity":"warning","kind":"no_limit"}`, "has no report"},
+		{"unknown schema", `{"schema_version":"99","report":{"summary":{},"pods":[]}}`, `schema version "99"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeReports(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadReports_MissingFile(t *testing.T) {
+	if _, err := LoadReports(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
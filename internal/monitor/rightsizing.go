@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RightSizingEntry describes a pod whose usage is far from its memory request
+type RightSizingEntry struct {
+	Namespace    string            `json:"namespace"`
+	PodName      string            `json:"pod_name"`
+	UsagePercent float64           `json:"usage_percent"`
+	Delta        resource.Quantity `json:"delta"` // Request minus usage: unused bytes when positive, shortfall when negative
+}
+
+// RightSizing splits pods into over-provisioned (usage below lowPercent of request)
+// and under-provisioned (usage above highPercent of request)
+// Pods without usage or request are skipped; each list is ordered by the largest delta first
+func (r *MemoryReport) RightSizing(lowPercent, highPercent float64) (over, under []RightSizingEntry) {
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		if pod.CurrentUsage == nil || pod.MemoryRequest == nil {
+			continue
+		}
+		pod.CalculateUsagePercent()
+		if pod.UsagePercent == nil {
+			continue
+		}
+
+		entry := RightSizingEntry{
+			Namespace:    pod.Namespace,
+			PodName:      pod.PodName,
+			UsagePercent: *pod.UsagePercent,
+			Delta:        *resource.NewQuantity(pod.MemoryRequest.Value()-pod.CurrentUsage.Value(), resource.BinarySI),
+		}
+		switch {
+		case entry.UsagePercent < lowPercent:
+			over = append(over, entry)
+		case entry.UsagePercent > highPercent:
+			under = append(under, entry)
+		}
+	}
+
+	sort.SliceStable(over, func(i, j int) bool { return over[i].Delta.Cmp(over[j].Delta) > 0 })
+	sort.SliceStable(under, func(i, j int) bool { return under[i].Delta.Cmp(under[j].Delta) < 0 })
+	return over, under
+}
+
+// PrintRightSizing prints over- and under-provisioned pods with how far their request is from usage
+func (r *MemoryReport) PrintRightSizing(lowPercent, highPercent float64) {
+	over, under := r.RightSizing(lowPercent, highPercent)
+
+	fmt.Printf("=== Memory Request Right-Sizing ===\n")
+	fmt.Printf("Over-provisioned (using < %.0f%% of request): %d pods\n", lowPercent, len(over))
+	for i := range over {
+		e := &over[i]
+		fmt.Printf("  📉 %s/%s | Usage: %s of request | Unused: %s\n",
+			e.Namespace, e.PodName, k8s.FormatPercent(&e.UsagePercent), k8s.FormatMemory(&e.Delta))
+	}
+
+	fmt.Printf("Under-provisioned (using > %.0f%% of request): %d pods\n", highPercent, len(under))
+	for i := range under {
+		e := &under[i]
+		fmt.Printf("  📈 %s/%s | Usage: %s of request | Request headroom: %s\n",
+			e.Namespace, e.PodName, k8s.FormatPercent(&e.UsagePercent), k8s.FormatMemory(&e.Delta))
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func rightSizingPod(name string, usage, request int64) k8s.PodMemoryInfo {
+	pod := k8s.PodMemoryInfo{Namespace: "prod", PodName: name}
+	if usage > 0 {
+		pod.CurrentUsage = resource.NewQuantity(usage*mi, resource.BinarySI)
+	}
+	if request > 0 {
+		pod.MemoryRequest = resource.NewQuantity(request*mi, resource.BinarySI)
+	}
+	return pod
+}
+
+func TestRightSizing_SplitsOverAndUnderProvisioned(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		rightSizingPod("idle", 100, 1024),
+		rightSizingPod("slightly-idle", 200, 512),
+		rightSizingPod("balanced", 350, 512),
+		rightSizingPod("tight", 480, 512),
+		rightSizingPod("over-request", 700, 512),
+		rightSizingPod("no-request", 700, 0),
+		rightSizingPod("no-metrics", 0, 512),
+	}}
+
+	over, under := report.RightSizing(50, 90)
+
+	if len(over) != 2 || over[0].PodName != "idle" || over[1].PodName != "slightly-idle" {
+		t.Fatalf("expected idle pods ordered by unused bytes, got %+v", over)
+	}
+	if over[0].Delta.Value() != 924*mi {
+		t.Errorf("expected 924Mi unused for idle pod, got %d", over[0].Delta.Value())
+	}
+	if len(under) != 2 || under[0].PodName != "over-request" || under[1].PodName != "tight" {
+		t.Fatalf("expected under-provisioned pods ordered by shortfall, got %+v", under)
+	}
+	if under[0].Delta.Value() != -188*mi {
+		t.Errorf("expected -188Mi delta for pod above its request, got %d", under[0].Delta.Value())
+	}
+}
+
+func TestPrintRightSizing(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		rightSizingPod("idle", 100, 1024),
+		rightSizingPod("over-request", 700, 512),
+	}}
+
+	output := captureStdout(t, func() { report.PrintRightSizing(50, 90) })
+
+	for _, expected := range []string{
+		"Over-provisioned (using < 50% of request): 1 pods",
+		"prod/idle | Usage: 9.8% of request | Unused: 924.0 MB",
+		"Under-provisioned (using > 90% of request): 1 pods",
+		"prod/over-request | Usage: 136.7% of request | Request headroom: -188.0 MB",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+}
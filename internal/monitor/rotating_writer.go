@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// segmentTimeFormat is the suffix format of rotated report files
+const segmentTimeFormat = "20060102T150405Z"
+
+// RotatingWriter appends reports to a file and moves it aside once it grows too large or too old
+// Rotation is only checked through RotateIfDue, between reports, so a report is never split across files
+type RotatingWriter struct {
+	path    string
+	maxSize int64         // Size in bytes after which the file is rotated, 0 disables
+	maxAge  time.Duration // Age after which the file is rotated, 0 disables
+	now     func() time.Time
+
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// NewRotatingWriter opens path for appending, creating it if needed
+func NewRotatingWriter(path string, maxSize int64, maxAge time.Duration, now func() time.Time) (*RotatingWriter, error) {
+	w := &RotatingWriter{path: path, maxSize: maxSize, maxAge: maxAge, now: now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the current file, picking up the size of an existing one
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat output file: %w", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	w.opened = w.now()
+	return nil
+}
+
+// Write appends p to the current file
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Size returns the number of bytes in the current file
+func (w *RotatingWriter) Size() int64 {
+	return w.size
+}
+
+// RotateIfDue rotates the file when it reached the size limit or the rotation interval
+// An empty file is never rotated, so idle periods do not leave empty segments behind
+func (w *RotatingWriter) RotateIfDue() (bool, error) {
+	if w.size == 0 {
+		return false, nil
+	}
+	oversized := w.maxSize > 0 && w.size >= w.maxSize
+	expired := w.maxAge > 0 && w.now().Sub(w.opened) >= w.maxAge
+	if !oversized && !expired {
+		return false, nil
+	}
+	return true, w.rotate()
+}
+
+// rotate closes the current file, renames it with a timestamped suffix and opens a new one
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	segment, err := w.segmentName()
+	if err == nil {
+		err = os.Rename(w.path, segment)
+	}
+	if err != nil {
+		// Keep writing to the same file rather than losing reports
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate output file: %w", err)
+	}
+	return w.open()
+}
+
+// segmentName returns an unused name for the closed file, e.g. report.csv.20240501T120000Z
+// A counter is added when several rotations happen within the same second.
+// It fails when a candidate name cannot be checked, e.g. when the directory is not readable
+func (w *RotatingWriter) segmentName() (string, error) {
+	base := w.path + "." + w.now().UTC().Format(segmentTimeFormat)
+	name := base
+	for i := 1; ; i++ {
+		_, err := os.Stat(name)
+		if os.IsNotExist(err) {
+			return name, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check segment name %s: %w", name, err)
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// Close closes the current file
+func (w *RotatingWriter) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+func TestRotatingWriter_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w, err := NewRotatingWriter(path, 10, 0, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("NewRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	if rotated, err := w.RotateIfDue(); err != nil || rotated {
+		t.Fatalf("expected an empty file not to rotate, got rotated=%v err=%v", rotated, err)
+	}
+	// A report larger than the limit is still written whole; rotation happens before the next one
+	if _, err := w.Write([]byte("first report\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if rotated, err := w.RotateIfDue(); err != nil || !rotated {
+		t.Fatalf("expected rotation past the size limit, got rotated=%v err=%v", rotated, err)
+	}
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	segment, err := os.ReadFile(path + ".20240501T120000Z")
+	if err != nil || string(segment) != "first report\n" {
+		t.Errorf("expected the first report in the rotated segment, got %q (%v)", segment, err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "second\n" {
+		t.Errorf("expected the current file to start fresh, got %q (%v)", current, err)
+	}
+	if w.Size() != int64(len("second\n")) {
+		t.Errorf("expected size of the new file, got %d", w.Size())
+	}
+}
+
+func TestRotatingWriter_RotatesByAgeWithUniqueNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w, err := NewRotatingWriter(path, 0, time.Hour, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("NewRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	_, _ = w.Write([]byte("{}\n"))
+	if rotated, _ := w.RotateIfDue(); rotated {
+		t.Fatal("expected no rotation before the interval elapsed")
+	}
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		// Opening the new file resets its age, so move the clock back to force a second rotation in the same second
+		w.opened = now.Add(-time.Hour)
+		_, _ = w.Write([]byte("{}\n"))
+		if rotated, err := w.RotateIfDue(); err != nil || !rotated {
+			t.Fatalf("expected rotation after the interval, got rotated=%v err=%v", rotated, err)
+		}
+	}
+
+	for _, name := range []string{path + ".20240501T130000Z", path + ".20240501T130000Z-1"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("expected segment %s: %v", name, err)
+		}
+	}
+}
+
+func TestRotatingWriter_SegmentNameFailsOnStatError(t *testing.T) {
+	// A regular file used as the directory makes every stat fail with something other than not-exist
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	w := &RotatingWriter{path: filepath.Join(notDir, "report.csv"), now: time.Now}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.segmentName()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected an error when the segment name cannot be checked")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("segmentName() did not return on a stat error")
+	}
+}
+
+func TestRotatingWriter_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := os.WriteFile(path, []byte("header\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewRotatingWriter(path, 0, 0, time.Now)
+	if err != nil {
+		t.Fatalf("NewRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	if w.Size() != int64(len("header\n")) {
+		t.Errorf("expected the existing size to be picked up, got %d", w.Size())
+	}
+}
+
+func TestWriteOutputFile_RepeatsCSVHeaderAfterRotation(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.Output = config.OutputFormatCSV
+	m := newTestMonitor(cfg, testPod{namespace: "prod", name: "api", usage: "100Mi", request: "200Mi", limit: "400Mi"})
+	path := filepath.Join(t.TempDir(), "report.csv")
+	w, err := NewRotatingWriter(path, 1, 0, time.Now)
+	if err != nil {
+		t.Fatalf("NewRotatingWriter() failed: %v", err)
+	}
+	m.outputFile = w
+	defer m.Close()
+
+	for i := 0; i < 2; i++ {
+		analysis, err := m.AnalyzeMemoryUsage(context.Background())
+		if err != nil {
+			t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+		}
+		if err := m.WriteOutputFile(analysis); err != nil {
+			t.Fatalf("WriteOutputFile() failed: %v", err)
+		}
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(current)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "timestamp,") {
+		t.Errorf("expected the rotated file to start with a header and hold one row, got %q", lines)
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SessionStats accumulates figures across the monitoring cycles of one run
+type SessionStats struct {
+	Started        time.Time
+	Cycles         int                // Cycles that produced an analysis
+	FailedCycles   int                // Cycles whose collection failed
+	CriticalEvents int                // Critical problems reported, summed over cycles
+	PeakPod        string             // namespace/name of the pod with the highest usage seen
+	PeakPodUsage   *resource.Quantity // nil until a pod reports usage
+	PeakTotalUsage *resource.Quantity // Highest total usage of a single cycle
+}
+
+// newSessionStats starts a session at the given time
+func newSessionStats(started time.Time) *SessionStats {
+	return &SessionStats{Started: started}
+}
+
+// record folds one cycle's analysis into the session totals
+func (s *SessionStats) record(analysis *AnalysisResult) {
+	s.Cycles++
+	critical, _ := analysis.SeverityCounts()
+	s.CriticalEvents += critical
+
+	total := analysis.Report.Summary.TotalMemoryUsage
+	if s.PeakTotalUsage == nil || total.Cmp(*s.PeakTotalUsage) > 0 {
+		s.PeakTotalUsage = &total
+	}
+	for i := range analysis.Report.Pods {
+		pod := &analysis.Report.Pods[i]
+		if pod.CurrentUsage == nil {
+			continue
+		}
+		if s.PeakPodUsage == nil || pod.CurrentUsage.Cmp(*s.PeakPodUsage) > 0 {
+			usage := *pod.CurrentUsage
+			s.PeakPodUsage = &usage
+			s.PeakPod = podIDOf(pod).String()
+		}
+	}
+}
+
+// Print writes the session recap, ending at now
+func (s *SessionStats) Print(w io.Writer, now time.Time) {
+	fmt.Fprintf(w, "=== Session Summary ===\n")
+	fmt.Fprintf(w, "  Duration: %s\n", now.Sub(s.Started).Round(time.Second))
+	fmt.Fprintf(w, "  Cycles Run: %d", s.Cycles)
+	if s.FailedCycles > 0 {
+		fmt.Fprintf(w, " (%d failed)", s.FailedCycles)
+	}
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "  Critical Events: %d\n", s.CriticalEvents)
+	fmt.Fprintf(w, "  Peak Total Usage: %s\n", k8s.FormatMemoryTotal(s.PeakTotalUsage))
+	if s.PeakPodUsage != nil {
+		fmt.Fprintf(w, "  Peak Pod Usage: %s (%s)\n", k8s.FormatMemory(s.PeakPodUsage), s.PeakPod)
+	}
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionStats_AccumulatesAcrossCycles(t *testing.T) {
+	m := newTestMonitor(testMonitorConfig(),
+		testPod{namespace: "prod", name: "big", usage: "530Mi", request: "512Mi", limit: "512Mi"},
+		testPod{namespace: "prod", name: "small", usage: "100Mi", request: "1Gi", limit: "2Gi"},
+	)
+
+	var criticalPerCycle int
+	for i := 0; i < 2; i++ {
+		analysis, err := m.AnalyzeMemoryUsage(context.Background())
+		if err != nil {
+			t.Fatalf("AnalyzeMemoryUsage() failed: %v", err)
+		}
+		criticalPerCycle = 0
+		for _, p := range analysis.Problems {
+			if p.Severity == SeverityCritical {
+				criticalPerCycle++
+			}
+		}
+	}
+
+	session := m.Session()
+	if session.Cycles != 2 {
+		t.Errorf("expected 2 cycles, got %d", session.Cycles)
+	}
+	if criticalPerCycle == 0 || session.CriticalEvents != 2*criticalPerCycle {
+		t.Errorf("expected %d critical events over both cycles, got %d", 2*criticalPerCycle, session.CriticalEvents)
+	}
+	if session.PeakPod != "prod/big" || session.PeakPodUsage == nil || session.PeakPodUsage.Value() != 530*1024*1024 {
+		t.Errorf("expected prod/big as the peak pod, got %s at %v", session.PeakPod, session.PeakPodUsage)
+	}
+	if session.PeakTotalUsage == nil || session.PeakTotalUsage.Value() != 630*1024*1024 {
+		t.Errorf("expected 630Mi peak total usage, got %v", session.PeakTotalUsage)
+	}
+}
+
+func TestSessionStats_Print(t *testing.T) {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	session := newSessionStats(started)
+	session.FailedCycles = 1
+
+	var out strings.Builder
+	session.Print(&out, started.Add(90*time.Second))
+	text := out.String()
+	for _, want := range []string{"Duration: 1m30s", "Cycles Run: 0 (1 failed)", "Critical Events: 0", "Peak Total Usage: N/A"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in summary, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Peak Pod Usage") {
+		t.Errorf("peak pod should be omitted before any usage is seen, got:\n%s", text)
+	}
+}
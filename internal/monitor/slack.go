@@ -0,0 +1,53 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SlackNotifier posts critical memory problems to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a notifier for the given webhook URL and request timeout
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify posts a single message listing the given problems
+func (n *SlackNotifier) Notify(ctx context.Context, problems []Problem) error {
+	body, err := json.Marshal(map[string]string{"text": formatSlackMessage(problems)})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	if err := postPayload(ctx, n.httpClient, n.webhookURL, contentTypeJSON, nil, bytes.NewReader(body)); err != nil {
+		return fmt.Errorf("failed to post slack message: %w", err)
+	}
+	return nil
+}
+
+// formatSlackMessage renders problems as a Slack message with one bullet per problem
+func formatSlackMessage(problems []Problem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ":rotating_light: *%d critical memory problem(s) detected*\n", len(problems))
+	for _, p := range problems {
+		fmt.Fprintf(&b, "• %s\n", p.Message)
+	}
+	return b.String()
+}
+
+// criticalPodKey identifies the pod a problem belongs to for de-duplication
+func criticalPodKey(p Problem) string {
+	return p.podID().String()
+}
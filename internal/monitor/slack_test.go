@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSlackTestServer(t *testing.T, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid slack payload: %v", err)
+		}
+		messages = append(messages, payload["text"])
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &messages
+}
+
+func criticalAnalysis(pods ...string) *AnalysisResult {
+	analysis := &AnalysisResult{}
+	for _, pod := range pods {
+		analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "prod", pod,
+			"is using %.1f%% of its memory limit", 97.0))
+	}
+	analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "prod", "quiet", "has no memory limit defined"))
+	return analysis
+}
+
+func TestNotifyCritical_DeduplicatesAcrossCycles(t *testing.T) {
+	server, messages := newSlackTestServer(t, http.StatusOK)
+	m := &MemoryMonitor{slack: NewSlackNotifier(server.URL, time.Second), notifiedPods: map[string]bool{}}
+	ctx := context.Background()
+
+	m.NotifyCritical(ctx, criticalAnalysis("api"))
+	m.NotifyCritical(ctx, criticalAnalysis("api"))
+	if len(*messages) != 1 {
+		t.Fatalf("expected 1 notification for a repeated critical pod, got %d", len(*messages))
+	}
+	if !strings.Contains((*messages)[0], "Pod prod/api is using 97.0% of its memory limit") {
+		t.Errorf("unexpected message: %s", (*messages)[0])
+	}
+	if strings.Contains((*messages)[0], "quiet") {
+		t.Errorf("warnings should not be notified: %s", (*messages)[0])
+	}
+
+	m.NotifyCritical(ctx, criticalAnalysis("api", "worker"))
+	if len(*messages) != 2 || strings.Contains((*messages)[1], "prod/api") {
+		t.Fatalf("expected only the new worker pod to be notified, got %v", *messages)
+	}
+
+	// A pod that recovers is reported again when it turns critical later
+	m.NotifyCritical(ctx, criticalAnalysis("worker"))
+	m.NotifyCritical(ctx, criticalAnalysis("api", "worker"))
+	if len(*messages) != 3 || !strings.Contains((*messages)[2], "prod/api") {
+		t.Fatalf("expected recovered pod to be notified again, got %v", *messages)
+	}
+}
+
+func TestNotifyCritical_RetriesAfterFailedPost(t *testing.T) {
+	server, messages := newSlackTestServer(t, http.StatusInternalServerError)
+	m := &MemoryMonitor{slack: NewSlackNotifier(server.URL, time.Second), notifiedPods: map[string]bool{}}
+
+	m.NotifyCritical(context.Background(), criticalAnalysis("api"))
+	m.NotifyCritical(context.Background(), criticalAnalysis("api"))
+	if len(*messages) != 2 {
+		t.Fatalf("expected a failed notification to be retried, got %d posts", len(*messages))
+	}
+}
+
+func TestNotifyCritical_DisabledWithoutWebhook(t *testing.T) {
+	m := &MemoryMonitor{notifiedPods: map[string]bool{}}
+	m.NotifyCritical(context.Background(), criticalAnalysis("api"))
+	if len(m.notifiedPods) != 0 {
+		t.Errorf("expected no tracking without a webhook, got %v", m.notifiedPods)
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// usageProblemKinds are the problem kinds derived from the usage sample, and so affected by its age
+var usageProblemKinds = map[string]bool{
+	ProblemKindRequestUsage: true,
+	ProblemKindLimitUsage:   true,
+	ProblemKindOverLimit:    true,
+
+	ProblemKindAbsoluteThreshold: true,
+}
+
+// metricsStale reports whether the pod's usage sample is older than maxAge; a zero maxAge disables the check
+func metricsStale(pod *k8s.PodMemoryInfo, maxAge time.Duration) bool {
+	return maxAge > 0 && pod.MetricsAge != nil && *pod.MetricsAge > maxAge
+}
+
+// deprioritizeStaleMetrics downgrades usage problems of pods with stale metrics to warnings
+// Such pods may have restarted since the sample was taken, so they no longer count as high usage
+func (a *AnalysisResult) deprioritizeStaleMetrics(maxAge time.Duration) {
+	staleAges := make(map[podID]time.Duration)
+	for i := range a.Report.Pods {
+		pod := &a.Report.Pods[i]
+		if metricsStale(pod, maxAge) {
+			staleAges[podIDOf(pod)] = *pod.MetricsAge
+		}
+	}
+	if len(staleAges) == 0 {
+		return
+	}
+
+	for i := range a.Problems {
+		p := &a.Problems[i]
+		age, stale := staleAges[p.podID()]
+		if !stale || !usageProblemKinds[p.Kind] {
+			continue
+		}
+		p.Severity = SeverityWarning
+		p.Message += fmt.Sprintf(" (metrics %s old)", formatDuration(age))
+		a.ProblemsFound[i] = p.Message
+	}
+
+	highUsage := a.HighUsagePods[:0]
+	for i := range a.HighUsagePods {
+		pod := a.HighUsagePods[i]
+		if _, stale := staleAges[podIDOf(&pod)]; !stale {
+			highUsage = append(highUsage, pod)
+		}
+	}
+	a.HighUsagePods = highUsage
+}
+
+// formatMetricsAge returns a table cell flagging a stale usage sample, or empty when it is fresh
+func formatMetricsAge(pod *k8s.PodMemoryInfo, maxAge time.Duration) string {
+	if !metricsStale(pod, maxAge) {
+		return ""
+	}
+	return "Metrics: " + formatDuration(*pod.MetricsAge) + " old"
+}
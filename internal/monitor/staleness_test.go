@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func staleAnalysis(age time.Duration) *AnalysisResult {
+	pod := k8s.PodMemoryInfo{Namespace: "prod", PodName: "api", MetricsAge: &age}
+	analysis := &AnalysisResult{
+		Report:        MemoryReport{Pods: []k8s.PodMemoryInfo{pod}},
+		HighUsagePods: []k8s.PodMemoryInfo{pod},
+	}
+	analysis.addProblem(newPodLimitProblem("prod", "api", 97.0))
+	analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoRequest, "prod", "api", "has no memory request defined"))
+	return analysis
+}
+
+func TestDeprioritizeStaleMetrics(t *testing.T) {
+	analysis := staleAnalysis(5 * time.Minute)
+	analysis.deprioritizeStaleMetrics(2 * time.Minute)
+
+	usage := analysis.Problems[0]
+	if usage.Severity != SeverityWarning || !strings.HasSuffix(usage.Message, "(metrics 5m old)") {
+		t.Errorf("expected a downgraded usage problem noting the age, got %+v", usage)
+	}
+	if analysis.ProblemsFound[0] != usage.Message {
+		t.Errorf("expected problem messages to stay in sync, got %q", analysis.ProblemsFound[0])
+	}
+	if analysis.Problems[1].Message != "Pod prod/api has no memory request defined" {
+		t.Errorf("configuration problems should not depend on metrics age, got %q", analysis.Problems[1].Message)
+	}
+	if len(analysis.HighUsagePods) != 0 {
+		t.Errorf("expected stale pods to leave the high usage list, got %d", len(analysis.HighUsagePods))
+	}
+}
+
+func TestDeprioritizeStaleMetrics_FreshOrDisabled(t *testing.T) {
+	for name, maxAge := range map[string]time.Duration{"fresh": 10 * time.Minute, "disabled": 0} {
+		t.Run(name, func(t *testing.T) {
+			analysis := staleAnalysis(5 * time.Minute)
+			analysis.deprioritizeStaleMetrics(maxAge)
+			if analysis.Problems[0].Severity != SeverityCritical || len(analysis.HighUsagePods) != 1 {
+				t.Errorf("expected the problem to stay critical, got %+v", analysis.Problems[0])
+			}
+		})
+	}
+}
+
+func TestFormatMetricsAge(t *testing.T) {
+	age := 3 * time.Minute
+	pod := &k8s.PodMemoryInfo{MetricsAge: &age}
+	if got := formatMetricsAge(pod, time.Minute); got != "Metrics: 3m old" {
+		t.Errorf("formatMetricsAge() = %q, want %q", got, "Metrics: 3m old")
+	}
+	if got := formatMetricsAge(pod, 5*time.Minute); got != "" {
+		t.Errorf("expected fresh metrics to be hidden, got %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// clearScreen moves the cursor home and clears the terminal so the view refreshes in place
+const clearScreen = "\033[H\033[2J"
+
+// PrintStatusOnly clears the terminal and prints a compact, count-only view of the analysis
+func (a *AnalysisResult) PrintStatusOnly(cfg *config.Config) {
+	summary := &a.Report.Summary
+	fmt.Print(clearScreen)
+	fmt.Printf("=== Kubernetes Memory Status (%s) ===\n", formatTimestamp(summary.Timestamp, cfg))
+	fmt.Printf("Pods: %d total | %d running | %d with metrics\n",
+		summary.TotalPods, summary.RunningPods, summary.PodsWithMetrics)
+	fmt.Printf("Over warning: %d | High usage: %d | Over limit: %d\n",
+		summary.WarningPodsCount, summary.CriticalPods, summary.PodsOverLimit)
+	fmt.Printf("Problems: %d | Total usage: %s\n", a.ProblemCount(), k8s.FormatMemoryTotal(&summary.TotalMemoryUsage))
+}
+
+// updateRiskCounts stores the pods-at-risk counts in the report summary so every output format carries them
+func (a *AnalysisResult) updateRiskCounts() {
+	summary := &a.Report.Summary
+	summary.CriticalPods = countUniquePods(a.HighUsagePods)
+	summary.WarningPodsCount = countUniquePods(a.WarningPods)
+	summary.PodsOverLimit = a.countPodsOverLimit()
+}
+
+// countPodsOverLimit counts pods whose usage has reached or exceeded their memory limit
+func (a *AnalysisResult) countPodsOverLimit() int {
+	count := 0
+	for i := range a.Report.Pods {
+		pod := &a.Report.Pods[i]
+		if pod.LimitUsagePercent != nil && *pod.LimitUsagePercent >= 100.0 {
+			count++
+		}
+	}
+	return count
+}
+
+// countUniquePods counts distinct pods, since a pod can be flagged more than once
+func countUniquePods(pods []k8s.PodMemoryInfo) int {
+	seen := make(map[podID]bool, len(pods))
+	for i := range pods {
+		seen[podIDOf(&pods[i])] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// suggestRequest sizes a request from observed usage: usage × factor, rounded up to a multiple of roundTo bytes
+func suggestRequest(usage *resource.Quantity, factor float64, roundTo int64) *resource.Quantity {
+	if usage == nil || usage.Value() <= 0 || roundTo <= 0 {
+		return nil
+	}
+	steps := math.Ceil(float64(usage.Value()) * factor / float64(roundTo))
+	return resource.NewQuantity(int64(steps)*roundTo, resource.BinarySI)
+}
+
+// applySuggestedRequests fills SuggestedRequest for every container that reports usage
+func applySuggestedRequests(pods []k8s.PodMemoryInfo, factor float64, roundTo int64) {
+	for i := range pods {
+		for j := range pods[i].Containers {
+			c := &pods[i].Containers[j]
+			c.SuggestedRequest = suggestRequest(c.CurrentUsage, factor, roundTo)
+		}
+	}
+}
+
+// printSuggestedRequests lists containers whose suggested request differs from the current one
+func printSuggestedRequests(pods []k8s.PodMemoryInfo, factor float64, roundTo int64) {
+	var lines []string
+	for i := range pods {
+		pod := &pods[i]
+		for j := range pod.Containers {
+			c := &pod.Containers[j]
+			if c.SuggestedRequest == nil {
+				continue
+			}
+			if c.MemoryRequest != nil && c.MemoryRequest.Cmp(*c.SuggestedRequest) == 0 {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("    %s/%s container %s: %s",
+				pod.Namespace, pod.PodName, c.ContainerName, formatRequestChange(c.MemoryRequest, c.SuggestedRequest)))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Printf("• Adjust memory requests for %d containers (usage × %.2f, rounded up to %s):\n",
+		len(lines), factor, k8s.FormatMemory(resource.NewQuantity(roundTo, resource.BinarySI)))
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+// formatRequestChange renders "current → suggested (±delta)", or "none → suggested" when unset
+func formatRequestChange(current, suggested *resource.Quantity) string {
+	if current == nil {
+		return fmt.Sprintf("none → %s", k8s.FormatMemory(suggested))
+	}
+	delta := suggested.DeepCopy()
+	delta.Sub(*current)
+	sign := "+"
+	if delta.Sign() < 0 {
+		sign = ""
+	}
+	return fmt.Sprintf("%s → %s (%s%s)", k8s.FormatMemory(current), k8s.FormatMemory(suggested), sign, k8s.FormatMemory(&delta))
+}
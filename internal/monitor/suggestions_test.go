@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const mi = 1024 * 1024
+
+// captureStdout returns everything fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	fn()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	buf := new(strings.Builder)
+	_, _ = io.Copy(buf, r)
+	return buf.String()
+}
+
+func TestSuggestRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usage    *resource.Quantity
+		factor   float64
+		roundTo  int64
+		expected int64
+	}{
+		{name: "rounds up to next step", usage: resource.NewQuantity(100*mi, resource.BinarySI), factor: 1.2, roundTo: 32 * mi, expected: 128 * mi},
+		{name: "exact multiple stays", usage: resource.NewQuantity(80*mi, resource.BinarySI), factor: 1.2, roundTo: 32 * mi, expected: 96 * mi},
+		{name: "small usage rounds to one step", usage: resource.NewQuantity(1*mi, resource.BinarySI), factor: 1.2, roundTo: 32 * mi, expected: 32 * mi},
+		{name: "custom factor and step", usage: resource.NewQuantity(500*mi, resource.BinarySI), factor: 1.5, roundTo: 64 * mi, expected: 768 * mi},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := suggestRequest(tc.usage, tc.factor, tc.roundTo)
+			if got == nil || got.Value() != tc.expected {
+				t.Fatalf("suggestRequest() = %v, want %d bytes", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSuggestRequest_NoUsage(t *testing.T) {
+	if got := suggestRequest(nil, 1.2, 32*mi); got != nil {
+		t.Fatalf("expected no suggestion without metrics, got %v", got)
+	}
+}
+
+func TestPrintSuggestedRequests(t *testing.T) {
+	pods := []k8s.PodMemoryInfo{
+		{
+			Namespace: "prod",
+			PodName:   "api",
+			Containers: []k8s.ContainerMemoryInfo{
+				{ContainerName: "app", CurrentUsage: resource.NewQuantity(100*mi, resource.BinarySI), MemoryRequest: resource.NewQuantity(512*mi, resource.BinarySI)},
+				{ContainerName: "sidecar", CurrentUsage: resource.NewQuantity(10*mi, resource.BinarySI)},
+				{ContainerName: "sized", CurrentUsage: resource.NewQuantity(80*mi, resource.BinarySI), MemoryRequest: resource.NewQuantity(96*mi, resource.BinarySI)},
+				{ContainerName: "no-metrics", MemoryRequest: resource.NewQuantity(64*mi, resource.BinarySI)},
+			},
+		},
+	}
+	applySuggestedRequests(pods, 1.2, 32*mi)
+
+	output := captureStdout(t, func() { printSuggestedRequests(pods, 1.2, 32*mi) })
+
+	for _, expected := range []string{
+		"Adjust memory requests for 2 containers (usage × 1.20, rounded up to 32.0 MB)",
+		"prod/api container app: 512.0 MB → 128.0 MB (-384.0 MB)",
+		"prod/api container sidecar: none → 32.0 MB",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "sized") || strings.Contains(output, "no-metrics") {
+		t.Errorf("expected unchanged and metric-less containers to be skipped:\n%s", output)
+	}
+}
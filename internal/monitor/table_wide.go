@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// wideTableHeader lists the columns of the table-wide output
+var wideTableHeader = []string{"NAMESPACE", "POD", "STATUS", "NODE", "QOS", "AGE",
+	"USAGE", "REQUEST", "REQ%", "LIMIT", "LIM%", "HEADROOM"}
+
+// printWideTable prints one aligned row per pod with placement details, followed by its containers
+func (r *MemoryReport) printWideTable(pods []k8s.PodMemoryInfo, cfg *config.Config) {
+	fmt.Print(r.renderWideTable(pods, cfg))
+}
+
+// renderWideTable lays out the given pods of the report and their containers as tab-aligned columns
+func (r *MemoryReport) renderWideTable(pods []k8s.PodMemoryInfo, cfg *config.Config) string {
+	now := r.Summary.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+	showMetadata := len(cfg.Labels) > 0 || len(cfg.Annotations) > 0 || len(cfg.EnvVars) > 0
+
+	header := append([]string{}, wideTableHeader...)
+	if r.MultiCluster() {
+		header = append([]string{"CLUSTER"}, header...)
+	}
+	if cfg.ShowImages {
+		header = append(header, "IMAGE")
+	}
+	if showMetadata {
+		header = append(header, "METADATA")
+	}
+	if cfg.Explain {
+		header = append(header, "REASON")
+	}
+	table := newAlignedTable(cfg.UseColor)
+	table.addRow("", "", header...)
+
+	for i := range pods {
+		pod := &pods[i]
+		pod.CalculateUsagePercent()
+		row := []string{
+			pod.Namespace,
+			pod.PodName,
+			podStateInfo(pod),
+			valueOrNA(pod.NodeName),
+			valueOrNA(pod.QOSClass),
+			formatAge(pod.CreatedAt, now),
+			k8s.FormatMemory(pod.CurrentUsage),
+			k8s.FormatMemory(pod.MemoryRequest),
+			k8s.FormatPercent(pod.UsagePercent),
+			k8s.FormatMemory(pod.MemoryLimit),
+			k8s.FormatPercent(pod.LimitUsagePercent),
+			k8s.FormatMemory(pod.Headroom),
+		}
+		if r.MultiCluster() {
+			row = append([]string{pod.Cluster}, row...)
+		}
+		if cfg.ShowImages {
+			row = append(row, "")
+		}
+		if showMetadata {
+			metadata := append(formatRequestedLabels(pod.Labels, cfg.Labels),
+				formatRequestedAnnotations(pod.Annotations, cfg.Annotations)...)
+			metadata = append(metadata, formatRequestedEnvVars(pod, cfg.EnvVars)...)
+			row = append(row, strings.Join(metadata, ", "))
+		}
+		status := getMemoryStatus(pod, cfg)
+		if cfg.Explain {
+			row = append(row, status.explanation())
+		}
+		table.addRow(podStatusSymbol(pod, cfg), status.Status, row...)
+		if cfg.CompactPods {
+			continue
+		}
+
+		for j := range pod.Containers {
+			c := pod.Containers[j]
+			c.CalculateUsagePercent()
+			row := []string{"", "  - " + c.ContainerName, "", "", "", "",
+				k8s.FormatMemory(c.CurrentUsage),
+				k8s.FormatMemory(c.MemoryRequest),
+				k8s.FormatPercent(c.UsagePercent),
+				k8s.FormatMemory(c.MemoryLimit),
+				k8s.FormatPercent(c.LimitUsagePercent),
+				k8s.FormatMemory(c.Headroom),
+			}
+			if r.MultiCluster() {
+				row = append([]string{""}, row...)
+			}
+			if cfg.ShowImages {
+				row = append(row, shortImageName(c.Image))
+			}
+			table.addRow("", "", row...)
+		}
+	}
+	return table.String()
+}
+
+// formatAge renders how long ago a pod was created in the largest whole unit, like kubectl
+func formatAge(created, now time.Time) string {
+	if created.IsZero() {
+		return "N/A"
+	}
+	return formatDuration(now.Sub(created))
+}
+
+// formatDuration renders a duration in its largest whole unit (s, m, h or d)
+func formatDuration(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// valueOrNA returns value, or N/A when it is empty
+func valueOrNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
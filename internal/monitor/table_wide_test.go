@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func wideTestReport() *MemoryReport {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return &MemoryReport{
+		Summary: k8s.MemorySummary{Timestamp: now},
+		Pods: []k8s.PodMemoryInfo{
+			{
+				Namespace: "prod", PodName: "api-7d9f", Phase: "Running", Ready: true,
+				NodeName: "node-a", QOSClass: "Burstable", CreatedAt: now.Add(-3 * time.Hour),
+				CurrentUsage: qty(200 * mi), MemoryRequest: qty(256 * mi), MemoryLimit: qty(512 * mi),
+				Containers: []k8s.ContainerMemoryInfo{
+					{ContainerName: "app", Image: "registry.example.com/api:1.2", CurrentUsage: qty(200 * mi)},
+				},
+			},
+			{
+				Namespace: "kube-system", PodName: "dns", Phase: "Pending",
+				Labels: map[string]string{"team": "core"},
+			},
+		},
+	}
+}
+
+func TestRenderWideTable_AlignsColumns(t *testing.T) {
+	report := wideTestReport()
+	out := report.renderWideTable(report.Pods, &config.Config{MemoryWarningPercent: 80.0})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, two pods and one container row, got %d lines:\n%s", len(lines), out)
+	}
+
+	// Every row starts the USAGE column at the same display column
+	column := strings.Index(lines[0], "USAGE")
+	for i, want := range []string{"200.0 MB", "200.0 MB", "N/A"} {
+		if got := textAtColumn(lines[i+1], column); !strings.HasPrefix(got, want) {
+			t.Errorf("expected %q at the USAGE column of %q, got %q", want, lines[i+1], got)
+		}
+	}
+	for _, want := range []string{"node-a", "Burstable", "3h", "Running/Ready", "  - app", "N/A"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "IMAGE") || strings.Contains(out, "METADATA") {
+		t.Errorf("optional columns should be hidden by default:\n%s", out)
+	}
+}
+
+// displayWidth returns how many terminal columns text occupies, counting status emoji as two
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width++
+		if r > unicode.MaxLatin1 {
+			width++
+		}
+	}
+	return width
+}
+
+// textAtColumn returns the part of line starting at the given display column
+// Status emoji count as two columns, as terminals render them
+func textAtColumn(line string, column int) string {
+	width := 0
+	for i, r := range line {
+		if width >= column {
+			return line[i:]
+		}
+		width++
+		if r > unicode.MaxLatin1 {
+			width++
+		}
+	}
+	return ""
+}
+
+func TestRenderWideTable_OptionalColumns(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80.0, ShowImages: true, Labels: []string{"team"}}
+	report := wideTestReport()
+	out := report.renderWideTable(report.Pods, cfg)
+	for _, want := range []string{"IMAGE", "api:1.2", "METADATA", "team: core"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		created time.Time
+		want    string
+	}{
+		{"unknown", time.Time{}, "N/A"},
+		{"seconds", now.Add(-42 * time.Second), "42s"},
+		{"minutes", now.Add(-15 * time.Minute), "15m"},
+		{"hours", now.Add(-5 * time.Hour), "5h"},
+		{"days", now.Add(-50 * time.Hour), "2d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatAge(tt.created, now); got != tt.want {
+				t.Errorf("formatAge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderWideTable_Explain(t *testing.T) {
+	report := wideTestReport()
+	out := report.renderWideTable(report.Pods, &config.Config{MemoryWarningPercent: 80.0, Explain: true})
+	for _, want := range []string{"REASON", "(ok: request_usage 78.1% < 80%)", "(no_data: no usage metrics)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package monitor
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+// formatTimestamp renders t in the configured timestamp format, RFC3339 by default
+// Every printed timestamp goes through it so --timestamp-format applies to all outputs alike
+func formatTimestamp(t time.Time, cfg *config.Config) string {
+	switch cfg.TimestampFormat {
+	case config.TimestampEpoch:
+		return strconv.FormatInt(t.Unix(), 10)
+	case config.TimestampEpochMillis:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
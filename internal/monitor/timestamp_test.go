@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", "2024-05-01T12:00:00Z"},
+		{config.TimestampRFC3339, "2024-05-01T12:00:00Z"},
+		{config.TimestampEpoch, "1714564800"},
+		{config.TimestampEpochMillis, "1714564800500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatTimestamp(timestamp, &config.Config{TimestampFormat: tt.format}); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMemoryMonitor_TimestampUsesClockAndFormat(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TimestampFormat = config.TimestampEpoch
+	m := newTestMonitor(cfg)
+	m.SetClock(fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})
+
+	if got := m.Timestamp(); got != "1714564800" {
+		t.Errorf("expected the clock time as epoch seconds, got %q", got)
+	}
+}
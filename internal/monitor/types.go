@@ -18,10 +18,34 @@ const (
 	limitStateNone    = "None"
 )
 
+// shortDigestLength is how many digest characters are kept when displaying images
+const shortDigestLength = 12
+
+// maxRecommendationNamespaces caps how many offending namespaces are listed per recommendation
+const maxRecommendationNamespaces = 5
+
+// maxRecommendationPods caps how many unbounded pods are listed, heaviest first
+const maxRecommendationPods = 5
+
 // MemoryReport contains the complete memory report for the cluster
 type MemoryReport struct {
-	Summary k8s.MemorySummary   `json:"summary"`
-	Pods    []k8s.PodMemoryInfo `json:"pods"`
+	Summary     k8s.MemorySummary   `json:"summary"`
+	Pods        []k8s.PodMemoryInfo `json:"pods"`
+	PhaseFilter []string            `json:"phase_filter,omitempty"` // Phases listed in Pods; the summary covers all phases
+	Clusters    []string            `json:"clusters,omitempty"`     // Contexts merged into the report, multi-cluster runs only
+
+	// Pods left out of Pods by --exclude-label; the summary still counts them
+	ExcludedPods int `json:"excluded_pods,omitempty"`
+
+	// Allocatable memory per node, only collected for the node report
+	// Keys are cluster/node in multi-context runs, see nodeKey
+	NodeAllocatable map[string]resource.Quantity `json:"node_allocatable,omitempty"`
+
+	// Pods changed since the previous cycle with --watch-pods-changed-only, nil lists every pod
+	ChangedPods map[podID]bool `json:"-"`
+
+	// Number of the check that produced the report, counting from 1 for the monitor's first cycle
+	Cycle int `json:"cycle,omitempty"`
 }
 
 // AnalysisResult contains the analysis of memory usage patterns and issues
@@ -29,62 +53,207 @@ type AnalysisResult struct {
 	Report        MemoryReport        `json:"report"`
 	HighUsagePods []k8s.PodMemoryInfo `json:"high_usage_pods"`
 	WarningPods   []k8s.PodMemoryInfo `json:"warning_pods"`
+	Unschedulable []k8s.PodMemoryInfo `json:"unschedulable_pods,omitempty"` // Pending pods the scheduler could not place
 	ProblemsFound []string            `json:"problems_found"`
+	Problems      []Problem           `json:"problems"`
+	Transitions   []StatusTransition  `json:"transitions,omitempty"` // Status changes since the previous cycle, with --watch-threshold-crossings
+
+	// Problems left out of Problems and ProblemsFound by --max-problems, lowest severity first
+	DroppedProblems int  `json:"dropped_problems,omitempty"`
+	droppedCritical int  // Critical problems among DroppedProblems
+	maxProblems     int  // Problems kept at most, 0 for no limit
+	trimEarly       bool // Cut problems back while analyzing, off when stale downgrades or dedupe change them later
 }
 
 // PrintSummary prints a human-readable summary of the memory report
-func (r *MemoryReport) PrintSummary() {
+func (r *MemoryReport) PrintSummary(cfg *config.Config) {
 	fmt.Printf("\n")
 	fmt.Printf("=== Kubernetes Memory Report ===\n")
-	fmt.Printf("Generated at: %s\n", r.Summary.Timestamp.Format(time.RFC3339))
+	fmt.Printf("Generated at: %s\n", formatTimestamp(r.Summary.Timestamp, cfg))
 	fmt.Printf("\n")
 
 	fmt.Printf("Cluster Overview:\n")
 	fmt.Printf("  Namespaces: %d\n", r.Summary.NamespaceCount)
 	fmt.Printf("  Total Pods: %d\n", r.Summary.TotalPods)
+	if len(r.PhaseFilter) > 0 && len(r.Pods) != r.Summary.TotalPods {
+		fmt.Printf("  Pods Shown: %d (phases: %s)\n", len(r.Pods), strings.Join(r.PhaseFilter, ", "))
+	}
+	if r.ExcludedPods > 0 {
+		fmt.Printf("  Pods Excluded by Label: %d\n", r.ExcludedPods)
+	}
 	fmt.Printf("  Running Pods: %d\n", r.Summary.RunningPods)
 	fmt.Printf("  Pods with Metrics: %d\n", r.Summary.PodsWithMetrics)
+	if r.Summary.PodsWithMetrics > 0 {
+		fmt.Printf("  Usage Metric: working set from metrics-server (includes active page cache, not RSS)\n")
+	}
 	fmt.Printf("  Pods with Limits: %d\n", r.Summary.PodsWithLimits)
 	fmt.Printf("  Pods with Requests: %d\n", r.Summary.PodsWithRequests)
+	fmt.Printf("  Pods at Risk: %d critical | %d warning | %d over limit\n",
+		r.Summary.CriticalPods, r.Summary.WarningPodsCount, r.Summary.PodsOverLimit)
+	if timings := r.Summary.Timings; timings.Total > 0 {
+		fmt.Printf("  Collection Time: %d ms (pod list %d ms, metrics %d ms)\n",
+			timings.Total.Milliseconds(), timings.PodList.Milliseconds(), timings.Metrics.Milliseconds())
+	}
+	if r.Summary.Truncated {
+		fmt.Printf("  Truncated Report: only the first %d of %d namespaces were collected (--max-namespaces)\n",
+			r.Summary.NamespacesCollected, r.Summary.NamespaceCount)
+	} else if r.Summary.Partial {
+		fmt.Printf("  Partial Report: collection cancelled after %d namespaces\n", r.Summary.NamespacesCollected)
+	}
+	if len(r.Summary.ForbiddenNamespaces) > 0 {
+		fmt.Printf("  Forbidden Namespaces (partial data): %s\n", strings.Join(r.Summary.ForbiddenNamespaces, ", "))
+	}
 	fmt.Printf("\n")
 }
 
+// PrintCycleBanner prints the line separating watch cycles in table output
+func (r *MemoryReport) PrintCycleBanner(cfg *config.Config) {
+	fmt.Printf("===== Cycle %d @ %s =====\n", r.Cycle, formatTimestamp(r.Summary.Timestamp, cfg))
+}
+
 // PrintDetailedReport prints detailed pod-by-pod memory information
 func (r *MemoryReport) PrintDetailedReport(cfg *config.Config) {
-	r.PrintSummary()
+	r.PrintSummary(cfg)
 
 	if len(r.Pods) == 0 {
 		fmt.Printf("No pods found.\n")
 		return
 	}
 
+	pods := r.Pods
+	if cfg.OnlyProblems {
+		pods = podsWithProblems(r.Pods, cfg)
+		if len(pods) == 0 {
+			fmt.Printf("No pods with memory problems.\n")
+			return
+		}
+	}
+	if r.ChangedPods != nil {
+		pods = filterChangedPods(pods, r.ChangedPods)
+		if len(pods) == 0 {
+			fmt.Printf("No pod changes since the previous cycle.\n")
+			return
+		}
+	}
+
 	fmt.Printf("=== Detailed Pod Memory Information ===\n")
 
-	currentNamespace := ""
-	for i := range r.Pods {
-		pod := &r.Pods[i]
-		if pod.Namespace != currentNamespace {
-			currentNamespace = pod.Namespace
-			fmt.Printf("\nNamespace: %s\n", currentNamespace)
+	if cfg.Output == config.OutputFormatWide {
+		fmt.Printf("\n")
+		r.printWideTable(pods, cfg)
+		fmt.Printf("\n")
+		return
+	}
+
+	// Columns are aligned within each namespace block, or across all pods when sorted by headroom
+	groupByNamespace := cfg.SortBy != config.SortByHeadroom
+	for _, block := range podBlocks(pods, groupByNamespace) {
+		if groupByNamespace && r.MultiCluster() {
+			fmt.Printf("\nNamespace: %s (cluster %s)\n", block[0].Namespace, block[0].Cluster)
+			fmt.Printf("%s\n", strings.Repeat("-", 80))
+		} else if groupByNamespace {
+			fmt.Printf("\nNamespace: %s\n", block[0].Namespace)
 			fmt.Printf("%s\n", strings.Repeat("-", 80))
 		}
-
-		fmt.Printf("  %s\n", formatPodInfo(pod, cfg))
+		table := newAlignedTable(cfg.UseColor)
+		table.indent = "  "
+		for i := range block {
+			addPodRows(table, &block[i], cfg, r.MultiCluster())
+		}
+		fmt.Print(table.String())
 	}
 	fmt.Printf("\n")
 }
 
+// podsWithProblems keeps the pods whose memory status needs attention, dropping healthy pods and those without usage data
+func podsWithProblems(pods []k8s.PodMemoryInfo, cfg *config.Config) []k8s.PodMemoryInfo {
+	var result []k8s.PodMemoryInfo
+	for i := range pods {
+		pods[i].CalculateUsagePercent()
+		switch getMemoryStatus(&pods[i], cfg).Status {
+		case "ok", "no_data", statusCompleted:
+			continue
+		}
+		result = append(result, pods[i])
+	}
+	return result
+}
+
+// podBlocks splits consecutive pods into one block per cluster and namespace, or a single block when not grouping
+func podBlocks(pods []k8s.PodMemoryInfo, groupByNamespace bool) [][]k8s.PodMemoryInfo {
+	if !groupByNamespace {
+		return [][]k8s.PodMemoryInfo{pods}
+	}
+	var blocks [][]k8s.PodMemoryInfo
+	start := 0
+	for i := 1; i <= len(pods); i++ {
+		if i == len(pods) || pods[i].Namespace != pods[start].Namespace || pods[i].Cluster != pods[start].Cluster {
+			blocks = append(blocks, pods[start:i])
+			start = i
+		}
+	}
+	return blocks
+}
+
+// addPodRows adds a pod, its containers and its requested metadata to the detailed table
+// Section headings and metadata are kept as unaligned notes so they do not widen the columns
+func addPodRows(table *alignedTable, pod *k8s.PodMemoryInfo, cfg *config.Config, multiCluster bool) {
+	cells := podBaseCells(pod, multiCluster)
+	if age := formatMetricsAge(pod, cfg.MaxMetricsAge); age != "" {
+		cells = append(cells, age)
+	}
+	status := getMemoryStatus(pod, cfg)
+	if cfg.Explain {
+		cells = append(cells, status.explanation())
+	}
+	table.addRow(podStatusSymbol(pod, cfg), status.Status, cells...)
+	if cfg.CompactPods {
+		return
+	}
+	if len(pod.Containers) > 0 {
+		table.addNote(containerSectionTitle)
+	}
+	for i := range pod.Containers {
+		c := pod.Containers[i]
+		cells = append([]string{"   - " + c.ContainerName, ""}, containerMemoryCells(&c)...)
+		ratio := ""
+		if cfg.ShowRequestRatio {
+			ratio = formatRequestLimitRatio(&c)
+		}
+		if cfg.ShowImages && c.Image != "" {
+			cells = append(cells, ratio, "", "Image: "+shortImageName(c.Image))
+		} else if ratio != "" {
+			cells = append(cells, ratio)
+		}
+		table.addRow("", "", cells...)
+	}
+	if stats := formatContainerStats(pod, cfg); stats != "" {
+		table.addNote(stats)
+	}
+	if m := formatMetadataSection(pod, cfg); m != "" {
+		for _, line := range strings.Split(m, "\n") {
+			table.addNote(line)
+		}
+	}
+}
+
 // PrintCSV prints pod memory information in CSV format
 func (r *MemoryReport) PrintCSV(cfg *config.Config, showHeader bool) {
 	formatter := NewCSVFormatter()
 	formatter.FormatReport(r, cfg, showHeader)
 }
 
+// MultiCluster reports whether the report merges pods from more than one cluster
+func (r *MemoryReport) MultiCluster() bool {
+	return len(r.Clusters) > 1
+}
+
 // buildCSVRecord creates a CSV record for a container within a pod
-func buildCSVRecord(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo, cfg *config.Config, timestamp time.Time) []string {
-	record := []string{
-		timestamp.Format(time.RFC3339),
-		getContainerMemoryStatus(pod, container, cfg),
+func buildCSVRecord(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo, cfg *config.Config, timestamp time.Time,
+	multiCluster bool) []string {
+	status := getContainerMemoryStatus(pod, container, cfg).Status
+	record := csvLeadingColumns(formatTimestamp(timestamp, cfg), status, pod.Cluster, multiCluster)
+	record = append(record,
 		pod.Namespace,
 		pod.PodName,
 		pod.Phase,
@@ -95,8 +264,26 @@ func buildCSVRecord(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo,
 		formatPercentForCSV(container.UsagePercent),
 		formatPercentForCSV(container.LimitUsagePercent),
 		container.ContainerName,
+		formatBytesForCSV(container.Headroom),
+	)
+	if cfg.ShowImages {
+		record = append(record, container.Image)
+	}
+	if cfg.ShowRequestRatio {
+		record = append(record, formatPercentForCSV(container.RequestLimitRatio))
+	}
+	record = appendPodMetadataColumns(record, pod, cfg)
+
+	// Add environment variable values
+	for _, name := range cfg.EnvVars {
+		record = append(record, container.EnvVars[name])
 	}
 
+	return record
+}
+
+// appendPodMetadataColumns appends the requested label and annotation values of a pod
+func appendPodMetadataColumns(record []string, pod *k8s.PodMemoryInfo, cfg *config.Config) []string {
 	// Add label values
 	for _, label := range cfg.Labels {
 		if value, exists := pod.Labels[label]; exists {
@@ -108,7 +295,7 @@ func buildCSVRecord(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo,
 
 	// Add annotation values
 	for _, annotation := range cfg.Annotations {
-		if value, exists := pod.Annotations[annotation]; exists {
+		if value, exists := annotationValue(pod.Annotations, annotation); exists {
 			// Clean annotation values for CSV (remove newlines and quotes)
 			cleanValue := strings.ReplaceAll(strings.ReplaceAll(value, "\n", " "), "\r", " ")
 			record = append(record, cleanValue)
@@ -121,10 +308,9 @@ func buildCSVRecord(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo,
 }
 
 // buildCSVRecordForPod creates a CSV record for a pod without container breakdown
-func buildCSVRecordForPod(pod *k8s.PodMemoryInfo, cfg *config.Config, timestamp time.Time) []string {
-	record := []string{
-		timestamp.Format(time.RFC3339),
-		getMemoryStatus(pod, cfg),
+func buildCSVRecordForPod(pod *k8s.PodMemoryInfo, cfg *config.Config, timestamp time.Time, multiCluster bool) []string {
+	record := csvLeadingColumns(formatTimestamp(timestamp, cfg), getMemoryStatus(pod, cfg).Status, pod.Cluster, multiCluster)
+	record = append(record,
 		pod.Namespace,
 		pod.PodName,
 		pod.Phase,
@@ -135,31 +321,37 @@ func buildCSVRecordForPod(pod *k8s.PodMemoryInfo, cfg *config.Config, timestamp
 		formatPercentForCSV(pod.UsagePercent),
 		formatPercentForCSV(pod.LimitUsagePercent),
 		"", // empty container_name for pod-level record
+		formatBytesForCSV(pod.Headroom),
+	)
+	if cfg.ShowImages {
+		record = append(record, "") // no image for pod-level record
 	}
-
-	// Add label values
-	for _, label := range cfg.Labels {
-		if value, exists := pod.Labels[label]; exists {
-			record = append(record, value)
-		} else {
-			record = append(record, "")
-		}
+	if cfg.ShowRequestRatio {
+		record = append(record, "") // ratio is only computed per container
 	}
+	record = appendPodMetadataColumns(record, pod, cfg)
 
-	// Add annotation values
-	for _, annotation := range cfg.Annotations {
-		if value, exists := pod.Annotations[annotation]; exists {
-			// Clean annotation values for CSV (remove newlines and quotes)
-			cleanValue := strings.ReplaceAll(strings.ReplaceAll(value, "\n", " "), "\r", " ")
-			record = append(record, cleanValue)
-		} else {
-			record = append(record, "")
-		}
+	// Add environment variable values
+	for _, name := range cfg.EnvVars {
+		value, _ := pod.EnvVar(name)
+		record = append(record, value)
 	}
 
 	return record
 }
 
+// shortImageName drops the registry and repository path from an image reference
+// and abbreviates digests, e.g. "registry.example.com/team/api@sha256:0123…" becomes "api@sha256:0123456789ab"
+func shortImageName(image string) string {
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	if name, digest, ok := strings.Cut(image, "@sha256:"); ok && len(digest) > shortDigestLength {
+		image = name + "@sha256:" + digest[:shortDigestLength]
+	}
+	return image
+}
+
 // Helper functions for CSV formatting
 func formatBytesForCSV(q *resource.Quantity) string {
 	if q == nil {
@@ -175,102 +367,142 @@ func formatPercentForCSV(percent *float64) string {
 	return strconv.FormatFloat(*percent, 'f', 2, 64)
 }
 
-// getMemoryStatus determines the memory status of a pod for CSV output
-func getMemoryStatus(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
+// memoryStatus is a memory status together with the rule that decided it
+type memoryStatus struct {
+	Status string // e.g. "critical", "no_limit" or "ok"
+	Reason string // Deciding rule, e.g. "limit_usage 92.0% ≥ 90%"
+}
+
+// explanation describes the status and its deciding rule for --explain, e.g. "(critical: limit_usage 92.0% ≥ 90%)"
+func (s memoryStatus) explanation() string {
+	return fmt.Sprintf("(%s: %s)", s.Status, s.Reason)
+}
+
+// getMemoryStatus determines the memory status of a pod and the rule that decided it
+func getMemoryStatus(pod *k8s.PodMemoryInfo, cfg *config.Config) memoryStatus {
+	if podCompleted(pod) {
+		return memoryStatus{statusCompleted, "phase Succeeded"}
+	}
+
 	if pod.CurrentUsage == nil {
-		return "no_data"
+		return memoryStatus{"no_data", "no usage metrics"}
 	}
 
-	if status, missing := missingConfigStatus(pod); missing {
+	if status, missing := missingConfigStatus(pod.MemoryRequest, pod.MemoryLimit); missing {
 		return status
 	}
 
-	if isCritical(pod) {
-		return "critical"
+	return usageStatus(pod, pod.UsagePercent, pod.LimitUsagePercent, cfg)
+}
+
+// statusCompleted is the memory status of pods that ran to completion
+const statusCompleted = "completed"
+
+// podCompleted reports whether all the pod's containers exited successfully, as with a finished Job
+// Such pods are never ready, but that is their expected end state rather than a failure
+func podCompleted(pod *k8s.PodMemoryInfo) bool {
+	return pod.Phase == "Succeeded"
+}
+
+// getContainerMemoryStatus determines the memory status of a container and the rule that decided it
+func getContainerMemoryStatus(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo, cfg *config.Config) memoryStatus {
+	if podCompleted(pod) {
+		return memoryStatus{statusCompleted, "phase Succeeded"}
 	}
 
-	if isWarning(pod, cfg) {
-		return "warning"
+	if container.CurrentUsage == nil {
+		return memoryStatus{"no_data", "no usage metrics"}
 	}
 
-	if !pod.Ready || pod.Phase != "Running" {
-		return "not_ready"
+	if status, missing := missingConfigStatus(container.MemoryRequest, container.MemoryLimit); missing {
+		return status
 	}
 
-	return "ok"
+	return usageStatus(pod, container.UsagePercent, container.LimitUsagePercent, cfg)
 }
 
-// getContainerMemoryStatus determines the memory status of a container for CSV output
-func getContainerMemoryStatus(pod *k8s.PodMemoryInfo, container *k8s.ContainerMemoryInfo, cfg *config.Config) string {
-	if container.CurrentUsage == nil {
-		return "no_data"
+// missingConfigStatus reports the status of a pod or container lacking a memory request or limit
+func missingConfigStatus(request, limit *resource.Quantity) (memoryStatus, bool) {
+	switch {
+	case request == nil && limit == nil:
+		return memoryStatus{"no_config", "no memory request or limit"}, true
+	case request == nil:
+		return memoryStatus{"no_request", "no memory request"}, true
+	case limit == nil:
+		return memoryStatus{"no_limit", "no memory limit"}, true
+	default:
+		return memoryStatus{}, false
 	}
+}
 
-	if status, missing := missingContainerConfigStatus(container); missing {
-		return status
+// usageStatus applies the critical, warning and readiness rules, in that order, to a usage
+// given as percentages of the request and of the limit
+func usageStatus(pod *k8s.PodMemoryInfo, usagePercent, limitUsagePercent *float64, cfg *config.Config) memoryStatus {
+	if usagePercent != nil && *usagePercent >= criticalRequestPercent {
+		return memoryStatus{"critical", thresholdReason(config.PrimaryMetricRequest, usagePercent, "≥", criticalRequestPercent)}
 	}
 
-	if isContainerCritical(container) {
-		return "critical"
+	if limitUsagePercent != nil && *limitUsagePercent >= criticalLimitPercent {
+		return memoryStatus{"critical", thresholdReason(config.PrimaryMetricLimit, limitUsagePercent, "≥", criticalLimitPercent)}
 	}
 
-	if isContainerWarning(container, cfg) {
-		return "warning"
+	primary := primaryPercent(cfg, usagePercent, limitUsagePercent)
+	if overWarningThreshold(primary, cfg) {
+		return memoryStatus{"warning", thresholdReason(primaryMetricName(cfg), primary, "≥", cfg.MemoryWarningPercent)}
 	}
 
-	if !pod.Ready || pod.Phase != "Running" {
-		return "not_ready"
+	if pod.Phase != "Running" {
+		return memoryStatus{"not_ready", "phase " + valueOrNA(pod.Phase)}
+	}
+	if !pod.Ready {
+		reason := "not ready"
+		if condition := pod.NotReadyReason(); condition != "" {
+			reason += " (" + condition + ")"
+		}
+		return memoryStatus{"not_ready", reason}
 	}
 
-	return "ok"
+	if primary == nil {
+		return memoryStatus{"ok", "below all thresholds"}
+	}
+	return memoryStatus{"ok", thresholdReason(primaryMetricName(cfg), primary, "<", cfg.MemoryWarningPercent)}
 }
 
-func missingConfigStatus(pod *k8s.PodMemoryInfo) (string, bool) {
-	switch {
-	case pod.MemoryRequest == nil && pod.MemoryLimit == nil:
-		return "no_config", true
-	case pod.MemoryRequest == nil:
-		return "no_request", true
-	case pod.MemoryLimit == nil:
-		return "no_limit", true
-	default:
-		return "", false
-	}
+// thresholdReason describes how a usage percentage compares with a threshold, e.g. "request_usage 85.0% ≥ 80%"
+func thresholdReason(metric string, percent *float64, comparison string, threshold float64) string {
+	return fmt.Sprintf("%s_usage %s %s %s%%", metric, k8s.FormatPercent(percent), comparison,
+		strconv.FormatFloat(threshold, 'f', -1, 64))
 }
 
-func missingContainerConfigStatus(container *k8s.ContainerMemoryInfo) (string, bool) {
-	switch {
-	case container.MemoryRequest == nil && container.MemoryLimit == nil:
-		return "no_config", true
-	case container.MemoryRequest == nil:
-		return "no_request", true
-	case container.MemoryLimit == nil:
-		return "no_limit", true
-	default:
-		return "", false
-	}
+func isWarning(pod *k8s.PodMemoryInfo, cfg *config.Config) bool {
+	return overWarningThreshold(primaryPercent(cfg, pod.UsagePercent, pod.LimitUsagePercent), cfg)
 }
 
-func isCritical(pod *k8s.PodMemoryInfo) bool {
-	if pod.UsagePercent != nil && *pod.UsagePercent >= 95.0 {
-		return true
+// primaryPercent picks the percentage the warning threshold applies to: usage vs limit or usage vs request
+func primaryPercent(cfg *config.Config, usagePercent, limitUsagePercent *float64) *float64 {
+	if cfg.LimitIsPrimary() {
+		return limitUsagePercent
 	}
-	return pod.LimitUsagePercent != nil && *pod.LimitUsagePercent >= 90.0
+	return usagePercent
 }
 
-func isContainerCritical(container *k8s.ContainerMemoryInfo) bool {
-	if container.UsagePercent != nil && *container.UsagePercent >= 95.0 {
-		return true
+// primaryMetricName names the quantity the warning threshold is a percentage of
+func primaryMetricName(cfg *config.Config) string {
+	if cfg.LimitIsPrimary() {
+		return config.PrimaryMetricLimit
 	}
-	return container.LimitUsagePercent != nil && *container.LimitUsagePercent >= 90.0
+	return config.PrimaryMetricRequest
 }
 
-func isWarning(pod *k8s.PodMemoryInfo, cfg *config.Config) bool {
-	return pod.UsagePercent != nil && *pod.UsagePercent >= cfg.MemoryWarningPercent
+// overWarningThreshold reports whether a known percentage reaches the configured warning threshold
+func overWarningThreshold(percent *float64, cfg *config.Config) bool {
+	return percent != nil && *percent >= cfg.MemoryWarningPercent
 }
 
-func isContainerWarning(container *k8s.ContainerMemoryInfo, cfg *config.Config) bool {
-	return container.UsagePercent != nil && *container.UsagePercent >= cfg.MemoryWarningPercent
+// PrintJSON prints the analysis as JSON Lines
+func (a *AnalysisResult) PrintJSON(cfg *config.Config) {
+	formatter := NewJSONFormatter()
+	formatter.FormatAnalysis(a, cfg)
 }
 
 // PrintAnalysis prints the analysis results with warnings and recommendations
@@ -280,112 +512,268 @@ func (a *AnalysisResult) PrintAnalysis(cfg *config.Config) {
 }
 
 // formatPodInfo formats a single pod's memory information
-func formatPodInfo(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
-	var parts []string
-	parts = append(parts, formatPodBaseInfo(pod))
-	if c := formatContainerSection(pod.Containers); c != "" {
+func formatPodInfo(pod *k8s.PodMemoryInfo, cfg *config.Config, multiCluster bool) string {
+	base := formatPodBaseInfo(pod, cfg, multiCluster)
+	if age := formatMetricsAge(pod, cfg.MaxMetricsAge); age != "" {
+		base += " | " + age
+	}
+	status := getMemoryStatus(pod, cfg)
+	if cfg.Explain {
+		base += " " + status.explanation()
+	}
+	if cfg.UseColor {
+		base = colorize(base, status.Status)
+	}
+	if cfg.CompactPods {
+		return base
+	}
+	parts := []string{base}
+	if c := formatContainerSection(pod.Containers, cfg); c != "" {
 		parts = append(parts, c)
 	}
+	if stats := formatContainerStats(pod, cfg); stats != "" {
+		parts = append(parts, stats)
+	}
 	if m := formatMetadataSection(pod, cfg); m != "" {
 		parts = append(parts, m)
 	}
 	return strings.Join(parts, "\n")
 }
 
-func podStatusSymbol(pod *k8s.PodMemoryInfo) string {
+// podStatusSymbol returns the configured symbol for the pod's state, falling back to the default emoji
+func podStatusSymbol(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
+	if podCompleted(pod) {
+		return symbolOrDefault(cfg.SymbolCompleted, config.DefaultSymbolCompleted)
+	}
 	if pod.CurrentUsage == nil {
-		return "⚪"
+		return symbolOrDefault(cfg.SymbolNoData, config.DefaultSymbolNoData)
 	}
 	if pod.Ready && pod.Phase == "Running" {
-		return "🟢"
+		return symbolOrDefault(cfg.SymbolOK, config.DefaultSymbolOK)
 	}
 	if pod.Phase == "Pending" {
-		return "🟡"
+		return symbolOrDefault(cfg.SymbolWarning, config.DefaultSymbolWarning)
 	}
-	return "🔴"
+	return symbolOrDefault(cfg.SymbolCritical, config.DefaultSymbolCritical)
 }
 
-func formatPodBaseInfo(pod *k8s.PodMemoryInfo) string {
-	pod.CalculateUsagePercent()
+func symbolOrDefault(symbol, fallback string) string {
+	if symbol == "" {
+		return fallback
+	}
+	return symbol
+}
+
+// podStateInfo describes the pod phase and readiness, with the reason when not ready
+func podStateInfo(pod *k8s.PodMemoryInfo) string {
 	readyStatus := "Ready"
-	if !pod.Ready {
+	if podCompleted(pod) {
+		readyStatus = "Completed"
+	} else if !pod.Ready {
 		readyStatus = "NotReady"
+		if reason := pod.NotReadyReason(); reason != "" {
+			readyStatus += " (" + reason + ")"
+		}
 	}
-	stateInfo := fmt.Sprintf("[%s/%s]", pod.Phase, readyStatus)
+	return pod.Phase + "/" + readyStatus
+}
+
+func formatPodBaseInfo(pod *k8s.PodMemoryInfo, cfg *config.Config, multiCluster bool) string {
+	cells := podBaseCells(pod, multiCluster)
+	return fmt.Sprintf("%s %s %s | %s", podStatusSymbol(pod, cfg), cells[0], cells[1], strings.Join(cells[2:], " | "))
+}
+
+// podBaseCells returns the pod identity, state, memory and limit state fields shown for a pod
+// The identity includes the cluster when the report merges several clusters
+func podBaseCells(pod *k8s.PodMemoryInfo, multiCluster bool) []string {
+	pod.CalculateUsagePercent()
 	limState, reqState := limitState(pod)
-	return fmt.Sprintf("%s %s %s | Usage: %s | Request: %s (%s) | Limit: %s (%s) | Limits: %s | Requests: %s",
-		podStatusSymbol(pod),
-		fmt.Sprintf("%s/%s", pod.Namespace, pod.PodName),
-		stateInfo,
-		k8s.FormatMemory(pod.CurrentUsage),
-		k8s.FormatMemory(pod.MemoryRequest),
-		k8s.FormatPercent(pod.UsagePercent),
-		k8s.FormatMemory(pod.MemoryLimit),
-		k8s.FormatPercent(pod.LimitUsagePercent),
-		limState,
-		reqState,
-	)
+	id := podIDOf(pod)
+	if !multiCluster {
+		id.Cluster = ""
+	}
+	return []string{
+		id.String(),
+		"[" + podStateInfo(pod) + "]",
+		"Usage: " + k8s.FormatMemory(pod.CurrentUsage),
+		fmt.Sprintf("Request: %s (%s)", k8s.FormatMemory(pod.MemoryRequest), k8s.FormatPercent(pod.UsagePercent)),
+		fmt.Sprintf("Limit: %s (%s)", k8s.FormatMemory(pod.MemoryLimit), k8s.FormatPercent(pod.LimitUsagePercent)),
+		"Headroom: " + k8s.FormatMemory(pod.Headroom),
+		"Limits: " + limState,
+		"Requests: " + reqState,
+	}
+}
+
+// containerMemoryCells returns the memory fields shown for a container
+func containerMemoryCells(c *k8s.ContainerMemoryInfo) []string {
+	c.CalculateUsagePercent()
+	usage := "Usage: " + k8s.FormatMemory(c.CurrentUsage)
+	if c.PodUsageShare != nil {
+		usage += fmt.Sprintf(" (%.0f%% of pod)", *c.PodUsageShare)
+	}
+	return []string{
+		usage,
+		fmt.Sprintf("Request: %s (%s)", k8s.FormatMemory(c.MemoryRequest), k8s.FormatPercent(c.UsagePercent)),
+		fmt.Sprintf("Limit: %s (%s)", k8s.FormatMemory(c.MemoryLimit), k8s.FormatPercent(c.LimitUsagePercent)),
+		"Headroom: " + k8s.FormatMemory(c.Headroom),
+	}
 }
 
-func formatContainerSection(containers []k8s.ContainerMemoryInfo) string {
+// formatRequestLimitRatio returns the container's request as a percent of its limit, for auditing QoS intent
+// The ratio is computed when the container info is built or its usage percentages are calculated
+func formatRequestLimitRatio(c *k8s.ContainerMemoryInfo) string {
+	return "Req/Limit: " + k8s.FormatPercent(c.RequestLimitRatio)
+}
+
+// containerSectionTitle introduces the containers listed under a pod
+const containerSectionTitle = "      🧩 Containers:"
+
+func formatContainerSection(containers []k8s.ContainerMemoryInfo, cfg *config.Config) string {
 	if len(containers) == 0 {
 		return ""
 	}
 	var b strings.Builder
-	b.WriteString("      🧩 Containers:")
+	b.WriteString(containerSectionTitle)
 	for i := range containers {
 		c := containers[i]
-		c.CalculateUsagePercent()
-		b.WriteString("\n        - " + c.ContainerName)
-		b.WriteString(" | Usage: " + k8s.FormatMemory(c.CurrentUsage))
-		b.WriteString(" | Request: " + k8s.FormatMemory(c.MemoryRequest))
-		b.WriteString(" (" + k8s.FormatPercent(c.UsagePercent) + ") | Limit: ")
-		b.WriteString(k8s.FormatMemory(c.MemoryLimit))
-		b.WriteString(" (" + k8s.FormatPercent(c.LimitUsagePercent) + ")")
+		b.WriteString("\n        - " + c.ContainerName + " | " + strings.Join(containerMemoryCells(&c), " | "))
+		if cfg.ShowRequestRatio {
+			b.WriteString(" | " + formatRequestLimitRatio(&c))
+		}
+		if cfg.ShowImages && c.Image != "" {
+			b.WriteString(" | Image: " + shortImageName(c.Image))
+		}
 	}
 	return b.String()
 }
 
 // printRecommendations prints actionable recommendations based on the analysis
-func printRecommendations(a *AnalysisResult) {
+func printRecommendations(a *AnalysisResult, cfg *config.Config) {
 	fmt.Printf("📋 Recommendations:\n")
 
-	podsWithoutLimits := 0
-	podsWithoutRequests := 0
+	missingLimits, missingRequests := countContainersMissingConfig(a.Report.Pods)
 
-	for i := range a.Report.Pods {
-		pod := &a.Report.Pods[i]
-		if pod.MemoryLimit == nil {
-			podsWithoutLimits++
-		}
-		if pod.MemoryRequest == nil {
-			podsWithoutRequests++
-		}
+	if total := sumCounts(missingLimits); total > 0 {
+		fmt.Printf("• Set memory limits for %d containers across %d namespaces to prevent OOM kills and resource contention\n",
+			total, len(missingLimits))
+		printTopNamespaces(missingLimits)
+		printTopUnboundedPods(a.Report.Pods)
 	}
 
-	if podsWithoutLimits > 0 {
-		fmt.Printf("• Set memory limits for %d pods to prevent OOM kills and resource contention\n", podsWithoutLimits)
+	if total := sumCounts(missingRequests); total > 0 {
+		fmt.Printf("• Set memory requests for %d containers across %d namespaces to enable proper scheduling\n",
+			total, len(missingRequests))
+		printTopNamespaces(missingRequests)
 	}
 
-	if podsWithoutRequests > 0 {
-		fmt.Printf("• Set memory requests for %d pods to enable proper scheduling\n", podsWithoutRequests)
+	if cfg.SuggestRequests {
+		printSuggestedRequests(a.Report.Pods, cfg.SuggestFactor, cfg.SuggestRoundToBytes())
 	}
 
 	if len(a.HighUsagePods) > 0 {
 		fmt.Printf("• Monitor %d high-usage pods closely - consider scaling or optimization\n", len(a.HighUsagePods))
 	}
 
-	if a.Report.Summary.PodsWithMetrics < a.Report.Summary.RunningPods {
+	if !cfg.NoMetrics && a.Report.Summary.PodsWithMetrics < a.Report.Summary.RunningPods {
 		fmt.Printf("• Consider installing/checking metrics-server for complete memory monitoring\n")
 	}
 
-	fmt.Printf("• Regular monitoring recommended with current threshold: %.1f%%\n", 80.0)
+	fmt.Printf("• Regular monitoring recommended with current threshold: %.1f%% of %s\n",
+		cfg.MemoryWarningPercent, primaryMetricName(cfg))
+}
+
+// countContainersMissingConfig counts, per namespace, the containers lacking a memory limit or request
+func countContainersMissingConfig(pods []k8s.PodMemoryInfo) (missingLimits, missingRequests map[string]int) {
+	missingLimits = make(map[string]int)
+	missingRequests = make(map[string]int)
+	for i := range pods {
+		pod := &pods[i]
+		for j := range pod.Containers {
+			c := &pod.Containers[j]
+			if c.MemoryLimit == nil {
+				missingLimits[pod.Namespace]++
+			}
+			if c.MemoryRequest == nil {
+				missingRequests[pod.Namespace]++
+			}
+		}
+	}
+	return missingLimits, missingRequests
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+// topNamespaces returns namespaces ordered by descending count (then name), capped at limit
+func topNamespaces(counts map[string]int, limit int) []string {
+	namespaces := make([]string, 0, len(counts))
+	for ns := range counts {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		if counts[namespaces[i]] != counts[namespaces[j]] {
+			return counts[namespaces[i]] > counts[namespaces[j]]
+		}
+		return namespaces[i] < namespaces[j]
+	})
+	if len(namespaces) > limit {
+		namespaces = namespaces[:limit]
+	}
+	return namespaces
+}
+
+// topUnboundedPods returns pods without a memory limit ordered by descending current usage
+// (then namespace/name), capped at limit. Pods without usage data cannot be ranked and are skipped.
+func topUnboundedPods(pods []k8s.PodMemoryInfo, limit int) []*k8s.PodMemoryInfo {
+	var unbounded []*k8s.PodMemoryInfo
+	for i := range pods {
+		pod := &pods[i]
+		if pod.MemoryLimit == nil && pod.CurrentUsage != nil {
+			unbounded = append(unbounded, pod)
+		}
+	}
+	sort.Slice(unbounded, func(i, j int) bool {
+		if cmp := unbounded[i].CurrentUsage.Cmp(*unbounded[j].CurrentUsage); cmp != 0 {
+			return cmp > 0
+		}
+		if unbounded[i].Namespace != unbounded[j].Namespace {
+			return unbounded[i].Namespace < unbounded[j].Namespace
+		}
+		return unbounded[i].PodName < unbounded[j].PodName
+	})
+	if len(unbounded) > limit {
+		unbounded = unbounded[:limit]
+	}
+	return unbounded
+}
+
+func printTopUnboundedPods(pods []k8s.PodMemoryInfo) {
+	top := topUnboundedPods(pods, maxRecommendationPods)
+	if len(top) == 0 {
+		return
+	}
+	fmt.Printf("  Highest-impact pods without a limit:\n")
+	for _, pod := range top {
+		fmt.Printf("    - set a limit on %s/%s (currently using %s unbounded)\n",
+			pod.Namespace, pod.PodName, k8s.FormatMemory(pod.CurrentUsage))
+	}
+}
+
+func printTopNamespaces(counts map[string]int) {
+	for _, ns := range topNamespaces(counts, maxRecommendationNamespaces) {
+		fmt.Printf("    - %s: %d containers\n", ns, counts[ns])
+	}
 }
 
 // formatMetadataSection formats labels and annotations for display based on configuration
 func formatMetadataSection(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
 	// Only show metadata if specifically requested
-	if len(cfg.Labels) == 0 && len(cfg.Annotations) == 0 {
+	if len(cfg.Labels) == 0 && len(cfg.Annotations) == 0 && len(cfg.EnvVars) == 0 && cfg.ExpectedMemory == "" {
 		return ""
 	}
 
@@ -411,9 +799,40 @@ func formatMetadataSection(pod *k8s.PodMemoryInfo, cfg *config.Config) string {
 		}
 	}
 
+	// Format requested container environment variables
+	if envVars := formatRequestedEnvVars(pod, cfg.EnvVars); len(envVars) > 0 {
+		if result.Len() > 0 {
+			result.WriteString("\n")
+		}
+		result.WriteString("      🔧 Env:")
+		for _, envPair := range envVars {
+			result.WriteString(fmt.Sprintf("\n        - %s", envPair))
+		}
+	}
+
+	if expected := formatExpectedMemory(pod, cfg); expected != "" {
+		if result.Len() > 0 {
+			result.WriteString("\n")
+		}
+		result.WriteString(expected)
+	}
+
 	return result.String()
 }
 
+// formatRequestedEnvVars formats the requested environment variables found on the pod's containers
+func formatRequestedEnvVars(pod *k8s.PodMemoryInfo, requestedEnvVars []string) []string {
+	result := make([]string, 0, len(requestedEnvVars))
+	for _, name := range requestedEnvVars {
+		if value, exists := pod.EnvVar(name); exists {
+			result = append(result, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+
+	sort.Strings(result) // Sort for consistent output
+	return result
+}
+
 // formatRequestedLabels extracts and formats only the requested labels from a pod
 func formatRequestedLabels(podLabels map[string]string, requestedLabels []string) []string {
 	if len(requestedLabels) == 0 || len(podLabels) == 0 {
@@ -439,7 +858,7 @@ func formatRequestedAnnotations(podAnnotations map[string]string, requestedAnnot
 
 	result := make([]string, 0, len(requestedAnnotations))
 	for _, requestedAnnotation := range requestedAnnotations {
-		if value, exists := podAnnotations[requestedAnnotation]; exists {
+		if value, exists := annotationValue(podAnnotations, requestedAnnotation); exists {
 			// Limit annotation values to prevent extremely long output
 			if len(value) > 80 {
 				value = value[:77] + "..."
@@ -457,7 +876,7 @@ func formatRequestedAnnotations(podAnnotations map[string]string, requestedAnnot
 func contains(pods []k8s.PodMemoryInfo, target *k8s.PodMemoryInfo) bool {
 	for i := range pods {
 		pod := &pods[i]
-		if pod.Namespace == target.Namespace && pod.PodName == target.PodName {
+		if podIDOf(pod) == podIDOf(target) {
 			return true
 		}
 	}
@@ -1,8 +1,10 @@
 package monitor
 
 import (
+	"encoding/json"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -105,7 +107,7 @@ func TestFormatPodInfo(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := formatPodInfo(&tc.pod, cfg)
+			result := formatPodInfo(&tc.pod, cfg, false)
 
 			// Check if the expected symbol is present
 			if !strings.Contains(result, tc.expectedSymbol) {
@@ -156,7 +158,7 @@ func TestFormatPodInfo_NoMetricsOverridesStatus(t *testing.T) {
 				CurrentUsage: nil, // No metrics - this should override status
 			}
 
-			result := formatPodInfo(&pod, cfg)
+			result := formatPodInfo(&pod, cfg, false)
 
 			if !strings.Contains(result, tc.shouldShow) {
 				t.Errorf("Expected grey symbol ⚪ for phase=%s ready=%t, but got: %s",
@@ -173,13 +175,206 @@ func TestFormatContainerSection_FormatsContainers(t *testing.T) {
 		MemoryRequest: resource.NewQuantity(200*1024*1024, resource.BinarySI),
 		MemoryLimit:   resource.NewQuantity(400*1024*1024, resource.BinarySI),
 	}
-	result := formatContainerSection([]k8s.ContainerMemoryInfo{c})
+	result := formatContainerSection([]k8s.ContainerMemoryInfo{c}, &config.Config{})
 	expected := "- app | Usage: 100.0 MB | Request: 200.0 MB (50.0%) | Limit: 400.0 MB (25.0%)"
 	if !strings.Contains(result, expected) {
 		t.Fatalf("expected %q in %q", expected, result)
 	}
 }
 
+func TestFormatContainerSection_ShowsImages(t *testing.T) {
+	c := k8s.ContainerMemoryInfo{ContainerName: "app", Image: "registry.example.com/team/app:1.2.3"}
+
+	if result := formatContainerSection([]k8s.ContainerMemoryInfo{c}, &config.Config{}); strings.Contains(result, "Image:") {
+		t.Fatalf("expected no image without --show-images, got %q", result)
+	}
+	result := formatContainerSection([]k8s.ContainerMemoryInfo{c}, &config.Config{ShowImages: true})
+	if !strings.Contains(result, "| Image: app:1.2.3") {
+		t.Fatalf("expected shortened image in %q", result)
+	}
+}
+
+func TestShortImageName(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected string
+	}{
+		{image: "nginx:1.25", expected: "nginx:1.25"},
+		{image: "registry.example.com:5000/team/api:v2", expected: "api:v2"},
+		{image: "ghcr.io/org/worker@sha256:0123456789abcdef0123456789abcdef", expected: "worker@sha256:0123456789ab"},
+	}
+
+	for _, tc := range testCases {
+		if got := shortImageName(tc.image); got != tc.expected {
+			t.Errorf("shortImageName(%q) = %q, want %q", tc.image, got, tc.expected)
+		}
+	}
+}
+
+func TestBuildCSVRecord_ImageColumn(t *testing.T) {
+	cfg := &config.Config{ShowImages: true}
+	pod := &k8s.PodMemoryInfo{Namespace: "default", PodName: "p"}
+	container := &k8s.ContainerMemoryInfo{ContainerName: "app", Image: "registry.example.com/team/app:1.2.3"}
+
+	header := NewCSVFormatter().buildHeader(cfg, false)
+	record := buildCSVRecord(pod, container, cfg, time.Now(), false)
+	if len(header) != len(record) {
+		t.Fatalf("header has %d columns but record has %d", len(header), len(record))
+	}
+	if header[13] != "image" || record[13] != "registry.example.com/team/app:1.2.3" {
+		t.Fatalf("expected full image in image column, got %q=%q", header[13], record[13])
+	}
+}
+
+func TestBuildCSVRecord_EnvVarColumns(t *testing.T) {
+	cfg := &config.Config{EnvVars: []string{"DAG_ID", "TASK_ID"}}
+	pod := &k8s.PodMemoryInfo{
+		Namespace: "airflow",
+		PodName:   "worker",
+		Containers: []k8s.ContainerMemoryInfo{
+			{ContainerName: "base", EnvVars: map[string]string{"DAG_ID": "daily_export"}},
+			{ContainerName: "sidecar"},
+		},
+	}
+
+	header := NewCSVFormatter().buildHeader(cfg, false)
+	record := buildCSVRecord(pod, &pod.Containers[1], cfg, time.Now(), false)
+	podRecord := buildCSVRecordForPod(pod, cfg, time.Now(), false)
+	if len(header) != len(record) || len(header) != len(podRecord) {
+		t.Fatalf("header has %d columns but records have %d and %d", len(header), len(record), len(podRecord))
+	}
+
+	last := len(header) - 1
+	if header[last-1] != "env_DAG_ID" || header[last] != "env_TASK_ID" {
+		t.Fatalf("expected env columns at the end, got header %v", header)
+	}
+	if record[last-1] != "" {
+		t.Errorf("expected empty DAG_ID for the sidecar row, got %q", record[last-1])
+	}
+	if podRecord[last-1] != "daily_export" || podRecord[last] != "" {
+		t.Errorf("expected pod row DAG_ID from the base container, got %v", podRecord[last-1:])
+	}
+}
+
+func TestBuildHeader_SuffixesDuplicateColumns(t *testing.T) {
+	cfg := &config.Config{
+		Labels:      []string{"app.tier", "app_tier", "team", "app.tier"},
+		Annotations: []string{"team"},
+	}
+	pod := &k8s.PodMemoryInfo{Namespace: "default", PodName: "p", Labels: map[string]string{"app.tier": "web", "app_tier": "api"}}
+
+	header := NewCSVFormatter().buildHeader(cfg, false)
+	record := buildCSVRecordForPod(pod, cfg, time.Now(), false)
+	if len(header) != len(record) {
+		t.Fatalf("header has %d columns but record has %d", len(header), len(record))
+	}
+
+	seen := make(map[string]bool)
+	for _, column := range header {
+		if seen[column] {
+			t.Errorf("duplicate column %q in header %v", column, header)
+		}
+		seen[column] = true
+	}
+
+	first := len(header) - 5
+	expected := []string{"label_app_tier", "label_app_tier_2", "label_team", "label_app_tier_3", "annotation_team"}
+	for i, column := range expected {
+		if header[first+i] != column {
+			t.Errorf("column %d: expected %q, got %q", first+i, column, header[first+i])
+		}
+	}
+	if record[first] != "web" || record[first+1] != "api" {
+		t.Errorf("expected label values to keep their positions, got %v", record[first:])
+	}
+}
+
+func TestBuildCSVRecord_TimestampFormat(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pod := &k8s.PodMemoryInfo{Namespace: "default", PodName: "p"}
+	container := &k8s.ContainerMemoryInfo{ContainerName: "app"}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"", "2024-05-01T12:00:00Z"},
+		{config.TimestampRFC3339, "2024-05-01T12:00:00Z"},
+		{config.TimestampEpoch, "1714564800"},
+		{config.TimestampEpochMillis, "1714564800000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := &config.Config{TimestampFormat: tt.format}
+			if got := buildCSVRecord(pod, container, cfg, timestamp, false)[0]; got != tt.expected {
+				t.Errorf("expected container row timestamp %q, got %q", tt.expected, got)
+			}
+			if got := buildCSVRecordForPod(pod, cfg, timestamp, false)[0]; got != tt.expected {
+				t.Errorf("expected pod row timestamp %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildCSVRecord_ClusterColumn(t *testing.T) {
+	cfg := &config.Config{}
+	pod := &k8s.PodMemoryInfo{Cluster: "prod-eu", Namespace: "default", PodName: "p"}
+	container := &k8s.ContainerMemoryInfo{ContainerName: "app"}
+
+	tests := []struct {
+		name         string
+		multiCluster bool
+		namespaceAt  int
+	}{
+		{"single cluster omits the column", false, 2},
+		{"multi-cluster inserts it before namespace", true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := NewCSVFormatter().buildHeader(cfg, tt.multiCluster)
+			record := buildCSVRecord(pod, container, cfg, time.Now(), tt.multiCluster)
+			podRecord := buildCSVRecordForPod(pod, cfg, time.Now(), tt.multiCluster)
+			if len(header) != len(record) || len(header) != len(podRecord) {
+				t.Fatalf("header has %d columns but records have %d and %d", len(header), len(record), len(podRecord))
+			}
+			if header[tt.namespaceAt] != "namespace" || record[tt.namespaceAt] != "default" || podRecord[tt.namespaceAt] != "default" {
+				t.Errorf("expected namespace at column %d, got header %v", tt.namespaceAt, header)
+			}
+			hasCluster := header[2] == "cluster" && record[2] == "prod-eu" && podRecord[2] == "prod-eu"
+			if hasCluster != tt.multiCluster {
+				t.Errorf("expected cluster column %v, got header %v record %v", tt.multiCluster, header, record)
+			}
+		})
+	}
+}
+
+func TestRequestLimitRatio_SectionAndCSVColumn(t *testing.T) {
+	cfg := &config.Config{ShowRequestRatio: true}
+	pod := &k8s.PodMemoryInfo{Namespace: "default", PodName: "p"}
+	container := &k8s.ContainerMemoryInfo{
+		ContainerName: "app",
+		MemoryRequest: resource.NewQuantity(256*1024*1024, resource.BinarySI),
+		MemoryLimit:   resource.NewQuantity(512*1024*1024, resource.BinarySI),
+	}
+
+	if result := formatContainerSection([]k8s.ContainerMemoryInfo{*container}, cfg); !strings.Contains(result, "| Req/Limit: 50.0%") {
+		t.Errorf("expected the request/limit ratio in the container section, got %q", result)
+	}
+
+	container.CalculateUsagePercent()
+	header := NewCSVFormatter().buildHeader(cfg, false)
+	record := buildCSVRecord(pod, container, cfg, time.Now(), false)
+	podRecord := buildCSVRecordForPod(pod, cfg, time.Now(), false)
+	if len(header) != len(record) || len(header) != len(podRecord) {
+		t.Fatalf("header has %d columns but records have %d and %d", len(header), len(record), len(podRecord))
+	}
+	if header[13] != "request_limit_percent" || record[13] != "50.00" {
+		t.Errorf("expected the ratio column, got %q=%q", header[13], record[13])
+	}
+}
+
 func TestFormatPodBaseInfo_FormatsBasicInfo(t *testing.T) {
 	pod := k8s.PodMemoryInfo{
 		PodName:       "app",
@@ -190,13 +385,30 @@ func TestFormatPodBaseInfo_FormatsBasicInfo(t *testing.T) {
 		MemoryRequest: resource.NewQuantity(100*1024*1024, resource.BinarySI),
 		MemoryLimit:   resource.NewQuantity(200*1024*1024, resource.BinarySI),
 	}
-	result := formatPodBaseInfo(&pod)
-	expected := "🟢 default/app [Running/Ready] | Usage: 50.0 MB | Request: 100.0 MB (50.0%) | Limit: 200.0 MB (25.0%) | Limits: All | Requests: All"
+	result := formatPodBaseInfo(&pod, &config.Config{}, false)
+	expected := "🟢 default/app [Running/Ready] | Usage: 50.0 MB | Request: 100.0 MB (50.0%) | Limit: 200.0 MB (25.0%) | Headroom: 150.0 MB | Limits: All | Requests: All"
 	if result != expected {
 		t.Fatalf("expected %q, got %q", expected, result)
 	}
 }
 
+func TestFormatPodBaseInfo_ShowsNotReadyReason(t *testing.T) {
+	pod := k8s.PodMemoryInfo{
+		PodName:   "app",
+		Namespace: "default",
+		Phase:     "Pending",
+		NotReadyConditions: []k8s.PodConditionInfo{
+			{Type: "PodScheduled", Reason: "Unschedulable"},
+			{Type: "Ready"},
+		},
+	}
+	result := formatPodBaseInfo(&pod, &config.Config{}, false)
+	expected := "[Pending/NotReady (PodScheduled: Unschedulable)]"
+	if !strings.Contains(result, expected) {
+		t.Fatalf("expected %q in %q", expected, result)
+	}
+}
+
 func TestGetMemoryStatus(t *testing.T) {
 	cfg := &config.Config{
 		MemoryWarningPercent: 80.0,
@@ -273,7 +485,7 @@ func TestGetMemoryStatus(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Calculate usage percentages
 			tt.pod.CalculateUsagePercent()
-			result := getMemoryStatus(&tt.pod, cfg)
+			result := getMemoryStatus(&tt.pod, cfg).Status
 			if result != tt.expected {
 				t.Errorf("getMemoryStatus() = %v, want %v", result, tt.expected)
 			}
@@ -309,7 +521,7 @@ func TestFormatPodInfo_IncludesContainerIdentifiers(t *testing.T) {
 		},
 	}
 
-	out := formatPodInfo(&pod, cfg)
+	out := formatPodInfo(&pod, cfg, false)
 	if !strings.Contains(out, "default/multi") {
 		t.Fatalf("base pod info missing")
 	}
@@ -330,7 +542,7 @@ func TestFormatPodInfo_ShowsLimitState(t *testing.T) {
 			{ContainerName: "b"},
 		},
 	}
-	out := formatPodInfo(&pod, cfg)
+	out := formatPodInfo(&pod, cfg, false)
 	if !strings.Contains(out, "Limit state: Partial") && !strings.Contains(out, "Limits: Partial") {
 		t.Fatalf("expected Partial limit state in output, got: %s", out)
 	}
@@ -409,12 +621,14 @@ func TestBuildCSVRecord(t *testing.T) {
 	}
 
 	// Calculate the actual values that will be returned
-	expectedStatus := getContainerMemoryStatus(pod, container, cfg)
+	expectedStatus := getContainerMemoryStatus(pod, container, cfg).Status
 	expectedUsageBytes := formatBytesForCSV(container.CurrentUsage)
 	expectedRequestBytes := formatBytesForCSV(container.MemoryRequest)
 	expectedLimitBytes := formatBytesForCSV(container.MemoryLimit)
 	expectedUsagePercent := formatPercentForCSV(container.UsagePercent)
 	expectedLimitUsagePercent := formatPercentForCSV(container.LimitUsagePercent)
+	container.CalculateUsagePercent()
+	expectedHeadroomBytes := strconv.FormatInt(300*1024*1024, 10)
 
 	expected := []string{
 		"2023-12-01T10:00:00Z",
@@ -429,12 +643,13 @@ func TestBuildCSVRecord(t *testing.T) {
 		expectedUsagePercent,
 		expectedLimitUsagePercent,
 		"app-container",
+		expectedHeadroomBytes,
 		"production", // env label
 		"backend",    // team label
 		"5",          // revision annotation
 	}
 
-	result := buildCSVRecord(pod, container, cfg, timestamp)
+	result := buildCSVRecord(pod, container, cfg, timestamp, false)
 
 	if len(result) != len(expected) {
 		t.Fatalf("Expected %d fields, got %d", len(expected), len(result))
@@ -476,12 +691,14 @@ func TestBuildCSVRecordForPod(t *testing.T) {
 	}
 
 	// Calculate the actual values that will be returned
-	expectedPodStatus := getMemoryStatus(pod, cfg)
+	expectedPodStatus := getMemoryStatus(pod, cfg).Status
 	expectedPodUsageBytes := formatBytesForCSV(pod.CurrentUsage)
 	expectedPodRequestBytes := formatBytesForCSV(pod.MemoryRequest)
 	expectedPodLimitBytes := formatBytesForCSV(pod.MemoryLimit)
 	expectedPodUsagePercent := formatPercentForCSV(pod.UsagePercent)
 	expectedPodLimitUsagePercent := formatPercentForCSV(pod.LimitUsagePercent)
+	pod.CalculateUsagePercent()
+	expectedPodHeadroomBytes := strconv.FormatInt(700*1024*1024, 10)
 
 	expected := []string{
 		"2023-12-01T15:30:00Z",
@@ -495,13 +712,14 @@ func TestBuildCSVRecordForPod(t *testing.T) {
 		expectedPodLimitBytes,
 		expectedPodUsagePercent,
 		expectedPodLimitUsagePercent,
-		"",           // empty container_name for pod-level record
+		"", // empty container_name for pod-level record
+		expectedPodHeadroomBytes,
 		"web-server", // app label
 		"v1.2.3",     // version label
 		"Deployment", // managed-by annotation
 	}
 
-	result := buildCSVRecordForPod(pod, cfg, timestamp)
+	result := buildCSVRecordForPod(pod, cfg, timestamp, false)
 
 	if len(result) != len(expected) {
 		t.Fatalf("Expected %d fields, got %d", len(expected), len(result))
@@ -557,14 +775,56 @@ func TestPrintAnalysis_FiltersPartialLimitPods(t *testing.T) {
 	buf := new(strings.Builder)
 	_, _ = io.Copy(buf, r)
 	out := buf.String()
+	// Recommendations legitimately name unbounded pods; only the High/Warning sections are checked here
+	sections, _, _ := strings.Cut(out, "📋 Recommendations:")
 
-	if strings.Contains(out, "partial") {
+	if strings.Contains(sections, "partial") {
 		t.Fatalf("expected pod with Partial limits to be omitted from High/Warning sections, got: %s", out)
 	}
 	if !strings.Contains(out, "all") {
 		t.Fatalf("expected pod with All limits to appear, got: %s", out)
 	}
 }
+
+func TestPrintAnalysis_ListsContainerHotspots(t *testing.T) {
+	cfg := &config.Config{MemoryWarningPercent: 80.0, SymbolCritical: "!!"}
+
+	pod := k8s.PodMemoryInfo{
+		Namespace: "ns", PodName: "grafana", Phase: "Running", Ready: true,
+		CurrentUsage: qty(600 * mi),
+		Containers: []k8s.ContainerMemoryInfo{
+			{ContainerName: "grafana", CurrentUsage: qty(500 * mi), MemoryRequest: qty(256 * mi), MemoryLimit: qty(512 * mi)},
+			{ContainerName: "sidecar", CurrentUsage: qty(100 * mi)},
+		},
+	}
+	pod.CalculateUsagePercent()
+	analysis := &AnalysisResult{Report: MemoryReport{Pods: []k8s.PodMemoryInfo{pod}}}
+
+	out := captureStdout(t, func() { analysis.PrintAnalysis(cfg) })
+
+	if !strings.Contains(out, "Container Hotspots (1):") {
+		t.Fatalf("expected one container hotspot, got: %s", out)
+	}
+	if !strings.Contains(out, "  !! ns/grafana/grafana | Usage: 500.0 MB") {
+		t.Errorf("expected the critical container with the configured symbol, got: %s", out)
+	}
+	if strings.Contains(out, "ns/grafana/sidecar") {
+		t.Errorf("expected the unconfigured sidecar to be omitted, got: %s", out)
+	}
+
+	// The same pod in two clusters gives one line per cluster
+	east, west := pod, pod
+	east.Cluster, west.Cluster = "east", "west"
+	multi := &AnalysisResult{Report: MemoryReport{Clusters: []string{"east", "west"}, Pods: []k8s.PodMemoryInfo{east, west}}}
+
+	out = captureStdout(t, func() { multi.PrintAnalysis(cfg) })
+
+	for _, expected := range []string{"  !! east/ns/grafana/grafana | Usage:", "  !! west/ns/grafana/grafana | Usage:"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in the hotspots, got: %s", expected, out)
+		}
+	}
+}
 func TestFormatRequestedAnnotations_TruncatesLongValues(t *testing.T) {
 	annotations := map[string]string{"key": strings.Repeat("a", 100)}
 	result := formatRequestedAnnotations(annotations, []string{"key"})
@@ -576,3 +836,473 @@ func TestFormatRequestedAnnotations_TruncatesLongValues(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, result[0])
 	}
 }
+
+func TestCountContainersMissingConfig_CountsPerNamespace(t *testing.T) {
+	pods := []k8s.PodMemoryInfo{
+		{
+			Namespace: "a",
+			Containers: []k8s.ContainerMemoryInfo{
+				{ContainerName: "app", MemoryLimit: qty(1), MemoryRequest: qty(1)},
+				{ContainerName: "sidecar"},
+			},
+		},
+		{
+			Namespace: "b",
+			Containers: []k8s.ContainerMemoryInfo{
+				{ContainerName: "app", MemoryRequest: qty(1)},
+				{ContainerName: "worker", MemoryRequest: qty(1)},
+			},
+		},
+	}
+
+	missingLimits, missingRequests := countContainersMissingConfig(pods)
+
+	if missingLimits["a"] != 1 || missingLimits["b"] != 2 {
+		t.Errorf("unexpected missing limits: %v", missingLimits)
+	}
+	if missingRequests["a"] != 1 || len(missingRequests) != 1 {
+		t.Errorf("unexpected missing requests: %v", missingRequests)
+	}
+	if top := topNamespaces(missingLimits, 1); len(top) != 1 || top[0] != "b" {
+		t.Errorf("expected namespace b as top offender, got %v", top)
+	}
+}
+
+func TestFormatPodInfo_ColorizesOnlyWhenEnabled(t *testing.T) {
+	pod := k8s.PodMemoryInfo{
+		Namespace: "ns", PodName: "p", Phase: "Running", Ready: true,
+		CurrentUsage:  qty(990),
+		MemoryRequest: qty(1000),
+		MemoryLimit:   qty(1000),
+	}
+
+	plain := formatPodInfo(&pod, &config.Config{MemoryWarningPercent: 80.0}, false)
+	if strings.Contains(plain, "\033[") {
+		t.Fatalf("expected no escape codes when color is disabled, got %q", plain)
+	}
+
+	colored := formatPodInfo(&pod, &config.Config{MemoryWarningPercent: 80.0, UseColor: true}, false)
+	if !strings.HasPrefix(colored, ansiRed) || !strings.Contains(colored, ansiReset) {
+		t.Fatalf("expected critical pod line wrapped in red, got %q", colored)
+	}
+}
+
+func TestPrintStatusOnly_PrintsCounts(t *testing.T) {
+	overLimit := k8s.PodMemoryInfo{Namespace: "ns", PodName: "over", LimitUsagePercent: pct(120)}
+	analysis := &AnalysisResult{
+		Report: MemoryReport{
+			Summary: k8s.MemorySummary{TotalPods: 3, RunningPods: 2, PodsWithMetrics: 2},
+			Pods:    []k8s.PodMemoryInfo{overLimit, {Namespace: "ns", PodName: "fine"}},
+		},
+		HighUsagePods: []k8s.PodMemoryInfo{overLimit, overLimit},
+		WarningPods:   []k8s.PodMemoryInfo{overLimit},
+		ProblemsFound: []string{"a", "b"},
+	}
+	analysis.updateRiskCounts()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	analysis.PrintStatusOnly(&config.Config{})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	buf := new(strings.Builder)
+	_, _ = io.Copy(buf, r)
+	out := buf.String()
+
+	for _, expected := range []string{
+		"Pods: 3 total | 2 running | 2 with metrics",
+		"Over warning: 1 | High usage: 1 | Over limit: 1",
+		"Problems: 2",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output, got: %s", expected, out)
+		}
+	}
+}
+
+func TestPrintJSON_ProblemsOnlyEmitsOneLinePerProblem(t *testing.T) {
+	analysis := &AnalysisResult{}
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "ns", "p", "is using %.1f%% of its memory limit", 95.0))
+	analysis.addProblem(newContainerProblem(SeverityWarning, ProblemKindNoLimit, "ns", "p", "c", "has no memory limit defined"))
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	analysis.PrintJSON(&config.Config{Output: config.OutputFormatJSON, ProblemsOnly: true})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	buf := new(strings.Builder)
+	_, _ = io.Copy(buf, r)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d: %s", len(lines), buf.String())
+	}
+	var problem Problem
+	if err := json.Unmarshal([]byte(lines[1]), &problem); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[1], err)
+	}
+	if problem.Kind != ProblemKindNoLimit || problem.Container != "c" || problem.Severity != SeverityWarning {
+		t.Errorf("unexpected problem decoded: %+v", problem)
+	}
+	if problem.Message != "Pod ns/p container c has no memory limit defined" {
+		t.Errorf("unexpected message: %q", problem.Message)
+	}
+}
+
+func TestPrintDetailedReport_AlignsColumnsWithinNamespace(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "api", Phase: "Running", Ready: true, CurrentUsage: qty(200 * mi),
+			Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app", CurrentUsage: qty(200 * mi)}}},
+		{Namespace: "prod", PodName: "a-much-longer-worker-name", Phase: "Pending", Labels: map[string]string{"team": "core"}},
+	}}
+	cfg := &config.Config{MemoryWarningPercent: 80.0, Labels: []string{"team"}}
+
+	out := captureStdout(t, func() { report.PrintDetailedReport(cfg) })
+
+	column := -1
+	var rows int
+	for _, line := range strings.Split(out, "\n") {
+		idx := strings.Index(line, "Usage:")
+		if idx < 0 {
+			continue
+		}
+		rows++
+		if column < 0 {
+			column = displayWidth(line[:idx])
+		} else if got := displayWidth(line[:idx]); got != column {
+			t.Errorf("Usage starts at column %d, expected %d: %q", got, column, line)
+		}
+	}
+	if rows != 3 {
+		t.Fatalf("expected two pod rows and one container row, got %d:\n%s", rows, out)
+	}
+	for _, want := range []string{"  🟢 prod/api ", "      🧩 Containers:\n        - app ", "      📏 Labels:\n        - team: core"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintRecommendations_UsesConfiguredThreshold(t *testing.T) {
+	analysis := &AnalysisResult{}
+	out := captureStdout(t, func() { printRecommendations(analysis, &config.Config{MemoryWarningPercent: 65.5}) })
+	if !strings.Contains(out, "current threshold: 65.5%") {
+		t.Errorf("expected the configured threshold in recommendations, got:\n%s", out)
+	}
+}
+
+func TestPrintRecommendations_RanksUnboundedPodsByUsage(t *testing.T) {
+	unbounded := func(ns, name string, usage int64) k8s.PodMemoryInfo {
+		pod := k8s.PodMemoryInfo{Namespace: ns, PodName: name, Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app"}}}
+		if usage > 0 {
+			pod.CurrentUsage = qty(usage)
+		}
+		return pod
+	}
+	bounded := unbounded("prod", "bounded", 8192*mi)
+	bounded.MemoryLimit = qty(16384 * mi)
+	analysis := &AnalysisResult{Report: MemoryReport{Pods: []k8s.PodMemoryInfo{
+		unbounded("prod", "small", 256*mi),
+		unbounded("prod", "big", 4096*mi),
+		unbounded("dev", "nometrics", 0),
+		bounded,
+		unbounded("dev", "medium", 1024*mi),
+	}}}
+
+	out := captureStdout(t, func() { printRecommendations(analysis, &config.Config{}) })
+
+	want := "    - set a limit on prod/big (currently using 4.00 GB unbounded)\n" +
+		"    - set a limit on dev/medium (currently using 1.00 GB unbounded)\n" +
+		"    - set a limit on prod/small (currently using 256.0 MB unbounded)\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected unbounded pods ranked by usage:\n%s\ngot:\n%s", want, out)
+	}
+	for _, absent := range []string{"prod/bounded", "dev/nometrics"} {
+		if strings.Contains(out, absent) {
+			t.Errorf("did not expect %s in the punch list:\n%s", absent, out)
+		}
+	}
+}
+
+func TestTopUnboundedPods_CapsAtLimit(t *testing.T) {
+	var pods []k8s.PodMemoryInfo
+	for i := 0; i < maxRecommendationPods+2; i++ {
+		pods = append(pods, k8s.PodMemoryInfo{Namespace: "ns", PodName: "p" + strconv.Itoa(i), CurrentUsage: qty(int64(i+1) * mi)})
+	}
+	top := topUnboundedPods(pods, maxRecommendationPods)
+	if len(top) != maxRecommendationPods {
+		t.Fatalf("expected %d pods, got %d", maxRecommendationPods, len(top))
+	}
+	if top[0].PodName != "p"+strconv.Itoa(maxRecommendationPods+1) {
+		t.Errorf("expected the heaviest pod first, got %s", top[0].PodName)
+	}
+}
+
+func TestPrintJSON_SummaryOnly(t *testing.T) {
+	analysis := &AnalysisResult{Report: MemoryReport{
+		Summary: k8s.MemorySummary{TotalPods: 2, CriticalPods: 1, WarningPodsCount: 1},
+		Pods:    []k8s.PodMemoryInfo{{Namespace: "ns", PodName: "p"}, {Namespace: "ns", PodName: "q"}},
+	}}
+	analysis.addProblem(newPodProblem(SeverityCritical, ProblemKindLimitUsage, "ns", "p", "is using %.1f%% of its memory limit", 95.0))
+	analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoLimit, "ns", "q", "has no memory limit defined"))
+	analysis.addProblem(newPodProblem(SeverityWarning, ProblemKindNoRequest, "ns", "q", "has no memory request defined"))
+
+	out := captureStdout(t, func() { analysis.PrintJSON(&config.Config{Output: config.OutputFormatJSON, SummaryOnly: true}) })
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	for _, absent := range []string{"report", "problems", "pods"} {
+		if _, ok := decoded[absent]; ok {
+			t.Errorf("expected no %q field in summary-only output", absent)
+		}
+	}
+	var doc summaryDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid summary document: %v", err)
+	}
+	if doc.Summary.TotalPods != 2 || doc.Summary.CriticalPods != 1 || doc.CriticalProblems != 1 || doc.WarningProblems != 2 {
+		t.Errorf("unexpected summary document: %+v", doc)
+	}
+}
+
+func TestPrintJSON_IncludesSchemaVersion(t *testing.T) {
+	generated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	analysis := &AnalysisResult{Report: MemoryReport{Summary: k8s.MemorySummary{Timestamp: generated, TotalPods: 1}}}
+
+	out := captureStdout(t, func() { analysis.PrintJSON(&config.Config{Output: config.OutputFormatJSON}) })
+
+	var decoded struct {
+		SchemaVersion string    `json:"schema_version"`
+		GeneratedAt   time.Time `json:"generated_at"`
+		Report        struct {
+			Summary struct {
+				TotalPods int `json:"total_pods"`
+			} `json:"summary"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON output %q: %v", out, err)
+	}
+	if decoded.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("expected schema version %q, got %q", ReportSchemaVersion, decoded.SchemaVersion)
+	}
+	if !decoded.GeneratedAt.Equal(generated) {
+		t.Errorf("expected generated_at %v, got %v", generated, decoded.GeneratedAt)
+	}
+	if decoded.Report.Summary.TotalPods != 1 {
+		t.Errorf("expected the report fields alongside the envelope, got %+v", decoded.Report)
+	}
+}
+
+func TestPodStatusSymbol_CustomSymbols(t *testing.T) {
+	cfg := &config.Config{SymbolOK: "[OK]", SymbolWarning: "[PEND]", SymbolCritical: "[FAIL]", SymbolNoData: "[--]", SymbolCompleted: "[DONE]"}
+
+	tests := []struct {
+		name  string
+		pod   k8s.PodMemoryInfo
+		want  string
+		plain string
+	}{
+		{"ok", k8s.PodMemoryInfo{Phase: "Running", Ready: true, CurrentUsage: qty(mi)}, "[OK]", "🟢"},
+		{"pending", k8s.PodMemoryInfo{Phase: "Pending", CurrentUsage: qty(mi)}, "[PEND]", "🟡"},
+		{"failed", k8s.PodMemoryInfo{Phase: "Failed", CurrentUsage: qty(mi)}, "[FAIL]", "🔴"},
+		{"no data", k8s.PodMemoryInfo{Phase: "Running", Ready: true}, "[--]", "⚪"},
+		{"completed", k8s.PodMemoryInfo{Phase: "Succeeded"}, "[DONE]", "🔵"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podStatusSymbol(&tt.pod, cfg); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if got := podStatusSymbol(&tt.pod, &config.Config{}); got != tt.plain {
+				t.Errorf("expected default %q, got %q", tt.plain, got)
+			}
+		})
+	}
+}
+
+func TestPrintDetailedReport_CustomSymbolsStayAligned(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "api", Phase: "Running", Ready: true, CurrentUsage: qty(200 * mi)},
+		{Namespace: "prod", PodName: "worker", Phase: "Pending", CurrentUsage: qty(100 * mi)},
+	}}
+	cfg := &config.Config{MemoryWarningPercent: 80.0, SymbolOK: "OK", SymbolWarning: "PEND"}
+
+	out := captureStdout(t, func() { report.PrintDetailedReport(cfg) })
+
+	for _, want := range []string{"  OK   prod/api ", "  PEND prod/worker "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if got := formatPodInfo(&report.Pods[1], cfg, false); !strings.HasPrefix(got, "PEND prod/worker") {
+		t.Errorf("expected the custom symbol in the analysis line, got %q", got)
+	}
+}
+
+func TestSymbolWidth(t *testing.T) {
+	for symbol, want := range map[string]int{"": 0, "OK": 2, "🟢": 2, "⚪": 2, "🗑️": 2, "✔": 2, "*": 1} {
+		if got := symbolWidth(symbol); got != want {
+			t.Errorf("symbolWidth(%q) = %d, want %d", symbol, got, want)
+		}
+	}
+}
+
+func TestPrintSummary_ReportsTruncationAndCancellation(t *testing.T) {
+	cfg := &config.Config{}
+	truncated := &MemoryReport{Summary: k8s.MemorySummary{
+		NamespaceCount: 40, Partial: true, Truncated: true, NamespacesCollected: 10,
+	}}
+	out := captureStdout(t, func() { truncated.PrintSummary(cfg) })
+	if !strings.Contains(out, "Truncated Report: only the first 10 of 40 namespaces were collected (--max-namespaces)") {
+		t.Errorf("expected truncation note, got:\n%s", out)
+	}
+	if strings.Contains(out, "Partial Report") {
+		t.Errorf("a truncated report should not be described as cancelled:\n%s", out)
+	}
+
+	cancelled := &MemoryReport{Summary: k8s.MemorySummary{NamespaceCount: 40, Partial: true, NamespacesCollected: 3}}
+	out = captureStdout(t, func() { cancelled.PrintSummary(cfg) })
+	if !strings.Contains(out, "Partial Report: collection cancelled after 3 namespaces") {
+		t.Errorf("expected cancellation note, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeReport_UnschedulablePods(t *testing.T) {
+	unschedulable := func(name string, request *resource.Quantity) k8s.PodMemoryInfo {
+		return k8s.PodMemoryInfo{Namespace: "prod", PodName: name, Phase: "Pending", MemoryRequest: request,
+			NotReadyConditions: []k8s.PodConditionInfo{{Type: "PodScheduled", Reason: "Unschedulable",
+				Message: "0/3 nodes are available: 3 Insufficient memory."}}}
+	}
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		unschedulable("big", qty(8192*mi)),
+		unschedulable("besteffort", nil),
+		{Namespace: "prod", PodName: "waiting", Phase: "Pending", NotReadyConditions: []k8s.PodConditionInfo{{Type: "PodScheduled"}}},
+	}}
+
+	analysis := AnalyzeReport(report, &config.Config{})
+
+	if len(analysis.Unschedulable) != 2 {
+		t.Fatalf("expected 2 unschedulable pods, got %d", len(analysis.Unschedulable))
+	}
+	var found []Problem
+	for _, p := range analysis.Problems {
+		if p.Kind == ProblemKindUnschedulable {
+			found = append(found, p)
+		}
+	}
+	if len(found) != 1 || found[0].PodName != "big" {
+		t.Fatalf("expected one problem for the pod requesting memory, got %+v", found)
+	}
+	if !strings.Contains(found[0].Message, "requesting 8.00 GB of memory: 0/3 nodes are available") {
+		t.Errorf("unexpected message %q", found[0].Message)
+	}
+
+	out := captureStdout(t, func() { analysis.PrintAnalysis(&config.Config{}) })
+	if !strings.Contains(out, "⏳ Unschedulable Pods (2):\n  prod/big | Request: 8.00 GB | 0/3 nodes are available: 3 Insufficient memory.\n") {
+		t.Errorf("expected the unschedulable section, got:\n%s", out)
+	}
+}
+
+func TestPrintDetailedReport_OnlyProblems(t *testing.T) {
+	healthy := func(ns, name string) k8s.PodMemoryInfo {
+		return k8s.PodMemoryInfo{Namespace: ns, PodName: name, Phase: "Running", Ready: true,
+			CurrentUsage: qty(100 * mi), MemoryRequest: qty(200 * mi), MemoryLimit: qty(400 * mi)}
+	}
+	hot := healthy("prod", "hot")
+	hot.CurrentUsage = qty(390 * mi)
+	unbounded := healthy("prod", "unbounded")
+	unbounded.MemoryLimit = nil
+	noData := healthy("prod", "nodata")
+	noData.CurrentUsage = nil
+	report := &MemoryReport{
+		Summary: k8s.MemorySummary{TotalPods: 5, NamespaceCount: 2},
+		Pods: []k8s.PodMemoryInfo{
+			healthy("dev", "calm"),
+			healthy("prod", "api"), hot, noData, unbounded,
+		},
+	}
+	cfg := &config.Config{MemoryWarningPercent: 80.0, OnlyProblems: true}
+
+	out := captureStdout(t, func() { report.PrintDetailedReport(cfg) })
+
+	for _, want := range []string{"Total Pods: 5", "Namespace: prod", "prod/hot", "prod/unbounded"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	for _, absent := range []string{"Namespace: dev", "dev/calm", "prod/api", "prod/nodata"} {
+		if strings.Contains(out, absent) {
+			t.Errorf("did not expect %q in output:\n%s", absent, out)
+		}
+	}
+
+	calm := &MemoryReport{Pods: []k8s.PodMemoryInfo{healthy("dev", "calm")}}
+	out = captureStdout(t, func() { calm.PrintDetailedReport(cfg) })
+	if !strings.Contains(out, "No pods with memory problems.") {
+		t.Errorf("expected a note when every pod is healthy, got:\n%s", out)
+	}
+}
+
+func TestCompactPods_OmitsContainersAndMetadata(t *testing.T) {
+	pod := k8s.PodMemoryInfo{Namespace: "prod", PodName: "api", Phase: "Running", Ready: true, CurrentUsage: qty(200 * mi),
+		Labels:     map[string]string{"team": "core"},
+		Containers: []k8s.ContainerMemoryInfo{{ContainerName: "app", CurrentUsage: qty(150 * mi)}, {ContainerName: "sidecar", CurrentUsage: qty(50 * mi)}}}
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{pod}}
+	cfg := &config.Config{MemoryWarningPercent: 80.0, Labels: []string{"team"}, ContainerStats: true, CompactPods: true}
+
+	out := captureStdout(t, func() { report.PrintDetailedReport(cfg) })
+	if !strings.Contains(out, "prod/api") {
+		t.Fatalf("expected the pod line, got:\n%s", out)
+	}
+	for _, absent := range []string{"Containers:", "- app", "Container stats", "Labels:"} {
+		if strings.Contains(out, absent) {
+			t.Errorf("did not expect %q in compact output:\n%s", absent, out)
+		}
+	}
+
+	if got := formatPodInfo(&pod, cfg, false); strings.Contains(got, "\n") {
+		t.Errorf("expected a single line in the analysis sections, got %q", got)
+	}
+
+	cfg.Output = config.OutputFormatWide
+	if got := report.renderWideTable(report.Pods, cfg); strings.Contains(got, "- app") {
+		t.Errorf("expected no container rows in the compact wide table, got:\n%s", got)
+	}
+}
+
+func TestPrintCycleBanner(t *testing.T) {
+	report := MemoryReport{Cycle: 7, Summary: k8s.MemorySummary{Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}}
+
+	out := captureStdout(t, func() { report.PrintCycleBanner(&config.Config{}) })
+
+	if out != "===== Cycle 7 @ 2024-05-01T12:00:00Z =====\n" {
+		t.Errorf("unexpected banner %q", out)
+	}
+}
+
+func TestFormatPodInfo_Explain(t *testing.T) {
+	pod := &k8s.PodMemoryInfo{
+		PodName: "api", Namespace: "prod", Phase: "Running", Ready: true,
+		CurrentUsage: qty(460 * mi), MemoryRequest: qty(512 * mi), MemoryLimit: qty(500 * mi),
+	}
+
+	output := formatPodInfo(pod, &config.Config{MemoryWarningPercent: 80, CompactPods: true, Explain: true}, false)
+	if !strings.HasSuffix(output, "(critical: limit_usage 92.0% ≥ 90%)") {
+		t.Errorf("expected the deciding rule at the end of the pod line, got %q", output)
+	}
+
+	output = formatPodInfo(pod, &config.Config{MemoryWarningPercent: 80, CompactPods: true}, false)
+	if strings.Contains(output, "critical:") {
+		t.Errorf("expected no explanation without --explain, got %q", output)
+	}
+}
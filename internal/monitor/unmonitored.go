@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+// UnmonitoredNode groups the running pods of one node that have no usage metrics
+type UnmonitoredNode struct {
+	Node        string
+	Pods        []k8s.PodMemoryInfo
+	RunningPods int // Running pods listed on the node, with or without metrics
+}
+
+// AllUnmonitored reports whether none of the node's running pods has metrics, hinting at a broken kubelet scrape
+func (n *UnmonitoredNode) AllUnmonitored() bool {
+	return len(n.Pods) == n.RunningPods
+}
+
+// UnmonitoredPods groups the running pods without usage metrics by node
+// Nodes with the most unmonitored pods come first, then by name; pods are ordered by namespace and name
+func (r *MemoryReport) UnmonitoredPods() []UnmonitoredNode {
+	byNode := make(map[string]*UnmonitoredNode)
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		if pod.Phase != "Running" {
+			continue
+		}
+		node := pod.NodeName
+		if node == "" {
+			node = unscheduledNode
+		}
+		group, ok := byNode[node]
+		if !ok {
+			group = &UnmonitoredNode{Node: node}
+			byNode[node] = group
+		}
+		group.RunningPods++
+		if pod.CurrentUsage == nil {
+			group.Pods = append(group.Pods, *pod)
+		}
+	}
+
+	var nodes []UnmonitoredNode
+	for _, group := range byNode {
+		if len(group.Pods) == 0 {
+			continue
+		}
+		sort.Slice(group.Pods, func(i, j int) bool {
+			if group.Pods[i].Namespace != group.Pods[j].Namespace {
+				return group.Pods[i].Namespace < group.Pods[j].Namespace
+			}
+			return group.Pods[i].PodName < group.Pods[j].PodName
+		})
+		nodes = append(nodes, *group)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if len(nodes[i].Pods) != len(nodes[j].Pods) {
+			return len(nodes[i].Pods) > len(nodes[j].Pods)
+		}
+		return nodes[i].Node < nodes[j].Node
+	})
+	return nodes
+}
+
+// PrintUnmonitoredPods lists the running pods without usage metrics per node, to debug metrics-server scrape gaps
+// Nothing is printed when every running pod has metrics
+func (r *MemoryReport) PrintUnmonitoredPods() {
+	nodes := r.UnmonitoredPods()
+	if len(nodes) == 0 {
+		return
+	}
+
+	total := 0
+	for i := range nodes {
+		total += len(nodes[i].Pods)
+	}
+	fmt.Printf("\n📡 Running Pods Without Metrics (%d):\n", total)
+	for i := range nodes {
+		node := &nodes[i]
+		line := fmt.Sprintf("  Node %s: %d of %d running pods", node.Node, len(node.Pods), node.RunningPods)
+		if node.AllUnmonitored() && node.RunningPods > 1 {
+			line += " (no metrics from this node, check its kubelet)"
+		}
+		fmt.Println(line)
+		for j := range node.Pods {
+			fmt.Printf("    - %s/%s\n", node.Pods[j].Namespace, node.Pods[j].PodName)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/k8s"
+)
+
+func TestUnmonitoredPods_GroupsByNode(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "api", Phase: "Running", NodeName: "node-a", CurrentUsage: qty(100 * mi)},
+		{Namespace: "prod", PodName: "worker", Phase: "Running", NodeName: "node-a"},
+		{Namespace: "prod", PodName: "web", Phase: "Running", NodeName: "node-b"},
+		{Namespace: "dev", PodName: "cache", Phase: "Running", NodeName: "node-b"},
+		{Namespace: "prod", PodName: "job", Phase: "Pending"},
+	}}
+
+	nodes := report.UnmonitoredPods()
+
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes with unmonitored pods, got %+v", nodes)
+	}
+	if nodes[0].Node != "node-b" || len(nodes[0].Pods) != 2 || !nodes[0].AllUnmonitored() {
+		t.Errorf("expected node-b first with all of its pods unmonitored, got %+v", nodes[0])
+	}
+	if nodes[0].Pods[0].PodName != "cache" {
+		t.Errorf("expected pods ordered by namespace, got %s first", nodes[0].Pods[0].PodName)
+	}
+	if nodes[1].Node != "node-a" || len(nodes[1].Pods) != 1 || nodes[1].AllUnmonitored() {
+		t.Errorf("expected node-a with one of two pods unmonitored, got %+v", nodes[1])
+	}
+
+	output := captureStdout(t, func() { report.PrintUnmonitoredPods() })
+	for _, expected := range []string{
+		"Running Pods Without Metrics (3):",
+		"  Node node-b: 2 of 2 running pods (no metrics from this node, check its kubelet)",
+		"    - dev/cache",
+		"  Node node-a: 1 of 2 running pods\n    - prod/worker",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "prod/job") {
+		t.Errorf("expected pending pods to be left out, got:\n%s", output)
+	}
+}
+
+func TestPrintUnmonitoredPods_SilentWhenAllMonitored(t *testing.T) {
+	report := &MemoryReport{Pods: []k8s.PodMemoryInfo{
+		{Namespace: "prod", PodName: "api", Phase: "Running", NodeName: "node-a", CurrentUsage: qty(100 * mi)},
+	}}
+
+	if output := captureStdout(t, func() { report.PrintUnmonitoredPods() }); output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
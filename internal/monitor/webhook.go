@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+// Content types used for webhook payloads
+const (
+	contentTypeJSON      = "application/json"
+	contentTypeJSONLines = "application/x-ndjson"
+)
+
+// WebhookSink posts each analysis to an HTTP endpoint using the JSON output format
+type WebhookSink struct {
+	url        string
+	headers    http.Header
+	httpClient *http.Client
+}
+
+// NewWebhookSink creates a sink for the given URL, extra "Name: value" headers and request timeout
+func NewWebhookSink(url string, headers []string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		headers:    parseHeaders(headers),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the analysis exactly as --output=json would print it
+func (s *WebhookSink) Send(ctx context.Context, analysis *AnalysisResult, cfg *config.Config) error {
+	var body bytes.Buffer
+	newJSONFormatterTo(&body).FormatAnalysis(analysis, cfg)
+
+	contentType := contentTypeJSON
+	if cfg.ProblemsOnly {
+		contentType = contentTypeJSONLines
+	}
+	return postPayload(ctx, s.httpClient, s.url, contentType, s.headers, &body)
+}
+
+// parseHeaders converts "Name: value" entries into an http.Header, ignoring malformed ones
+func parseHeaders(headers []string) http.Header {
+	parsed := http.Header{}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			continue
+		}
+		parsed.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return parsed
+}
+
+// postPayload sends a POST request and treats any non-2xx response as an error
+func postPayload(ctx context.Context, client *http.Client, url, contentType string, headers http.Header, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
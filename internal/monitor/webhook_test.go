@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardoferro/k8s-memory-watch/internal/config"
+)
+
+func TestWebhookSink_PostsAnalysisJSON(t *testing.T) {
+	var gotBody []byte
+	var gotHeader, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header.Get("Authorization")
+		gotContentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, []string{"Authorization: Bearer secret"}, time.Second)
+	cfg := &config.Config{Output: config.OutputFormatJSON}
+	if err := sink.Send(context.Background(), criticalAnalysis("api"), cfg); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	if gotHeader != "Bearer secret" {
+		t.Errorf("expected auth header to be forwarded, got %q", gotHeader)
+	}
+	if gotContentType != contentTypeJSON {
+		t.Errorf("expected content type %q, got %q", contentTypeJSON, gotContentType)
+	}
+	var decoded AnalysisResult
+	if err := json.Unmarshal(gotBody, &decoded); err != nil {
+		t.Fatalf("payload is not an analysis document: %v", err)
+	}
+	if len(decoded.Problems) != 2 {
+		t.Errorf("expected 2 problems in payload, got %d", len(decoded.Problems))
+	}
+}
+
+func TestWebhookSink_ProblemsOnlyUsesJSONLines(t *testing.T) {
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, nil, time.Second)
+	cfg := &config.Config{Output: config.OutputFormatJSON, ProblemsOnly: true}
+	if err := sink.Send(context.Background(), criticalAnalysis("api", "worker"), cfg); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(gotBody)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per problem, got %d: %s", len(lines), gotBody)
+	}
+}
+
+func TestWebhookSink_NonSuccessStatusIsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, nil, time.Second)
+	err := sink.Send(context.Background(), criticalAnalysis("api"), &config.Config{Output: config.OutputFormatJSON})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected status 401 error, got %v", err)
+	}
+}